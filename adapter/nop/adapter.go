@@ -2,7 +2,10 @@ package nopadapter
 
 import (
 	"fmt"
+	"math/big"
 	"net"
+	"net/netip"
+	"net/url"
 	"time"
 
 	"github.com/nikoksr/onelog"
@@ -11,71 +14,101 @@ import (
 // Compile-time check that Adapter and Context implements onelog.Logger and onelog.LoggerContext respectively
 var (
 	_ onelog.Logger        = (*Adapter)(nil)
-	_ onelog.LoggerContext = (*Context)(nil)
+	_ onelog.LoggerContext = Context{}
+	_ onelog.FatalWriter   = (*Adapter)(nil)
+	_ onelog.Template      = Template{}
 )
 
 type (
 	Adapter struct{}
 
-	Context struct{}
+	// Context discards all fields. Contexts of fatal records still run onelog.Exit once sent, like those of any other
+	// Logger.
+	Context struct {
+		fatal bool
+	}
+
+	Template struct {
+		fatal bool
+	}
 )
 
 // NewAdapter returns a new adapter. The nop adapter does not log anything and can be used as a placeholder or fallback.
 func NewAdapter() onelog.Logger { return &Adapter{} }
 
 func (a *Adapter) With(_ ...any) onelog.Logger { return a }
-func (a *Adapter) Debug() onelog.LoggerContext { return &Context{} }
-func (a *Adapter) Info() onelog.LoggerContext  { return &Context{} }
-func (a *Adapter) Warn() onelog.LoggerContext  { return &Context{} }
-func (a *Adapter) Error() onelog.LoggerContext { return &Context{} }
-func (a *Adapter) Fatal() onelog.LoggerContext { return &Context{} }
+func (a *Adapter) Debug() onelog.LoggerContext { return Context{} }
+func (a *Adapter) Info() onelog.LoggerContext  { return Context{} }
+func (a *Adapter) Warn() onelog.LoggerContext  { return Context{} }
+func (a *Adapter) Error() onelog.LoggerContext { return Context{} }
+func (a *Adapter) Fatal() onelog.LoggerContext { return Context{fatal: true} }
+
+func (a *Adapter) FatalNoExit() onelog.LoggerContext { return Context{} }
+
+func (c Context) Bytes(_ string, _ []byte) onelog.LoggerContext                    { return c }
+func (c Context) Hex(_ string, _ []byte) onelog.LoggerContext                      { return c }
+func (c Context) RawJSON(_ string, _ []byte) onelog.LoggerContext                  { return c }
+func (c Context) Str(_, _ string) onelog.LoggerContext                             { return c }
+func (c Context) Strs(_ string, _ []string) onelog.LoggerContext                   { return c }
+func (c Context) Stringer(_ string, _ fmt.Stringer) onelog.LoggerContext           { return c }
+func (c Context) Stringers(_ string, _ []fmt.Stringer) onelog.LoggerContext        { return c }
+func (c Context) Int(_ string, _ int) onelog.LoggerContext                         { return c }
+func (c Context) Ints(_ string, _ []int) onelog.LoggerContext                      { return c }
+func (c Context) Int8(_ string, _ int8) onelog.LoggerContext                       { return c }
+func (c Context) Ints8(_ string, _ []int8) onelog.LoggerContext                    { return c }
+func (c Context) Int16(_ string, _ int16) onelog.LoggerContext                     { return c }
+func (c Context) Ints16(_ string, _ []int16) onelog.LoggerContext                  { return c }
+func (c Context) Int32(_ string, _ int32) onelog.LoggerContext                     { return c }
+func (c Context) Ints32(_ string, _ []int32) onelog.LoggerContext                  { return c }
+func (c Context) Int64(_ string, _ int64) onelog.LoggerContext                     { return c }
+func (c Context) Ints64(_ string, _ []int64) onelog.LoggerContext                  { return c }
+func (c Context) Uint(_ string, _ uint) onelog.LoggerContext                       { return c }
+func (c Context) Uints(_ string, _ []uint) onelog.LoggerContext                    { return c }
+func (c Context) Uint8(_ string, _ uint8) onelog.LoggerContext                     { return c }
+func (c Context) Uints8(_ string, _ []uint8) onelog.LoggerContext                  { return c }
+func (c Context) Uint16(_ string, _ uint16) onelog.LoggerContext                   { return c }
+func (c Context) Uints16(_ string, _ []uint16) onelog.LoggerContext                { return c }
+func (c Context) Uint32(_ string, _ uint32) onelog.LoggerContext                   { return c }
+func (c Context) Uints32(_ string, _ []uint32) onelog.LoggerContext                { return c }
+func (c Context) Uint64(_ string, _ uint64) onelog.LoggerContext                   { return c }
+func (c Context) Uints64(_ string, _ []uint64) onelog.LoggerContext                { return c }
+func (c Context) Float32(_ string, _ float32) onelog.LoggerContext                 { return c }
+func (c Context) Floats32(_ string, _ []float32) onelog.LoggerContext              { return c }
+func (c Context) Float64(_ string, _ float64) onelog.LoggerContext                 { return c }
+func (c Context) Floats64(_ string, _ []float64) onelog.LoggerContext              { return c }
+func (c Context) Bool(_ string, _ bool) onelog.LoggerContext                       { return c }
+func (c Context) Bools(_ string, _ []bool) onelog.LoggerContext                    { return c }
+func (c Context) Time(_ string, _ time.Time) onelog.LoggerContext                  { return c }
+func (c Context) Times(_ string, _ []time.Time) onelog.LoggerContext               { return c }
+func (c Context) Dur(_ string, _ time.Duration) onelog.LoggerContext               { return c }
+func (c Context) Durs(_ string, _ []time.Duration) onelog.LoggerContext            { return c }
+func (c Context) TimeDiff(_ string, _ time.Time, _ time.Time) onelog.LoggerContext { return c }
+func (c Context) IPAddr(_ string, _ net.IP) onelog.LoggerContext                   { return c }
+func (c Context) IPPrefix(_ string, _ net.IPNet) onelog.LoggerContext              { return c }
+func (c Context) MACAddr(_ string, _ net.HardwareAddr) onelog.LoggerContext        { return c }
+func (c Context) Addr(_ string, _ netip.Addr) onelog.LoggerContext                 { return c }
+func (c Context) Prefix(_ string, _ netip.Prefix) onelog.LoggerContext             { return c }
+func (c Context) AddrPort(_ string, _ netip.AddrPort) onelog.LoggerContext         { return c }
+func (c Context) URL(_ string, _ *url.URL) onelog.LoggerContext                    { return c }
+func (c Context) BigInt(_ string, _ *big.Int) onelog.LoggerContext                 { return c }
+func (c Context) BigFloat(_ string, _ *big.Float) onelog.LoggerContext             { return c }
+func (c Context) Decimal(_ string, _ fmt.Stringer) onelog.LoggerContext            { return c }
+func (c Context) Err(_ error) onelog.LoggerContext                                 { return c }
+func (c Context) Errs(_ string, _ []error) onelog.LoggerContext                    { return c }
+func (c Context) AnErr(_ string, _ error) onelog.LoggerContext                     { return c }
+func (c Context) Any(_ string, _ any) onelog.LoggerContext                         { return c }
+func (c Context) Fields(_ onelog.Fields) onelog.LoggerContext                      { return c }
+func (c Context) Template() onelog.Template                                        { return Template(c) }
+
+func (c Context) Msgf(_ string, _ ...any) { c.Msg("") }
+func (c Context) Send()                   { c.Msg("") }
+func (c Context) Discard()                {}
 
-func (c *Context) Bytes(_ string, _ []byte) onelog.LoggerContext                    { return c }
-func (c *Context) Hex(_ string, _ []byte) onelog.LoggerContext                      { return c }
-func (c *Context) RawJSON(_ string, _ []byte) onelog.LoggerContext                  { return c }
-func (c *Context) Str(_, _ string) onelog.LoggerContext                             { return c }
-func (c *Context) Strs(_ string, _ []string) onelog.LoggerContext                   { return c }
-func (c *Context) Stringer(_ string, _ fmt.Stringer) onelog.LoggerContext           { return c }
-func (c *Context) Stringers(_ string, _ []fmt.Stringer) onelog.LoggerContext        { return c }
-func (c *Context) Int(_ string, _ int) onelog.LoggerContext                         { return c }
-func (c *Context) Ints(_ string, _ []int) onelog.LoggerContext                      { return c }
-func (c *Context) Int8(_ string, _ int8) onelog.LoggerContext                       { return c }
-func (c *Context) Ints8(_ string, _ []int8) onelog.LoggerContext                    { return c }
-func (c *Context) Int16(_ string, _ int16) onelog.LoggerContext                     { return c }
-func (c *Context) Ints16(_ string, _ []int16) onelog.LoggerContext                  { return c }
-func (c *Context) Int32(_ string, _ int32) onelog.LoggerContext                     { return c }
-func (c *Context) Ints32(_ string, _ []int32) onelog.LoggerContext                  { return c }
-func (c *Context) Int64(_ string, _ int64) onelog.LoggerContext                     { return c }
-func (c *Context) Ints64(_ string, _ []int64) onelog.LoggerContext                  { return c }
-func (c *Context) Uint(_ string, _ uint) onelog.LoggerContext                       { return c }
-func (c *Context) Uints(_ string, _ []uint) onelog.LoggerContext                    { return c }
-func (c *Context) Uint8(_ string, _ uint8) onelog.LoggerContext                     { return c }
-func (c *Context) Uints8(_ string, _ []uint8) onelog.LoggerContext                  { return c }
-func (c *Context) Uint16(_ string, _ uint16) onelog.LoggerContext                   { return c }
-func (c *Context) Uints16(_ string, _ []uint16) onelog.LoggerContext                { return c }
-func (c *Context) Uint32(_ string, _ uint32) onelog.LoggerContext                   { return c }
-func (c *Context) Uints32(_ string, _ []uint32) onelog.LoggerContext                { return c }
-func (c *Context) Uint64(_ string, _ uint64) onelog.LoggerContext                   { return c }
-func (c *Context) Uints64(_ string, _ []uint64) onelog.LoggerContext                { return c }
-func (c *Context) Float32(_ string, _ float32) onelog.LoggerContext                 { return c }
-func (c *Context) Floats32(_ string, _ []float32) onelog.LoggerContext              { return c }
-func (c *Context) Float64(_ string, _ float64) onelog.LoggerContext                 { return c }
-func (c *Context) Floats64(_ string, _ []float64) onelog.LoggerContext              { return c }
-func (c *Context) Bool(_ string, _ bool) onelog.LoggerContext                       { return c }
-func (c *Context) Bools(_ string, _ []bool) onelog.LoggerContext                    { return c }
-func (c *Context) Time(_ string, _ time.Time) onelog.LoggerContext                  { return c }
-func (c *Context) Times(_ string, _ []time.Time) onelog.LoggerContext               { return c }
-func (c *Context) Dur(_ string, _ time.Duration) onelog.LoggerContext               { return c }
-func (c *Context) Durs(_ string, _ []time.Duration) onelog.LoggerContext            { return c }
-func (c *Context) TimeDiff(_ string, _ time.Time, _ time.Time) onelog.LoggerContext { return c }
-func (c *Context) IPAddr(_ string, _ net.IP) onelog.LoggerContext                   { return c }
-func (c *Context) IPPrefix(_ string, _ net.IPNet) onelog.LoggerContext              { return c }
-func (c *Context) MACAddr(_ string, _ net.HardwareAddr) onelog.LoggerContext        { return c }
-func (c *Context) Err(_ error) onelog.LoggerContext                                 { return c }
-func (c *Context) Errs(_ string, _ []error) onelog.LoggerContext                    { return c }
-func (c *Context) AnErr(_ string, _ error) onelog.LoggerContext                     { return c }
-func (c *Context) Any(_ string, _ any) onelog.LoggerContext                         { return c }
-func (c *Context) Fields(_ onelog.Fields) onelog.LoggerContext                      { return c }
+// Msg discards the record. For fatal records, onelog.Exit runs afterwards.
+func (c Context) Msg(_ string) {
+	if c.fatal {
+		onelog.Exit(nil)
+	}
+}
 
-func (c *Context) Msg(_ string)            {}
-func (c *Context) Msgf(_ string, _ ...any) {}
+func (t Template) Context() onelog.LoggerContext { return Context(t) }
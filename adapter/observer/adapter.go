@@ -0,0 +1,179 @@
+package observeradapter
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nikoksr/onelog"
+	"github.com/nikoksr/onelog/internal/keys"
+	"github.com/nikoksr/onelog/internal/record"
+)
+
+// Compile-time check that Adapter implements onelog.Logger and onelog.FatalWriter
+var (
+	_ onelog.Logger      = (*Adapter)(nil)
+	_ onelog.FatalWriter = (*Adapter)(nil)
+)
+
+type (
+	// Adapter is an observer adapter for onelog. It implements the onelog.Logger interface and records all entries to
+	// its Logs. Fatal entries are recorded like any other before the process is terminated; use WithExitFunc or
+	// FatalNoExit to keep it running. Its contexts are not safe for concurrent use, while their templates are; see
+	// onelog.LoggerContext.Template.
+	Adapter struct {
+		logs   *Logs
+		fields []Field // Fields added using With
+		exit   onelog.ExitFunc
+	}
+
+	// Option configures an Adapter.
+	Option func(*Adapter)
+
+	// Field is a recorded field.
+	Field struct {
+		// Key is the key of the field.
+		Key string
+		// Value is the value as passed to the LoggerContext method, e.g. an int8 for Int8, a []string for Strs or an
+		// error for Err. TimeDiff fields hold the time.Duration between both times.
+		Value any
+	}
+
+	// Entry is a recorded log entry.
+	Entry struct {
+		// Time is the time the entry was sent.
+		Time time.Time
+		// Level is the level of the entry.
+		Level onelog.Level
+		// Message is the message of the entry.
+		Message string
+		// Fields are the fields added using With, followed by the fields of the entry, in the order they were added.
+		// Fields added via LoggerContext.Fields are flattened into single fields.
+		Fields []Field
+	}
+)
+
+// WithExitFunc sets the function used to terminate the process after fatal records. By default, the process-wide exit
+// function of onelog is used; see onelog.SetExitFunc.
+func WithExitFunc(fn onelog.ExitFunc) Option {
+	return func(a *Adapter) {
+		a.exit = fn
+	}
+}
+
+// NewAdapter returns a new observer adapter and the logs it records to. Loggers derived using With record to the same
+// logs.
+func NewAdapter(opts ...Option) (onelog.Logger, *Logs) {
+	a := &Adapter{logs: new(Logs)}
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	return a, a.logs
+}
+
+func (a *Adapter) newContext(level onelog.Level, fatal bool) onelog.LoggerContext {
+	return record.NewContext(func(msg string, fields []record.Field) {
+		entry := Entry{
+			Time:    time.Now(),
+			Level:   level,
+			Message: msg,
+			Fields:  make([]Field, 0, len(a.fields)+len(fields)),
+		}
+		entry.Fields = append(entry.Fields, a.fields...)
+
+		for i, f := range record.Snapshot(fields) {
+			switch fields[i].Kind {
+			case record.KindStringer, record.KindDecimal:
+				// Keep the fmt.Stringer rather than the string the snapshot holds, to preserve its type
+				entry.Fields = append(entry.Fields, Field{Key: f.Key, Value: fields[i].Value})
+			case record.KindStringers:
+				stringers, _ := fields[i].Value.([]fmt.Stringer)
+				entry.Fields = append(entry.Fields, Field{Key: f.Key, Value: append([]fmt.Stringer(nil), stringers...)})
+			case record.KindFields:
+				m, _ := f.Value.(onelog.Fields)
+				for _, key := range keys.Sorted(m) {
+					entry.Fields = append(entry.Fields, Field{Key: key, Value: m[key]})
+				}
+			case record.KindTimeDiff:
+				t, _ := f.Value.(time.Time)
+				entry.Fields = append(entry.Fields, Field{Key: f.Key, Value: t.Sub(f.Start)})
+			default:
+				entry.Fields = append(entry.Fields, Field{Key: f.Key, Value: f.Value})
+			}
+		}
+
+		a.logs.add(entry)
+
+		if fatal {
+			onelog.Exit(a.exit)
+		}
+	})
+}
+
+// With returns the logger with the given fields.
+func (a *Adapter) With(fields ...any) onelog.Logger {
+	withFields := make([]Field, len(a.fields), len(a.fields)+len(fields)/2)
+	copy(withFields, a.fields)
+
+	for i := 0; i+1 < len(fields); i += 2 {
+		key, ok := fields[i].(string)
+		if !ok {
+			continue
+		}
+		withFields = append(withFields, Field{Key: key, Value: fields[i+1]})
+	}
+
+	return &Adapter{logs: a.logs, fields: withFields, exit: a.exit}
+}
+
+// Debug returns a LoggerContext for a debug log. To send the log, use the Msg or Msgf methods.
+func (a *Adapter) Debug() onelog.LoggerContext {
+	return a.newContext(onelog.DebugLevel, false)
+}
+
+// Info returns a LoggerContext for an info log. To send the log, use the Msg or Msgf methods.
+func (a *Adapter) Info() onelog.LoggerContext {
+	return a.newContext(onelog.InfoLevel, false)
+}
+
+// Warn returns a LoggerContext for a warn log. To send the log, use the Msg or Msgf methods.
+func (a *Adapter) Warn() onelog.LoggerContext {
+	return a.newContext(onelog.WarnLevel, false)
+}
+
+// Error returns a LoggerContext for an error log. To send the log, use the Msg or Msgf methods.
+func (a *Adapter) Error() onelog.LoggerContext {
+	return a.newContext(onelog.ErrorLevel, false)
+}
+
+// Fatal returns a LoggerContext for a fatal log. To send the log, use the Msg or Msgf methods. Once the entry is
+// recorded, the exit hooks run and the process is terminated; see onelog.Exit.
+func (a *Adapter) Fatal() onelog.LoggerContext {
+	return a.newContext(onelog.FatalLevel, true)
+}
+
+// FatalNoExit returns a LoggerContext for a fatal log that does not exit once sent.
+func (a *Adapter) FatalNoExit() onelog.LoggerContext {
+	return a.newContext(onelog.FatalLevel, false)
+}
+
+// Field returns the value of the last field with the given key. The returned bool reports whether the field exists.
+func (e Entry) Field(key string) (any, bool) {
+	for i := len(e.Fields) - 1; i >= 0; i-- {
+		if e.Fields[i].Key == key {
+			return e.Fields[i].Value, true
+		}
+	}
+
+	return nil, false
+}
+
+// FieldMap returns the fields as a map. If a key was added more than once, the last value wins.
+func (e Entry) FieldMap() map[string]any {
+	m := make(map[string]any, len(e.Fields))
+	for _, f := range e.Fields {
+		m[f.Key] = f.Value
+	}
+
+	return m
+}
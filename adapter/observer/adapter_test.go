@@ -0,0 +1,169 @@
+package observeradapter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikoksr/onelog"
+	"github.com/nikoksr/onelog/onelogtest"
+)
+
+// TestEntries tests if entries are recorded with level, message and typed fields, including the ones added using With.
+func TestEntries(t *testing.T) {
+	t.Parallel()
+
+	logger, logs := NewAdapter()
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	err := errors.New("test error")
+	values := []int{1, 2, 3}
+
+	logger.With("test-with", "test").With("request", 42).Warn().
+		Int8("int8", 8).
+		Ints("ints", values).
+		Dur("dur", time.Second).
+		TimeDiff("diff", start.Add(time.Minute), start).
+		Err(err).
+		Stringer("stringer", bytes.NewBufferString("value")).
+		Fields(onelog.Fields{"b": 2, "a": 1}).
+		Msgf("test %d", 1)
+	values[0] = 42
+
+	entries := logs.All()
+	require.Len(t, entries, 1)
+
+	entry := entries[0]
+	assert.Equal(t, onelog.WarnLevel, entry.Level)
+	assert.Equal(t, "test 1", entry.Message)
+	assert.WithinDuration(t, time.Now(), entry.Time, time.Minute)
+	assert.Equal(t, []Field{
+		{Key: "test-with", Value: "test"},
+		{Key: "request", Value: 42},
+		{Key: "int8", Value: int8(8)},
+		{Key: "ints", Value: []int{1, 2, 3}},
+		{Key: "dur", Value: time.Second},
+		{Key: "diff", Value: time.Minute},
+		{Key: "error", Value: err},
+		{Key: "stringer", Value: bytes.NewBufferString("value")},
+		{Key: "a", Value: 1},
+		{Key: "b", Value: 2},
+	}, entry.Fields, "fields should keep their Go types and the values they had when the entry was sent")
+
+	value, ok := entry.Field("int8")
+	assert.True(t, ok)
+	assert.IsType(t, int8(0), value)
+	assert.Equal(t, 42, entry.FieldMap()["request"])
+}
+
+// TestFatal tests if the adapter passes the fatal conformance suite. The parser takes the recorded entries instead of
+// parsing the output, which the adapter does not write.
+func TestFatal(t *testing.T) {
+	t.Parallel()
+
+	var logs *Logs
+	onelogtest.RunFatal(t, onelogtest.Config{
+		NewFatalLogger: func(_ io.Writer, exit onelog.ExitFunc) onelog.Logger {
+			var logger onelog.Logger
+			logger, logs = NewAdapter(WithExitFunc(exit))
+			return logger
+		},
+		Parser: onelogtest.ParserFunc(func([]byte) ([]onelogtest.Record, error) {
+			var records []onelogtest.Record
+			for _, entry := range logs.TakeAll() {
+				record := onelogtest.Record(entry.FieldMap())
+				record[onelogtest.DefaultMessageKey] = entry.Message
+				record[onelogtest.DefaultLevelKey] = entry.Level.String()
+				records = append(records, record)
+			}
+			return records, nil
+		}),
+	})
+}
+
+// TestFilters tests the query helpers.
+func TestFilters(t *testing.T) {
+	t.Parallel()
+
+	logger, logs := NewAdapter(WithExitFunc(func(int) {}))
+	logger.Debug().Str("user", "bob").Msg("login")
+	logger.Info().Str("user", "alice").Msg("login")
+	logger.Info().Int("status", 500).Msg("request failed")
+	fatalContext, _ := onelog.FatalNoExit(logger)
+	fatalContext.Msg("shutdown")
+	logger.Fatal().Msg("shutdown")
+
+	assert.Equal(t, 2, logs.FilterLevel(onelog.InfoLevel).Len())
+	assert.Equal(t, 2, logs.FilterMessage("login").Len())
+	assert.Equal(t, 1, logs.FilterMessageSnippet("failed").Len())
+	assert.Equal(t, 1, logs.FilterField("user", "alice").Len())
+	assert.Equal(t, 0, logs.FilterField("status", int64(500)).Len(), "values should be compared with their types")
+	assert.Equal(t, 2, logs.FilterFieldKey("user").Len())
+	assert.Equal(t, 1, logs.FilterLevel(onelog.DebugLevel).FilterField("user", "bob").Len(), "filters should chain")
+	assert.Equal(t, 2, logs.FilterLevel(onelog.FatalLevel).Len(), "fatal entries should be recorded")
+
+	taken := logs.TakeAll()
+	assert.Len(t, taken, 5)
+	assert.Zero(t, logs.Len(), "taken entries should be removed")
+}
+
+// fakeT records failed assertions.
+type fakeT struct {
+	errors []string
+}
+
+func (f *fakeT) Errorf(format string, args ...any) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+// TestAssertions tests the assertion helpers.
+func TestAssertions(t *testing.T) {
+	t.Parallel()
+
+	logger, logs := NewAdapter()
+	logger.Error().Int("status", 500).Msg("request failed")
+
+	assert.True(t, logs.AssertLogged(t, onelog.ErrorLevel, "request failed"))
+	assert.True(t, logs.AssertNotLogged(t, onelog.InfoLevel, "request failed"))
+	assert.True(t, logs.AssertField(t, "status", 500))
+	assert.True(t, logs.AssertLen(t, 1))
+
+	failing := new(fakeT)
+	assert.False(t, logs.AssertLogged(failing, onelog.InfoLevel, "request failed"))
+	assert.False(t, logs.AssertNotLogged(failing, onelog.ErrorLevel, "request failed"))
+	assert.False(t, logs.AssertField(failing, "status", "500"))
+	assert.False(t, logs.AssertLen(failing, 2))
+
+	require.Len(t, failing.errors, 4)
+	assert.Contains(t, failing.errors[0], `error "request failed" status=500`, "failures should list the entries")
+}
+
+// TestConcurrent tests if loggers sharing the same logs can be used concurrently.
+func TestConcurrent(t *testing.T) {
+	t.Parallel()
+
+	logger, logs := NewAdapter()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			derived := logger.With("goroutine", i)
+			for j := 0; j < 50; j++ {
+				derived.Info().Int("j", j).Msg("test")
+				_ = logs.FilterField("goroutine", i).Len()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 400, logs.Len())
+	assert.Equal(t, 50, logs.FilterField("goroutine", 3).Len())
+}
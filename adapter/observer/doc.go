@@ -0,0 +1,3 @@
+// Package observeradapter provides an in-memory adapter for onelog that records every entry, so tests can assert on
+// logs without parsing any output. Field values keep the Go types they were logged with.
+package observeradapter
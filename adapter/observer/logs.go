@@ -0,0 +1,176 @@
+package observeradapter
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nikoksr/onelog"
+)
+
+// Logs is a concurrency-safe collection of recorded entries. The Filter methods return new, independent collections.
+type Logs struct {
+	mu      sync.RWMutex
+	entries []Entry
+}
+
+func (l *Logs) add(entry Entry) {
+	l.mu.Lock()
+	l.entries = append(l.entries, entry)
+	l.mu.Unlock()
+}
+
+// Len returns the number of entries.
+func (l *Logs) Len() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return len(l.entries)
+}
+
+// All returns a copy of all entries.
+func (l *Logs) All() []Entry {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	entries := make([]Entry, len(l.entries))
+	copy(entries, l.entries)
+
+	return entries
+}
+
+// TakeAll returns all entries and removes them from the collection.
+func (l *Logs) TakeAll() []Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entries := l.entries
+	l.entries = nil
+
+	return entries
+}
+
+// Filter returns the entries for which keep returns true.
+func (l *Logs) Filter(keep func(Entry) bool) *Logs {
+	filtered := new(Logs)
+	for _, entry := range l.All() {
+		if keep(entry) {
+			filtered.entries = append(filtered.entries, entry)
+		}
+	}
+
+	return filtered
+}
+
+// FilterLevel returns the entries with the given level.
+func (l *Logs) FilterLevel(level onelog.Level) *Logs {
+	return l.Filter(func(e Entry) bool {
+		return e.Level == level
+	})
+}
+
+// FilterMessage returns the entries with the given message.
+func (l *Logs) FilterMessage(msg string) *Logs {
+	return l.Filter(func(e Entry) bool {
+		return e.Message == msg
+	})
+}
+
+// FilterMessageSnippet returns the entries whose message contains snippet.
+func (l *Logs) FilterMessageSnippet(snippet string) *Logs {
+	return l.Filter(func(e Entry) bool {
+		return strings.Contains(e.Message, snippet)
+	})
+}
+
+// FilterField returns the entries with a field of the given key and value. Values are compared using
+// reflect.DeepEqual, so their types have to match, e.g. Int8 fields only match int8 values.
+func (l *Logs) FilterField(key string, value any) *Logs {
+	return l.Filter(func(e Entry) bool {
+		for _, f := range e.Fields {
+			if f.Key == key && reflect.DeepEqual(f.Value, value) {
+				return true
+			}
+		}
+
+		return false
+	})
+}
+
+// FilterFieldKey returns the entries with a field of the given key.
+func (l *Logs) FilterFieldKey(key string) *Logs {
+	return l.Filter(func(e Entry) bool {
+		_, ok := e.Field(key)
+		return ok
+	})
+}
+
+// AssertLogged asserts that at least one entry with the given level and message was recorded.
+func (l *Logs) AssertLogged(t assert.TestingT, level onelog.Level, msg string, msgAndArgs ...any) bool {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+
+	if l.FilterLevel(level).FilterMessage(msg).Len() > 0 {
+		return true
+	}
+
+	return assert.Fail(t, fmt.Sprintf("No %s entry with message %q was logged. Entries:\n%s", level, msg, l), msgAndArgs...)
+}
+
+// AssertNotLogged asserts that no entry with the given level and message was recorded.
+func (l *Logs) AssertNotLogged(t assert.TestingT, level onelog.Level, msg string, msgAndArgs ...any) bool {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+
+	if l.FilterLevel(level).FilterMessage(msg).Len() == 0 {
+		return true
+	}
+
+	return assert.Fail(t, fmt.Sprintf("Unexpected %s entry with message %q was logged", level, msg), msgAndArgs...)
+}
+
+// AssertField asserts that at least one entry with a field of the given key and value was recorded. Values are compared
+// like in FilterField.
+func (l *Logs) AssertField(t assert.TestingT, key string, value any, msgAndArgs ...any) bool {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+
+	if l.FilterField(key, value).Len() > 0 {
+		return true
+	}
+
+	return assert.Fail(t, fmt.Sprintf("No entry with field %s=%#v was logged. Entries:\n%s", key, value, l), msgAndArgs...)
+}
+
+// AssertLen asserts that the given number of entries was recorded.
+func (l *Logs) AssertLen(t assert.TestingT, n int, msgAndArgs ...any) bool {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+
+	if l.Len() == n {
+		return true
+	}
+
+	return assert.Fail(t, fmt.Sprintf("Expected %d entries, but %d were logged. Entries:\n%s", n, l.Len(), l), msgAndArgs...)
+}
+
+// String returns a human-readable listing of all entries, one per line.
+func (l *Logs) String() string {
+	var b strings.Builder
+	for _, entry := range l.All() {
+		fmt.Fprintf(&b, "\t%s %q", entry.Level, entry.Message)
+		for _, f := range entry.Fields {
+			fmt.Fprintf(&b, " %s=%#v", f.Key, f.Value)
+		}
+		b.WriteByte('\n')
+	}
+
+	return b.String()
+}
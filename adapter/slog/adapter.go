@@ -1,55 +1,167 @@
 package slogadapter
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"math/big"
 	"net"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"sync"
 	"time"
 
-	"github.com/shopspring/decimal"
-
 	"golang.org/x/exp/slog"
 
 	"github.com/nikoksr/onelog"
+	nopadapter "github.com/nikoksr/onelog/adapter/nop"
+	"github.com/nikoksr/onelog/internal/keys"
+	"github.com/nikoksr/onelog/internal/nilsafe"
+	"github.com/nikoksr/onelog/internal/numeric"
+	"github.com/nikoksr/onelog/internal/urlsafe"
 )
 
 // Compile-time check that Adapter and Context implements onelog.Logger and onelog.LoggerContext respectively
 var (
 	_ onelog.Logger        = (*Adapter)(nil)
 	_ onelog.LoggerContext = (*Context)(nil)
+	_ onelog.Template      = (*Template)(nil)
+	_ onelog.FatalWriter   = (*Adapter)(nil)
 )
 
 type (
 	// Adapter is a slog adapter for onelog. It implements the onelog.Logger interface.
 	Adapter struct {
 		logger *slog.Logger
+		exit   onelog.ExitFunc
+		enc    onelog.Encoding
+		noPool bool
 	}
 
-	// Context is the slog logging context. It implements the onelog.LoggerContext interface.
+	// Context is the slog logging context. It implements the onelog.LoggerContext interface. A Context is not safe for
+	// concurrent use; use Template to share its fields between goroutines. Unless WithoutPooling is set, it is returned
+	// to a pool once sent or discarded and must not be used afterwards.
 	Context struct {
 		level  slog.Level
 		logger *slog.Logger
-		fields []any
+		fields []slog.Attr
+		fatal  bool
+		exit   onelog.ExitFunc
+		enc    onelog.Encoding
+		noPool bool
+	}
+
+	// Template is an immutable snapshot of a Context. Its fields are handed to the handler using slog.Handler.WithAttrs
+	// once the template is taken, so it is as safe for concurrent use as the handler is. It implements the onelog.Template
+	// interface.
+	Template struct {
+		level  slog.Level
+		logger *slog.Logger
+		fatal  bool
+		exit   onelog.ExitFunc
+		enc    onelog.Encoding
+		noPool bool
 	}
+
+	// Option configures an Adapter.
+	Option func(*Adapter)
 )
 
+// WithExitFunc sets the function used to terminate the process after fatal records. By default, the process-wide exit
+// function of onelog is used; see onelog.SetExitFunc.
+func WithExitFunc(fn onelog.ExitFunc) Option {
+	return func(a *Adapter) {
+		a.exit = fn
+	}
+}
+
+// WithEncoding sets the policy for rendering times, durations, bytes and errors. By default, the zero
+// onelog.Encoding is used.
+func WithEncoding(enc onelog.Encoding) Option {
+	return func(a *Adapter) {
+		a.enc = enc
+	}
+}
+
+// WithoutPooling makes the adapter allocate a new context for every record instead of taking it from a pool.
+//
+// By default, contexts and their field buffers are taken from a pool and returned to it once the record is sent using
+// Msg, Msgf or Send, or abandoned using Discard, so that writing a record with typed fields allocates no more than the
+// handler does in the steady state. A pooled context must not be used after it has been returned; take a Template of it
+// instead to emit many records with the same fields. Without pooling, contexts may be reused after they are sent.
+func WithoutPooling() Option {
+	return func(a *Adapter) {
+		a.noPool = true
+	}
+}
+
 // NewAdapter creates a new slog adapter for onelog.
-func NewAdapter(l *slog.Logger) onelog.Logger {
-	return &Adapter{
+func NewAdapter(l *slog.Logger, opts ...Option) onelog.Logger {
+	a := &Adapter{
 		logger: l,
 	}
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	return a
+}
+
+const (
+	// defaultFields is the initial capacity of the field buffers of contexts, which saves growing them repeatedly
+	// for typical records.
+	defaultFields = 16
+
+	// maxPooledFields is the capacity above which the field buffer of a context is dropped before the context is
+	// returned to the pool, so that a single large record does not pin its buffer for the lifetime of the pool.
+	maxPooledFields = 128
+)
+
+// contextPool holds the contexts of sent records for reuse.
+var contextPool = sync.Pool{
+	New: func() any { return new(Context) },
+}
+
+// newContext returns a context for records of the given level, or a nop context if the handler does not handle the
+// level, so that records of disabled levels do not allocate.
+func (a *Adapter) newContext(level slog.Level) onelog.LoggerContext {
+	if !a.logger.Enabled(context.Background(), level) {
+		return nopadapter.Context{}
+	}
+
+	return a.context(level)
+}
+
+// context returns a context for records of the given level.
+func (a *Adapter) context(level slog.Level) *Context {
+	return newContext(Template{level: level, logger: a.logger, exit: a.exit, enc: a.enc, noPool: a.noPool})
+}
+
+// newContext returns a context with the level and settings of t, taken from the pool unless pooling is disabled.
+func newContext(t Template) *Context {
+	if t.noPool {
+		return &Context{level: t.level, logger: t.logger, fatal: t.fatal, exit: t.exit, enc: t.enc, noPool: true}
+	}
+
+	c, _ := contextPool.Get().(*Context)
+	c.level, c.logger, c.fatal, c.exit, c.enc = t.level, t.logger, t.fatal, t.exit, t.enc
+
+	return c
 }
 
-func (a *Adapter) newContext(level slog.Level) *Context {
-	return &Context{
-		level:  level,
-		logger: a.logger,
-		fields: make([]any, 0),
+// add appends attr to the fields of the record, allocating their buffer first if the context has none yet.
+func (c *Context) add(attr slog.Attr) {
+	if c.fields == nil {
+		c.fields = make([]slog.Attr, 0, defaultFields)
 	}
+
+	c.fields = append(c.fields, attr)
 }
 
 // With returns the logger with the given fields.
 func (a *Adapter) With(fields ...any) onelog.Logger {
-	return &Adapter{logger: a.logger.With(fields...)}
+	return &Adapter{logger: a.logger.With(a.enc.EncodeKeyValues(fields)...), exit: a.exit, enc: a.enc, noPool: a.noPool}
 }
 
 // Debug returns a LoggerContext for a debug log. To send the log, use the Msg or Msgf methods.
@@ -72,152 +184,183 @@ func (a *Adapter) Error() onelog.LoggerContext {
 	return a.newContext(slog.LevelError)
 }
 
-// Fatal returns a LoggerContext for a fatal log. To send the log, use the Msg or Msgf methods.
+// Fatal returns a LoggerContext for a fatal log. To send the log, use the Msg or Msgf methods. Slog has no fatal level,
+// so the record is written at error level. Once it is written, the exit hooks run and the process exits; see
+// onelog.Exit. Slog handlers write records synchronously, so there is nothing to flush.
 func (a *Adapter) Fatal() onelog.LoggerContext {
-	return a.newContext(slog.LevelError) // Using Error level here because Fatal is not supported by slog
+	ctx := a.context(slog.LevelError) // Exits even if the level is disabled
+	ctx.fatal = true
+
+	return ctx
+}
+
+// FatalNoExit returns a LoggerContext for a fatal log that does not exit once sent.
+func (a *Adapter) FatalNoExit() onelog.LoggerContext {
+	return a.newContext(slog.LevelError)
 }
 
 // Bytes adds the field key with val as a []byte to the logger context.
 func (c *Context) Bytes(key string, value []byte) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.String(key, string(value)))
+	c.add(slog.String(key, c.enc.EncodeBytes(value)))
 
 	return c
 }
 
 // Hex adds the field key with val as a hex string to the logger context.
 func (c *Context) Hex(key string, value []byte) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.String(key, fmt.Sprintf("%x", value)))
+	c.add(slog.String(key, fmt.Sprintf("%x", value)))
 
 	return c
 }
 
-// RawJSON adds the field key with val as a raw JSON string to the logger context.
+// rawJSON is a valid JSON value. The JSON handler embeds it verbatim, while text handlers write it as a string.
+type rawJSON []byte
+
+// MarshalJSON implements json.Marshaler.
+func (r rawJSON) MarshalJSON() ([]byte, error) {
+	return r, nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (r rawJSON) MarshalText() ([]byte, error) {
+	return r, nil
+}
+
+// RawJSON adds the field key with val as a json.RawMessage to the logger context. Invalid JSON is added as a string,
+// so that the record stays valid.
 func (c *Context) RawJSON(key string, value []byte) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.String(key, string(value)))
+	switch {
+	case len(value) == 0:
+		c.add(slog.Any(key, nil))
+	case !json.Valid(value):
+		c.add(slog.String(key, string(value)))
+	default:
+		c.add(slog.Any(key, rawJSON(value)))
+	}
 
 	return c
 }
 
 // Str adds the field key with val as a string to the logger context.
 func (c *Context) Str(key string, value string) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.String(key, value))
+	c.add(slog.String(key, value))
 
 	return c
 }
 
 // Strs adds the field key with val as a []string to the logger context.
 func (c *Context) Strs(key string, value []string) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.Any(key, value))
+	c.add(slog.Any(key, nilsafe.Slice(value)))
 
 	return c
 }
 
 // Stringer adds the field key with val as a fmt.Stringer to the logger context.
 func (c *Context) Stringer(key string, value fmt.Stringer) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.String(key, value.String()))
+	if nilsafe.IsNil(value) {
+		c.add(slog.Any(key, nil))
+		return c
+	}
+
+	c.add(slog.String(key, value.String()))
 
 	return c
 }
 
 // Stringers adds the field key with val as a []fmt.Stringer to the logger context.
 func (c *Context) Stringers(key string, value []fmt.Stringer) onelog.LoggerContext {
-	// Todo: Better way to do this?
-	strs := make([]string, len(value))
-	for i, str := range value {
-		strs[i] = str.String()
-	}
-	c.fields = append(c.fields, slog.Any(key, strs))
+	c.add(slog.Any(key, nilsafe.Strings(value)))
 
 	return c
 }
 
 // Int adds the field key with val as a int to the logger context.
 func (c *Context) Int(key string, value int) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.Int(key, value))
+	c.add(slog.Int(key, value))
 
 	return c
 }
 
 // Ints adds the field key with val as a []int to the logger context.
 func (c *Context) Ints(key string, value []int) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.Any(key, value))
+	c.add(slog.Any(key, nilsafe.Slice(value)))
 
 	return c
 }
 
 // Int8 adds the field key with val as a int8 to the logger context.
 func (c *Context) Int8(key string, value int8) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.Int64(key, int64(value)))
+	c.add(slog.Int64(key, int64(value)))
 
 	return c
 }
 
 // Ints8 adds the field key with val as a []int8 to the logger context.
 func (c *Context) Ints8(key string, value []int8) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.Any(key, value))
+	c.add(slog.Any(key, nilsafe.Slice(value)))
 
 	return c
 }
 
 // Int16 adds the field key with val as a int16 to the logger context.
 func (c *Context) Int16(key string, value int16) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.Int64(key, int64(value)))
+	c.add(slog.Int64(key, int64(value)))
 
 	return c
 }
 
 // Ints16 adds the field key with val as a []int16 to the logger context.
 func (c *Context) Ints16(key string, value []int16) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.Any(key, value))
+	c.add(slog.Any(key, nilsafe.Slice(value)))
 
 	return c
 }
 
 // Int32 adds the field key with val as a int32 to the logger context.
 func (c *Context) Int32(key string, value int32) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.Int64(key, int64(value)))
+	c.add(slog.Int64(key, int64(value)))
 
 	return c
 }
 
 // Ints32 adds the field key with val as a []int32 to the logger context.
 func (c *Context) Ints32(key string, value []int32) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.Any(key, value))
+	c.add(slog.Any(key, nilsafe.Slice(value)))
 
 	return c
 }
 
 // Int64 adds the field key with val as a int64 to the logger context.
 func (c *Context) Int64(key string, value int64) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.Int64(key, value))
+	c.add(slog.Int64(key, value))
 
 	return c
 }
 
 // Ints64 adds the field key with val as a []int64 to the logger context.
 func (c *Context) Ints64(key string, value []int64) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.Any(key, value))
+	c.add(slog.Any(key, nilsafe.Slice(value)))
 
 	return c
 }
 
 // Uint adds the field key with val as a uint to the logger context.
 func (c *Context) Uint(key string, value uint) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.Uint64(key, uint64(value)))
+	c.add(slog.Uint64(key, uint64(value)))
 
 	return c
 }
 
 // Uints adds the field key with val as a []uint to the logger context.
 func (c *Context) Uints(key string, value []uint) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.Any(key, value))
+	c.add(slog.Any(key, nilsafe.Slice(value)))
 
 	return c
 }
 
 // Uint8 adds the field key with val as a uint8 to the logger context.
 func (c *Context) Uint8(key string, value uint8) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.Uint64(key, uint64(value)))
+	c.add(slog.Uint64(key, uint64(value)))
 
 	return c
 }
@@ -231,157 +374,283 @@ func (c *Context) Uints8(key string, value []uint8) onelog.LoggerContext {
 		uints[i] = uint64(v)
 	}
 
-	c.fields = append(c.fields, slog.Any(key, uints))
+	c.add(slog.Any(key, uints))
 
 	return c
 }
 
 // Uint16 adds the field key with val as a uint16 to the logger context.
 func (c *Context) Uint16(key string, value uint16) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.Uint64(key, uint64(value)))
+	c.add(slog.Uint64(key, uint64(value)))
 
 	return c
 }
 
 // Uints16 adds the field key with val as a []uint16 to the logger context.
 func (c *Context) Uints16(key string, value []uint16) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.Any(key, value))
+	c.add(slog.Any(key, nilsafe.Slice(value)))
 
 	return c
 }
 
 // Uint32 adds the field key with val as a uint32 to the logger context.
 func (c *Context) Uint32(key string, value uint32) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.Uint64(key, uint64(value)))
+	c.add(slog.Uint64(key, uint64(value)))
 
 	return c
 }
 
 // Uints32 adds the field key with val as a []uint32 to the logger context.
 func (c *Context) Uints32(key string, value []uint32) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.Any(key, value))
+	c.add(slog.Any(key, nilsafe.Slice(value)))
 
 	return c
 }
 
 // Uint64 adds the field key with val as a uint64 to the logger context.
 func (c *Context) Uint64(key string, value uint64) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.Uint64(key, value))
+	c.add(slog.Uint64(key, value))
 
 	return c
 }
 
 // Uints64 adds the field key with val as a []uint64 to the logger context.
 func (c *Context) Uints64(key string, value []uint64) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.Any(key, value))
+	c.add(slog.Any(key, nilsafe.Slice(value)))
 
 	return c
 }
 
 // Float32 adds the field key with val as a float32 to the logger context.
 func (c *Context) Float32(key string, value float32) onelog.LoggerContext {
-	d, _ := decimal.NewFromFloat32(value).Float64()
+	// Widen the shortest decimal representation of value, so that e.g. 0.1 isn't rendered as 0.10000000149011612
+	d, _ := strconv.ParseFloat(strconv.FormatFloat(float64(value), 'g', -1, 32), 64)
 
-	c.fields = append(c.fields, slog.Float64(key, d))
+	c.add(slog.Float64(key, d))
 
 	return c
 }
 
 // Floats32 adds the field key with val as a []float32 to the logger context.
 func (c *Context) Floats32(key string, value []float32) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.Any(key, value))
+	c.add(slog.Any(key, nilsafe.Slice(value)))
 
 	return c
 }
 
 // Float64 adds the field key with val as a float64 to the logger context.
 func (c *Context) Float64(key string, value float64) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.Float64(key, value))
+	c.add(slog.Float64(key, value))
 
 	return c
 }
 
 // Floats64 adds the field key with val as a []float64 to the logger context.
 func (c *Context) Floats64(key string, value []float64) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.Any(key, value))
+	c.add(slog.Any(key, nilsafe.Slice(value)))
 
 	return c
 }
 
 // Bool adds the field key with val as a bool to the logger context.
 func (c *Context) Bool(key string, value bool) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.Bool(key, value))
+	c.add(slog.Bool(key, value))
 
 	return c
 }
 
 // Bools adds the field key with val as a []bool to the logger context.
 func (c *Context) Bools(key string, value []bool) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.Any(key, value))
+	c.add(slog.Any(key, nilsafe.Slice(value)))
 
 	return c
 }
 
 // Time adds the field key with val as a time.Time to the logger context.
 func (c *Context) Time(key string, value time.Time) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.Time(key, value))
+	switch c.enc.TimeFormat {
+	case "":
+		c.add(slog.String(key, value.Format(time.RFC3339Nano)))
+	case onelog.TimeFormatNative:
+		c.add(slog.Time(key, value))
+	default:
+		c.add(slog.Any(key, c.enc.EncodeTime(value)))
+	}
 
 	return c
 }
 
 // Times adds the field key with val as a []time.Time to the logger context.
 func (c *Context) Times(key string, value []time.Time) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.Any(key, value))
+	c.add(slog.Any(key, c.enc.EncodeTimes(value)))
 
 	return c
 }
 
 // Dur adds the field key with val as a time.Duration to the logger context.
 func (c *Context) Dur(key string, value time.Duration) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.Duration(key, value))
+	switch c.enc.DurationFormat {
+	case onelog.DurationNanos:
+		c.add(slog.Int64(key, int64(value)))
+	case onelog.DurationNative:
+		c.add(slog.Duration(key, value))
+	default:
+		c.add(slog.Any(key, c.enc.EncodeDuration(value)))
+	}
 
 	return c
 }
 
 // Durs adds the field key with val as a []time.Duration to the logger context.
 func (c *Context) Durs(key string, value []time.Duration) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.Any(key, value))
+	c.add(slog.Any(key, c.enc.EncodeDurations(value)))
 
 	return c
 }
 
-// TimeDiff adds the field key with begin and end as a time.Time to the logger context.
-func (c *Context) TimeDiff(key string, begin, end time.Time) onelog.LoggerContext {
-	diff := end.Sub(begin)
-	c.fields = append(c.fields, slog.Duration(key, diff))
-
-	return c
+// TimeDiff adds the field key with the duration between t and start to the logger context.
+func (c *Context) TimeDiff(key string, t, start time.Time) onelog.LoggerContext {
+	return c.Dur(key, t.Sub(start))
 }
 
 // IPAddr adds the field key with val as a net.IPAddr to the logger context.
 func (c *Context) IPAddr(key string, value net.IP) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.String(key, value.String()))
+	if len(value) == 0 {
+		c.add(slog.Any(key, nil))
+		return c
+	}
+
+	c.add(slog.String(key, value.String()))
 
 	return c
 }
 
 // IPPrefix adds the field key with val as a net.IPPrefix to the logger context.
 func (c *Context) IPPrefix(key string, value net.IPNet) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.String(key, value.String()))
+	if len(value.IP) == 0 {
+		c.add(slog.Any(key, nil))
+		return c
+	}
+
+	c.add(slog.String(key, value.String()))
 
 	return c
 }
 
 // MACAddr adds the field key with val as a net.HardwareAddr to the logger context.
 func (c *Context) MACAddr(key string, value net.HardwareAddr) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.String(key, value.String()))
+	if len(value) == 0 {
+		c.add(slog.Any(key, nil))
+		return c
+	}
+
+	c.add(slog.String(key, value.String()))
+
+	return c
+}
+
+// Addr adds the field key with val as a netip.Addr to the logger context.
+func (c *Context) Addr(key string, value netip.Addr) onelog.LoggerContext {
+	if !value.IsValid() {
+		c.add(slog.Any(key, nil))
+		return c
+	}
+
+	c.add(slog.String(key, value.String()))
+
+	return c
+}
+
+// Prefix adds the field key with val as a netip.Prefix to the logger context.
+func (c *Context) Prefix(key string, value netip.Prefix) onelog.LoggerContext {
+	if !value.IsValid() {
+		c.add(slog.Any(key, nil))
+		return c
+	}
+
+	c.add(slog.String(key, value.String()))
+
+	return c
+}
+
+// AddrPort adds the field key with val as a netip.AddrPort to the logger context.
+func (c *Context) AddrPort(key string, value netip.AddrPort) onelog.LoggerContext {
+	if !value.IsValid() {
+		c.add(slog.Any(key, nil))
+		return c
+	}
+
+	c.add(slog.String(key, value.String()))
+
+	return c
+}
+
+// URL adds the field key with val as a *url.URL to the logger context. The user information is redacted.
+func (c *Context) URL(key string, value *url.URL) onelog.LoggerContext {
+	if value == nil {
+		c.add(slog.Any(key, nil))
+		return c
+	}
+
+	c.add(slog.String(key, urlsafe.Redacted(value)))
+
+	return c
+}
+
+// BigInt adds the field key with val as a *big.Int to the logger context.
+func (c *Context) BigInt(key string, value *big.Int) onelog.LoggerContext {
+	if value == nil {
+		c.add(slog.Any(key, nil))
+		return c
+	}
+
+	return c.number(key, value.String())
+}
+
+// BigFloat adds the field key with val as a *big.Float to the logger context.
+func (c *Context) BigFloat(key string, value *big.Float) onelog.LoggerContext {
+	if value == nil {
+		c.add(slog.Any(key, nil))
+		return c
+	}
+
+	return c.number(key, numeric.BigFloat(value))
+}
+
+// Decimal adds the field key with val as a decimal number to the logger context.
+func (c *Context) Decimal(key string, value fmt.Stringer) onelog.LoggerContext {
+	if nilsafe.IsNil(value) {
+		c.add(slog.Any(key, nil))
+		return c
+	}
+
+	return c.number(key, value.String())
+}
+
+// number adds the field key with s embedded verbatim if it is a JSON number, or as a string otherwise.
+func (c *Context) number(key, s string) onelog.LoggerContext {
+	if !numeric.IsJSON(s) {
+		c.add(slog.String(key, s))
+		return c
+	}
+
+	c.add(slog.Any(key, rawJSON(s)))
 
 	return c
 }
 
 // AnErr adds the field key with val as a error to the logger context.
 func (c *Context) AnErr(key string, value error) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.String(key, value.Error()))
+	if nilsafe.IsNil(value) {
+		return c
+	}
+
+	if c.enc.ErrorFormat == onelog.ErrorString {
+		c.add(slog.String(key, value.Error()))
+		return c
+	}
+
+	c.add(slog.Any(key, c.enc.EncodeError(value)))
 
 	return c
 }
@@ -395,28 +664,23 @@ func (c *Context) Err(value error) onelog.LoggerContext {
 
 // Errs adds the field "error" with val as a []error to the logger context.
 func (c *Context) Errs(key string, value []error) onelog.LoggerContext {
-	// Todo: Better way to do this?
-	// Convert []error to []string. If we don't do this, slog prints empty objects
-	errs := make([]string, len(value))
-	for i, err := range value {
-		errs[i] = err.Error()
-	}
-
-	c.fields = append(c.fields, slog.Any(key, errs))
+	// Encode the errors ourselves. If we don't do this, slog prints empty objects
+	c.add(slog.Any(key, c.enc.EncodeErrors(value)))
 
 	return c
 }
 
 // Any adds the field key with val as a arbitrary value to the logger context.
 func (c *Context) Any(key string, value any) onelog.LoggerContext {
-	c.fields = append(c.fields, slog.Any(key, value))
+	value, _ = c.enc.EncodeValue(nilsafe.Value(value))
+	c.add(slog.Any(key, value))
 
 	return c
 }
 
 func (c *Context) Fields(fields onelog.Fields) onelog.LoggerContext {
-	for key, value := range fields {
-		c.fields = append(c.fields, slog.Any(key, value))
+	for _, key := range keys.Sorted(fields) {
+		c.Any(key, fields[key])
 	}
 
 	return c
@@ -425,8 +689,34 @@ func (c *Context) Fields(fields onelog.Fields) onelog.LoggerContext {
 // Msg sends the LoggerContext with msg to the logger.
 func (c *Context) Msg(msg string) {
 	//nolint:staticcheck // passing a nil context is fine, check slog.Logger.Info implementation for example
-	c.logger.Log(nil, c.level, msg, c.fields...)
-	c.fields = make([]any, 0) // reset fields
+	c.logger.LogAttrs(nil, c.level, msg, c.fields...)
+
+	fatal, exit := c.fatal, c.exit
+	c.release()
+
+	if fatal {
+		onelog.Exit(exit)
+	}
+}
+
+// release ends the record. Its fields are cleared, keeping their buffer, since slog copies the attributes into its
+// record. Unless pooling is disabled, the context is returned to the pool.
+func (c *Context) release() {
+	for i := range c.fields {
+		c.fields[i] = slog.Attr{} // Drop the references to the values
+	}
+	c.fields = c.fields[:0]
+
+	if c.noPool {
+		return
+	}
+
+	fields := c.fields
+	if cap(fields) > maxPooledFields {
+		fields = nil
+	}
+	*c = Context{fields: fields}
+	contextPool.Put(c)
 }
 
 // Msgf sends the LoggerContext with formatted msg to the logger.
@@ -434,3 +724,33 @@ func (c *Context) Msgf(format string, v ...any) {
 	msg := fmt.Sprintf(format, v...)
 	c.Msg(msg)
 }
+
+// Send sends the LoggerContext without a message to the logger.
+func (c *Context) Send() {
+	c.Msg("")
+}
+
+// Discard abandons the LoggerContext without writing the record.
+func (c *Context) Discard() {
+	c.release()
+}
+
+// Template returns an immutable snapshot of the level and fields of the LoggerContext.
+func (c *Context) Template() onelog.Template {
+	// The handler gets a copy of the fields, as their buffer is reused for the next record
+	attrs := append(make([]slog.Attr, 0, len(c.fields)), c.fields...)
+
+	return &Template{
+		level:  c.level,
+		logger: slog.New(c.logger.Handler().WithAttrs(attrs)),
+		fatal:  c.fatal,
+		exit:   c.exit,
+		enc:    c.enc,
+		noPool: c.noPool,
+	}
+}
+
+// Context returns a new LoggerContext holding the level and fields of the template.
+func (t *Template) Context() onelog.LoggerContext {
+	return newContext(*t)
+}
@@ -6,19 +6,25 @@ import (
 	"testing"
 
 	"github.com/nikoksr/onelog"
+	"github.com/nikoksr/onelog/internal/race"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/exp/slog"
 
-	"github.com/nikoksr/onelog/internal/testutils"
+	"github.com/nikoksr/onelog/onelogtest"
 )
 
 func newTestingAdapter(out io.Writer) onelog.Logger {
+	return newTestingAdapterWithOptions(out)
+}
+
+func newTestingAdapterWithOptions(out io.Writer, opts ...Option) onelog.Logger {
 	handler := slog.NewJSONHandler(out, &slog.HandlerOptions{
 		Level: slog.LevelDebug,
 	})
 	logger := slog.New(handler)
-	return NewAdapter(logger)
+	return NewAdapter(logger, opts...)
 }
 
 // TestNewAdapter tests if NewAdapter returns a non-nil *Adapter.
@@ -67,12 +73,93 @@ func TestContexts(t *testing.T) {
 	assert.Equal(t, logContext.(*Context).level, slog.LevelError, "the returned context should have the correct log level")
 }
 
-// TestMethods tests if each method returns a non-nil *Context and if the log is written correctly.
-func TestMethods(t *testing.T) {
+// TestConformance tests if the adapter passes the onelog conformance suite.
+func TestConformance(t *testing.T) {
+	t.Parallel()
+
+	onelogtest.Run(t, onelogtest.Config{
+		NewLogger: newTestingAdapter,
+		NewFatalLogger: func(out io.Writer, exit onelog.ExitFunc) onelog.Logger {
+			return newTestingAdapterWithOptions(out, WithExitFunc(exit))
+		},
+		NewEncodingLogger: func(out io.Writer, enc onelog.Encoding) onelog.Logger {
+			return newTestingAdapterWithOptions(out, WithEncoding(enc))
+		},
+		LevelNames: map[onelog.Level]string{onelog.FatalLevel: "ERROR"}, // slog has no fatal level
+		Pooled:     true,
+	})
+}
+
+// TestUnpooledConformance tests if the adapter passes the onelog conformance suite with pooling disabled.
+func TestUnpooledConformance(t *testing.T) {
+	t.Parallel()
+
+	onelogtest.Run(t, onelogtest.Config{
+		NewLogger: func(out io.Writer) onelog.Logger {
+			return newTestingAdapterWithOptions(out, WithoutPooling())
+		},
+		NewFatalLogger: func(out io.Writer, exit onelog.ExitFunc) onelog.Logger {
+			return newTestingAdapterWithOptions(out, WithoutPooling(), WithExitFunc(exit))
+		},
+		NewEncodingLogger: func(out io.Writer, enc onelog.Encoding) onelog.Logger {
+			return newTestingAdapterWithOptions(out, WithoutPooling(), WithEncoding(enc))
+		},
+		LevelNames: map[onelog.Level]string{onelog.FatalLevel: "ERROR"}, // slog has no fatal level
+	})
+}
+
+// TestAllocs tests if records with typed fields allocate nothing beyond writing them with slog directly from pooled
+// or reused contexts, or no more than the context and its field buffer from fresh unpooled ones, and if records of
+// disabled levels do not allocate at all.
+//
+//nolint:paralleltest // AllocsPerRun counts the allocations of all goroutines
+func TestAllocs(t *testing.T) {
+	if race.Enabled {
+		t.Skip("allocations are not reliable with the race detector")
+	}
+
+	logger := slog.New(slog.NewJSONHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	pooled := NewAdapter(logger)
+	unpooled := NewAdapter(logger, WithoutPooling())
+	reused := unpooled.Info()
+
+	addFields := func(c onelog.LoggerContext) onelog.LoggerContext {
+		return c.Str("str", "value").Int("int", 42).Int64("int64", 1234).Uint("uint", 42).Float64("float", 1.5).
+			Bool("bool", true)
+	}
+
+	raw := testing.AllocsPerRun(100, func() {
+		//nolint:staticcheck // passing a nil context is fine, check slog.Logger.Info implementation for example
+		logger.LogAttrs(nil, slog.LevelInfo, "message",
+			slog.String("str", "value"), slog.Int("int", 42), slog.Int64("int64", 1234), slog.Uint64("uint", 42),
+			slog.Float64("float", 1.5), slog.Bool("bool", true),
+		)
+	})
+
+	allocs := testing.AllocsPerRun(100, func() { addFields(unpooled.Info()).Msg("message") })
+	assert.LessOrEqual(t, allocs, raw+2, "fresh contexts should allocate no more than themselves and their buffer")
+
+	allocs = testing.AllocsPerRun(100, func() { addFields(pooled.Info()).Msg("message") })
+	assert.LessOrEqual(t, allocs, raw, "pooled contexts should not allocate beyond slog")
+
+	allocs = testing.AllocsPerRun(100, func() { addFields(reused).Msg("message") })
+	assert.LessOrEqual(t, allocs, raw, "reused contexts should not allocate beyond slog")
+
+	allocs = testing.AllocsPerRun(100, func() { addFields(pooled.Debug()).Msg("message") })
+	assert.Zero(t, allocs, "records of disabled levels should not allocate")
+}
+
+// TestFatalNoExit tests if FatalNoExit writes the log without terminating the process.
+func TestFatalNoExit(t *testing.T) {
 	t.Parallel()
 
 	buff := new(bytes.Buffer)
 	adapter := newTestingAdapter(buff)
 
-	testutils.TestingMethods(t, adapter, buff)
+	fatalWriter, ok := adapter.(onelog.FatalWriter)
+	require.True(t, ok, "the adapter should implement onelog.FatalWriter")
+
+	fatalWriter.FatalNoExit().Str("Test", "Value").Msg("Test message")
+
+	assert.Contains(t, buff.String(), "Test message", "the log should have been written")
 }
@@ -1,54 +1,165 @@
 package zapadapter
 
 import (
+	"encoding/json"
 	"fmt"
+	"math/big"
 	"net"
+	"net/netip"
+	"net/url"
+	"sync"
 	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
 	"github.com/nikoksr/onelog"
+	nopadapter "github.com/nikoksr/onelog/adapter/nop"
+	"github.com/nikoksr/onelog/internal/keys"
+	"github.com/nikoksr/onelog/internal/nilsafe"
+	"github.com/nikoksr/onelog/internal/numeric"
+	"github.com/nikoksr/onelog/internal/urlsafe"
 )
 
 // Compile-time check that Adapter and Context implements onelog.Logger and onelog.LoggerContext respectively
 var (
 	_ onelog.Logger        = (*Adapter)(nil)
 	_ onelog.LoggerContext = (*Context)(nil)
+	_ onelog.Template      = (*Template)(nil)
+	_ onelog.FatalWriter   = (*Adapter)(nil)
+	_ onelog.Syncer        = (*Adapter)(nil)
 )
 
 type (
 	// Adapter is a zap adapter for onelog. It implements the onelog.Logger interface.
 	Adapter struct {
 		logger *zap.Logger
+		opts   options
 	}
 
-	// Context is the zap logging context. It implements the onelog.LoggerContext interface.
+	// Context is the zap logging context. It implements the onelog.LoggerContext interface. A Context is not safe for
+	// concurrent use; use Template to share its fields between goroutines. Unless WithoutPooling is set, it is returned
+	// to a pool once sent or discarded and must not be used afterwards.
 	Context struct {
 		level  zapcore.Level
 		logger *zap.Logger
 		fields []zapcore.Field
+		fatal  bool
+		opts   options
+	}
+
+	// Template is an immutable snapshot of a Context. Its fields are encoded into a child logger once the template is
+	// taken, so it is safe for concurrent use and unaffected by later changes to the values. It implements the
+	// onelog.Template interface.
+	Template struct {
+		level  zapcore.Level
+		logger *zap.Logger
+		fatal  bool
+		opts   options
+	}
+
+	// Option configures an Adapter or a SugarAdapter.
+	Option func(*options)
+
+	options struct {
+		exit   onelog.ExitFunc
+		enc    onelog.Encoding
+		noPool bool
 	}
 )
 
+// WithExitFunc sets the function used to terminate the process after fatal records. By default, the process-wide exit
+// function of onelog is used; see onelog.SetExitFunc.
+func WithExitFunc(fn onelog.ExitFunc) Option {
+	return func(o *options) {
+		o.exit = fn
+	}
+}
+
+// WithEncoding sets the policy for rendering times, durations, bytes and errors. By default, the zero
+// onelog.Encoding is used.
+func WithEncoding(enc onelog.Encoding) Option {
+	return func(o *options) {
+		o.enc = enc
+	}
+}
+
+// WithoutPooling makes the adapter allocate a new context for every record instead of taking it from a pool.
+//
+// By default, contexts and their field buffers are taken from a pool and returned to it once the record is sent using
+// Msg, Msgf or Send, or abandoned using Discard, so that writing a record with typed fields allocates no more than zap
+// does in the steady state. A pooled context must not be used after it has been returned; take a Template of it instead
+// to emit many records with the same fields. The cores of the logger must not retain the fields they are given beyond
+// Write, which holds for all cores of zap. Without pooling, contexts may be reused after they are sent.
+func WithoutPooling() Option {
+	return func(o *options) {
+		o.noPool = true
+	}
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
 // NewAdapter creates a new zap adapter for onelog.
-func NewAdapter(l *zap.Logger) onelog.Logger {
+func NewAdapter(l *zap.Logger, opts ...Option) onelog.Logger {
 	return &Adapter{
 		logger: l,
+		opts:   newOptions(opts),
 	}
 }
 
+const (
+	// defaultFields is the initial capacity of the field buffers of contexts, which saves growing them repeatedly
+	// for typical records.
+	defaultFields = 16
+
+	// maxPooledFields is the capacity above which the field buffer of a context is dropped before the context is
+	// returned to the pool, so that a single large record does not pin its buffer for the lifetime of the pool.
+	maxPooledFields = 128
+)
+
+// contextPool holds the contexts of sent records for reuse.
+var contextPool = sync.Pool{
+	New: func() any { return new(Context) },
+}
+
+// newContext returns a context for records of the given level, or a nop context if the level is disabled, so that
+// records of disabled levels do not allocate.
 func (a *Adapter) newContext(level zapcore.Level) onelog.LoggerContext {
-	return &Context{
-		level:  level,
-		logger: a.logger,
-		fields: make([]zapcore.Field, 0),
+	if !a.logger.Core().Enabled(level) {
+		return nopadapter.Context{}
 	}
+
+	return newContext(Template{level: level, logger: a.logger, opts: a.opts})
+}
+
+// newContext returns a context with the level and settings of t, taken from the pool unless pooling is disabled.
+func newContext(t Template) *Context {
+	if t.opts.noPool {
+		return &Context{level: t.level, logger: t.logger, fatal: t.fatal, opts: t.opts}
+	}
+
+	c, _ := contextPool.Get().(*Context)
+	c.level, c.logger, c.fatal, c.opts = t.level, t.logger, t.fatal, t.opts
+
+	return c
 }
 
 // With returns the logger with the given fields.
 func (a *Adapter) With(fields ...any) onelog.Logger {
-	return &Adapter{logger: a.logger.Sugar().With(fields...).Desugar()}
+	fields = a.opts.enc.EncodeKeyValues(fields)
+	return &Adapter{logger: a.logger.Sugar().With(fields...).Desugar(), opts: a.opts}
+}
+
+// Sync flushes the buffered records of the underlying zap logger.
+func (a *Adapter) Sync() error {
+	return a.logger.Sync()
 }
 
 // Debug returns a LoggerContext for a debug log. To send the log, use the Msg or Msgf methods.
@@ -71,334 +182,604 @@ func (a *Adapter) Error() onelog.LoggerContext {
 	return a.newContext(zap.ErrorLevel)
 }
 
-// Fatal returns a LoggerContext for a fatal log. To send the log, use the Msg or Msgf methods.
+// Fatal returns a LoggerContext for a fatal log. To send the log, use the Msg or Msgf methods. Once the record is
+// written, the logger is synced, the exit hooks run and the process exits; see onelog.Exit.
 func (a *Adapter) Fatal() onelog.LoggerContext {
-	return a.newContext(zap.FatalLevel)
+	ctx := a.newFatalContext()
+	ctx.fatal = true
+
+	return ctx
 }
 
-func (c *Context) reset() {
-	c.fields = make([]zapcore.Field, 0)
+// FatalNoExit returns a LoggerContext for a fatal log that does not exit once sent.
+func (a *Adapter) FatalNoExit() onelog.LoggerContext {
+	return a.newFatalContext()
+}
+
+func (a *Adapter) newFatalContext() *Context {
+	return newContext(Template{
+		level:  zap.FatalLevel,
+		logger: a.logger.WithOptions(zap.WithFatalHook(noExitHook{})),
+		opts:   a.opts,
+	})
+}
+
+// noExitHook is a zapcore.CheckWriteHook that does nothing. Zap replaces zapcore.WriteThenNoop with os.Exit for fatal
+// entries, so we need our own no-op hook to write fatal entries without exiting.
+type noExitHook struct{}
+
+// OnWrite implements zapcore.CheckWriteHook.
+func (noExitHook) OnWrite(_ *zapcore.CheckedEntry, _ []zapcore.Field) {}
+
+// encodedField returns a field for a value returned by onelog.Encoding.
+func encodedField(key string, value any) zapcore.Field {
+	switch v := value.(type) {
+	case nil:
+		return nullField(key)
+	case string:
+		return zap.String(key, v)
+	case int64:
+		return zap.Int64(key, v)
+	case float64:
+		return zap.Float64(key, v)
+	default:
+		return zap.Any(key, v)
+	}
+}
+
+// nullField returns a field that renders key as null.
+func nullField(key string) zapcore.Field {
+	return zap.Reflect(key, nil)
+}
+
+// rawJSONField returns a field that embeds value verbatim, or null if it is empty. Invalid JSON is added as a string,
+// so that the record stays valid.
+func rawJSONField(key string, value []byte) zapcore.Field {
+	switch {
+	case len(value) == 0:
+		return nullField(key)
+	case !json.Valid(value):
+		return zap.ByteString(key, value)
+	default:
+		// Reflected fields are encoded using encoding/json, which writes json.RawMessage as is
+		return zap.Reflect(key, json.RawMessage(value))
+	}
+}
+
+// addrField returns a field that renders value as a string, or null if it is invalid.
+func addrField(key string, value netip.Addr) zapcore.Field {
+	if !value.IsValid() {
+		return nullField(key)
+	}
+
+	return zap.Stringer(key, value)
+}
+
+// prefixField returns a field that renders value as a string, or null if it is invalid.
+func prefixField(key string, value netip.Prefix) zapcore.Field {
+	if !value.IsValid() {
+		return nullField(key)
+	}
+
+	return zap.Stringer(key, value)
+}
+
+// addrPortField returns a field that renders value as a string, or null if it is invalid.
+func addrPortField(key string, value netip.AddrPort) zapcore.Field {
+	if !value.IsValid() {
+		return nullField(key)
+	}
+
+	return zap.Stringer(key, value)
+}
+
+// urlField returns a field that renders value with its user information redacted, or null if it is nil.
+func urlField(key string, value *url.URL) zapcore.Field {
+	if value == nil {
+		return nullField(key)
+	}
+
+	return zap.String(key, urlsafe.Redacted(value))
+}
+
+// bigIntField returns a field that renders value as a number, or null if it is nil.
+func bigIntField(key string, value *big.Int) zapcore.Field {
+	if value == nil {
+		return nullField(key)
+	}
+
+	return numberField(key, value.String())
+}
+
+// bigFloatField returns a field that renders value as a number, or null if it is nil.
+func bigFloatField(key string, value *big.Float) zapcore.Field {
+	if value == nil {
+		return nullField(key)
+	}
+
+	return numberField(key, numeric.BigFloat(value))
+}
+
+// decimalField returns a field that renders value as a number, or null if it is nil.
+func decimalField(key string, value fmt.Stringer) zapcore.Field {
+	if nilsafe.IsNil(value) {
+		return nullField(key)
+	}
+
+	return numberField(key, value.String())
+}
+
+// numberField returns a field that embeds s verbatim if it is a JSON number, or adds it as a string otherwise.
+func numberField(key, s string) zapcore.Field {
+	if !numeric.IsJSON(s) {
+		return zap.String(key, s)
+	}
+
+	return zap.Reflect(key, json.RawMessage(s))
+}
+
+// stringerArray is a []fmt.Stringer that renders nil elements as null. zap.Stringers calls String on nil elements.
+type stringerArray []fmt.Stringer
+
+// MarshalLogArray implements zapcore.ArrayMarshaler.
+func (a stringerArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, val := range a {
+		if nilsafe.IsNil(val) {
+			if err := enc.AppendReflected(nil); err != nil {
+				return err
+			}
+			continue
+		}
+		enc.AppendString(val.String())
+	}
+
+	return nil
+}
+
+// add appends f to the fields of the record, allocating their buffer first if the context has none yet.
+func (c *Context) add(f zapcore.Field) {
+	if c.fields == nil {
+		c.fields = make([]zapcore.Field, 0, defaultFields)
+	}
+
+	c.fields = append(c.fields, f)
+}
+
+// release ends the record. Its fields are cleared, keeping their buffer, since zap cores encode the fields of an entry
+// while writing it. Unless pooling is disabled, the context is returned to the pool.
+func (c *Context) release() {
+	for i := range c.fields {
+		c.fields[i] = zapcore.Field{} // Drop the references to the values
+	}
+	c.fields = c.fields[:0]
+
+	if c.opts.noPool {
+		return
+	}
+
+	fields := c.fields
+	if cap(fields) > maxPooledFields {
+		fields = nil
+	}
+	*c = Context{fields: fields}
+	contextPool.Put(c)
 }
 
 // Bytes adds the field key with val as a []byte to the logger context.
 func (c *Context) Bytes(key string, value []byte) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.ByteString(key, value))
+	c.add(zap.String(key, c.opts.enc.EncodeBytes(value)))
 
 	return c
 }
 
 // Hex adds the field key with val as a hex string to the logger context.
 func (c *Context) Hex(key string, value []byte) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.String(key, fmt.Sprintf("%x", value)))
+	c.add(zap.String(key, fmt.Sprintf("%x", value)))
 
 	return c
 }
 
-// RawJSON adds the field key with val as a raw json string to the logger context.
+// RawJSON adds the field key with val as a json.RawMessage to the logger context. Invalid JSON is added as a string,
+// so that the record stays valid.
 func (c *Context) RawJSON(key string, value []byte) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.ByteString(key, value))
+	c.add(rawJSONField(key, value))
 
 	return c
 }
 
 // Str adds the field key with val as a string to the logger context.
 func (c *Context) Str(key string, value string) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.String(key, value))
+	c.add(zap.String(key, value))
 
 	return c
 }
 
 // Strs adds the field key with val as a []string to the logger context.
 func (c *Context) Strs(key string, value []string) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.Strings(key, value))
+	c.add(zap.Strings(key, value))
 
 	return c
 }
 
 // Stringer adds the field key with val as a fmt.Stringer to the logger context.
 func (c *Context) Stringer(key string, val fmt.Stringer) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.Stringer(key, val))
+	if nilsafe.IsNil(val) {
+		c.add(nullField(key))
+		return c
+	}
+
+	c.add(zap.Stringer(key, val))
 
 	return c
 }
 
 // Stringers adds the field key with val as a []fmt.Stringer to the logger context.
 func (c *Context) Stringers(key string, vals []fmt.Stringer) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.Stringers(key, vals))
+	c.add(zap.Array(key, stringerArray(vals)))
 
 	return c
 }
 
 // Int adds the field key with val as a int to the logger context.
 func (c *Context) Int(key string, value int) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.Int(key, value))
+	c.add(zap.Int(key, value))
 
 	return c
 }
 
 // Ints adds the field key with val as a []int to the logger context.
 func (c *Context) Ints(key string, value []int) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.Ints(key, value))
+	c.add(zap.Ints(key, value))
 
 	return c
 }
 
 // Int8 adds the field key with val as a int8 to the logger context.
 func (c *Context) Int8(key string, value int8) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.Int8(key, value))
+	c.add(zap.Int8(key, value))
 
 	return c
 }
 
 // Ints8 adds the field key with val as a []int8 to the logger context.
 func (c *Context) Ints8(key string, value []int8) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.Int8s(key, value))
+	c.add(zap.Int8s(key, value))
 
 	return c
 }
 
 // Int16 adds the field key with val as a int16 to the logger context.
 func (c *Context) Int16(key string, value int16) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.Int16(key, value))
+	c.add(zap.Int16(key, value))
 
 	return c
 }
 
 // Ints16 adds the field key with val as a []int16 to the logger context.
 func (c *Context) Ints16(key string, value []int16) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.Int16s(key, value))
+	c.add(zap.Int16s(key, value))
 
 	return c
 }
 
 // Int32 adds the field key with val as a int32 to the logger context.
 func (c *Context) Int32(key string, value int32) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.Int32(key, value))
+	c.add(zap.Int32(key, value))
 
 	return c
 }
 
 // Ints32 adds the field key with val as a []int32 to the logger context.
 func (c *Context) Ints32(key string, value []int32) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.Int32s(key, value))
+	c.add(zap.Int32s(key, value))
 
 	return c
 }
 
 // Int64 adds the field key with val as a int64 to the logger context.
 func (c *Context) Int64(key string, value int64) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.Int64(key, value))
+	c.add(zap.Int64(key, value))
 
 	return c
 }
 
 // Ints64 adds the field key with val as a []int64 to the logger context.
 func (c *Context) Ints64(key string, value []int64) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.Int64s(key, value))
+	c.add(zap.Int64s(key, value))
 
 	return c
 }
 
 // Uint adds the field key with val as a uint to the logger context.
 func (c *Context) Uint(key string, value uint) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.Uint(key, value))
+	c.add(zap.Uint(key, value))
 
 	return c
 }
 
 // Uints adds the field key with val as a []uint to the logger context.
 func (c *Context) Uints(key string, value []uint) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.Uints(key, value))
+	c.add(zap.Uints(key, value))
 
 	return c
 }
 
 // Uint8 adds the field key with val as a uint8 to the logger context.
 func (c *Context) Uint8(key string, value uint8) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.Uint8(key, value))
+	c.add(zap.Uint8(key, value))
 
 	return c
 }
 
 // Uints8 adds the field key with val as a []uint8 to the logger context.
 func (c *Context) Uints8(key string, value []uint8) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.Uint8s(key, value))
+	c.add(zap.Uint8s(key, value))
 
 	return c
 }
 
 // Uint16 adds the field key with val as a uint16 to the logger context.
 func (c *Context) Uint16(key string, value uint16) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.Uint16(key, value))
+	c.add(zap.Uint16(key, value))
 
 	return c
 }
 
 // Uints16 adds the field key with val as a []uint16 to the logger context.
 func (c *Context) Uints16(key string, value []uint16) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.Uint16s(key, value))
+	c.add(zap.Uint16s(key, value))
 
 	return c
 }
 
 // Uint32 adds the field key with val as a uint32 to the logger context.
 func (c *Context) Uint32(key string, value uint32) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.Uint32(key, value))
+	c.add(zap.Uint32(key, value))
 
 	return c
 }
 
 // Uints32 adds the field key with val as a []uint32 to the logger context.
 func (c *Context) Uints32(key string, value []uint32) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.Uint32s(key, value))
+	c.add(zap.Uint32s(key, value))
 
 	return c
 }
 
 // Uint64 adds the field key with val as a uint64 to the logger context.
 func (c *Context) Uint64(key string, value uint64) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.Uint64(key, value))
+	c.add(zap.Uint64(key, value))
 
 	return c
 }
 
 // Uints64 adds the field key with val as a []uint64 to the logger context.
 func (c *Context) Uints64(key string, value []uint64) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.Uint64s(key, value))
+	c.add(zap.Uint64s(key, value))
 
 	return c
 }
 
 // Float32 adds the field key with val as a float32 to the logger context.
 func (c *Context) Float32(key string, value float32) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.Float32(key, value))
+	c.add(zap.Float32(key, value))
 
 	return c
 }
 
 // Floats32 adds the field key with val as a []float32 to the logger context.
 func (c *Context) Floats32(key string, value []float32) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.Float32s(key, value))
+	c.add(zap.Float32s(key, value))
 
 	return c
 }
 
 // Float64 adds the field key with val as a float64 to the logger context.
 func (c *Context) Float64(key string, value float64) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.Float64(key, value))
+	c.add(zap.Float64(key, value))
 
 	return c
 }
 
 // Floats64 adds the field key with val as a []float64 to the logger context.
 func (c *Context) Floats64(key string, value []float64) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.Float64s(key, value))
+	c.add(zap.Float64s(key, value))
 
 	return c
 }
 
 // Bool adds the field key with val as a bool to the logger context.
 func (c *Context) Bool(key string, value bool) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.Bool(key, value))
+	c.add(zap.Bool(key, value))
 
 	return c
 }
 
 // Bools adds the field key with val as a []bool to the logger context.
 func (c *Context) Bools(key string, value []bool) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.Bools(key, value))
+	c.add(zap.Bools(key, value))
 
 	return c
 }
 
 // Time adds the field key with val as a time.Time to the logger context.
 func (c *Context) Time(key string, value time.Time) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.Time(key, value))
+	if c.opts.enc.TimeFormat == onelog.TimeFormatNative {
+		c.add(zap.Time(key, value))
+		return c
+	}
+
+	c.add(encodedField(key, c.opts.enc.EncodeTime(value)))
 
 	return c
 }
 
 // Times adds the field key with val as a []time.Time to the logger context.
 func (c *Context) Times(key string, value []time.Time) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.Times(key, value))
+	c.add(zap.Any(key, c.opts.enc.EncodeTimes(value)))
 
 	return c
 }
 
 // Dur adds the field key with val as a time.Duration to the logger context.
 func (c *Context) Dur(key string, value time.Duration) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.Duration(key, value))
+	switch c.opts.enc.DurationFormat {
+	case onelog.DurationNanos:
+		c.add(zap.Int64(key, int64(value)))
+	case onelog.DurationNative:
+		c.add(zap.Duration(key, value))
+	default:
+		c.add(encodedField(key, c.opts.enc.EncodeDuration(value)))
+	}
 
 	return c
 }
 
 // Durs adds the field key with val as a []time.Duration to the logger context.
 func (c *Context) Durs(key string, value []time.Duration) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.Durations(key, value))
+	c.add(zap.Any(key, c.opts.enc.EncodeDurations(value)))
 
 	return c
 }
 
-// TimeDiff adds the field key with begin and end as a time.Time to the logger context.
-func (c *Context) TimeDiff(key string, begin, end time.Time) onelog.LoggerContext {
-	diff := end.Sub(begin)
-	c.fields = append(c.fields, zap.Duration(key, diff))
-
-	return c
+// TimeDiff adds the field key with the duration between t and start to the logger context.
+func (c *Context) TimeDiff(key string, t, start time.Time) onelog.LoggerContext {
+	return c.Dur(key, t.Sub(start))
 }
 
 // IPAddr adds the field key with val as a net.IP to the logger context.
 func (c *Context) IPAddr(key string, value net.IP) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.String(key, value.String()))
+	if len(value) == 0 {
+		c.add(nullField(key))
+		return c
+	}
+
+	c.add(zap.String(key, value.String()))
 
 	return c
 }
 
 // IPPrefix adds the field key with val as a net.IPNet to the logger context.
 func (c *Context) IPPrefix(key string, value net.IPNet) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.String(key, value.String()))
+	if len(value.IP) == 0 {
+		c.add(nullField(key))
+		return c
+	}
+
+	c.add(zap.String(key, value.String()))
 
 	return c
 }
 
 // MACAddr adds the field key with val as a net.HardwareAddr to the logger context.
 func (c *Context) MACAddr(key string, value net.HardwareAddr) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.String(key, value.String()))
+	if len(value) == 0 {
+		c.add(nullField(key))
+		return c
+	}
+
+	c.add(zap.String(key, value.String()))
+
+	return c
+}
+
+// Addr adds the field key with val as a netip.Addr to the logger context.
+func (c *Context) Addr(key string, value netip.Addr) onelog.LoggerContext {
+	c.add(addrField(key, value))
+
+	return c
+}
+
+// Prefix adds the field key with val as a netip.Prefix to the logger context.
+func (c *Context) Prefix(key string, value netip.Prefix) onelog.LoggerContext {
+	c.add(prefixField(key, value))
+
+	return c
+}
+
+// AddrPort adds the field key with val as a netip.AddrPort to the logger context.
+func (c *Context) AddrPort(key string, value netip.AddrPort) onelog.LoggerContext {
+	c.add(addrPortField(key, value))
+
+	return c
+}
+
+// URL adds the field key with val as a *url.URL to the logger context. The user information is redacted.
+func (c *Context) URL(key string, value *url.URL) onelog.LoggerContext {
+	c.add(urlField(key, value))
+
+	return c
+}
+
+// BigInt adds the field key with val as a *big.Int to the logger context.
+func (c *Context) BigInt(key string, value *big.Int) onelog.LoggerContext {
+	c.add(bigIntField(key, value))
+
+	return c
+}
+
+// BigFloat adds the field key with val as a *big.Float to the logger context.
+func (c *Context) BigFloat(key string, value *big.Float) onelog.LoggerContext {
+	c.add(bigFloatField(key, value))
+
+	return c
+}
+
+// Decimal adds the field key with val as a decimal number to the logger context.
+func (c *Context) Decimal(key string, value fmt.Stringer) onelog.LoggerContext {
+	c.add(decimalField(key, value))
 
 	return c
 }
 
 // AnErr adds the field key with val as a error to the logger context.
 func (c *Context) AnErr(key string, err error) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.NamedError(key, err))
+	if nilsafe.IsNil(err) {
+		return c
+	}
+
+	if c.opts.enc.ErrorFormat == onelog.ErrorString {
+		c.add(zap.String(key, err.Error()))
+		return c
+	}
+
+	c.add(encodedField(key, c.opts.enc.EncodeError(err)))
 
 	return c
 }
 
 // Err adds the field key with val as a error to the logger context.
 func (c *Context) Err(err error) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.Error(err))
-
-	return c
+	return c.AnErr("error", err)
 }
 
 // Errs adds the field key with val as a []error to the logger context.
 func (c *Context) Errs(key string, errs []error) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.Errors(key, errs))
+	c.add(zap.Any(key, c.opts.enc.EncodeErrors(errs)))
 
 	return c
 }
 
 // Any adds the field key with val as a arbitrary value to the logger context.
 func (c *Context) Any(key string, value any) onelog.LoggerContext {
-	c.fields = append(c.fields, zap.Any(key, value))
+	if nilsafe.IsNil(value) {
+		c.add(nullField(key))
+		return c
+	}
+
+	if encoded, ok := c.opts.enc.EncodeValue(value); ok {
+		c.add(encodedField(key, encoded))
+		return c
+	}
+
+	c.add(zap.Any(key, value))
 
 	return c
 }
 
 func (c *Context) Fields(fields onelog.Fields) onelog.LoggerContext {
-	for k, v := range fields {
-		c.fields = append(c.fields, zap.Any(k, v))
+	for _, key := range keys.Sorted(fields) {
+		c.Any(key, fields[key])
 	}
 
 	return c
@@ -407,7 +788,14 @@ func (c *Context) Fields(fields onelog.Fields) onelog.LoggerContext {
 // Msg sends the LoggerContext with msg to the logger.
 func (c *Context) Msg(msg string) {
 	c.logger.Log(c.level, msg, c.fields...)
-	c.reset()
+
+	logger, fatal, exit := c.logger, c.fatal, c.opts.exit
+	c.release()
+
+	if fatal {
+		_ = logger.Sync() // The process exits either way; there is nobody left to report the error to
+		onelog.Exit(exit)
+	}
 }
 
 // Msgf sends the LoggerContext with formatted msg to the logger.
@@ -415,3 +803,23 @@ func (c *Context) Msgf(format string, v ...any) {
 	msg := fmt.Sprintf(format, v...)
 	c.Msg(msg)
 }
+
+// Send sends the LoggerContext without a message to the logger.
+func (c *Context) Send() {
+	c.Msg("")
+}
+
+// Discard abandons the LoggerContext without writing the record.
+func (c *Context) Discard() {
+	c.release()
+}
+
+// Template returns an immutable snapshot of the level and fields of the LoggerContext.
+func (c *Context) Template() onelog.Template {
+	return &Template{level: c.level, logger: c.logger.With(c.fields...), fatal: c.fatal, opts: c.opts}
+}
+
+// Context returns a new LoggerContext holding the level and fields of the template.
+func (t *Template) Context() onelog.LoggerContext {
+	return newContext(*t)
+}
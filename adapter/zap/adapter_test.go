@@ -6,22 +6,24 @@ import (
 	"testing"
 
 	"github.com/nikoksr/onelog"
+	"github.com/nikoksr/onelog/internal/race"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
-	"github.com/nikoksr/onelog/internal/testutils"
+	"github.com/nikoksr/onelog/onelogtest"
 )
 
 func newLogger(out io.Writer) *zap.Logger {
 	return zap.New(
 		zapcore.NewCore(
 			zapcore.NewJSONEncoder(zapcore.EncoderConfig{
-				MessageKey:     "msg",
-				TimeKey:        "time",
-				EncodeTime:     zapcore.RFC3339NanoTimeEncoder,
-				EncodeDuration: zapcore.NanosDurationEncoder,
+				MessageKey:  "msg",
+				LevelKey:    "level",
+				EncodeLevel: zapcore.LowercaseLevelEncoder,
+				TimeKey:     "time",
 			}),
 			zapcore.AddSync(out),
 			zapcore.DebugLevel,
@@ -80,12 +82,107 @@ func TestContexts(t *testing.T) {
 	assert.Equal(t, logContext.(*Context).level, zap.FatalLevel, "the returned context should have the correct log level")
 }
 
-// TestMethods tests if each method returns a non-nil *Context and if the log is written correctly.
-func TestMethods(t *testing.T) {
+// TestConformance tests if the adapter passes the onelog conformance suite.
+func TestConformance(t *testing.T) {
+	t.Parallel()
+
+	onelogtest.Run(t, onelogtest.Config{
+		NewLogger: newAdapter,
+		NewFatalLogger: func(out io.Writer, exit onelog.ExitFunc) onelog.Logger {
+			return NewAdapter(newLogger(out), WithExitFunc(exit))
+		},
+		NewEncodingLogger: func(out io.Writer, enc onelog.Encoding) onelog.Logger {
+			return NewAdapter(newLogger(out), WithEncoding(enc))
+		},
+		Pooled: true,
+	})
+}
+
+// TestProductionEncoding tests if the default encoding renders values the same with zap's production encoder, whose
+// time and duration settings differ from onelog's.
+func TestProductionEncoding(t *testing.T) {
+	t.Parallel()
+
+	onelogtest.RunEncoding(t, onelogtest.Config{
+		NewLogger: func(out io.Writer) onelog.Logger {
+			core := zapcore.NewCore(
+				zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), zapcore.AddSync(out), zapcore.DebugLevel,
+			)
+			return NewAdapter(zap.New(core))
+		},
+	})
+}
+
+// TestUnpooledConformance tests if the adapter passes the onelog conformance suite with pooling disabled, including
+// the reuse of contexts after Msg.
+func TestUnpooledConformance(t *testing.T) {
+	t.Parallel()
+
+	onelogtest.Run(t, onelogtest.Config{
+		NewLogger: func(out io.Writer) onelog.Logger {
+			return NewAdapter(newLogger(out), WithoutPooling())
+		},
+		NewFatalLogger: func(out io.Writer, exit onelog.ExitFunc) onelog.Logger {
+			return NewAdapter(newLogger(out), WithoutPooling(), WithExitFunc(exit))
+		},
+		NewEncodingLogger: func(out io.Writer, enc onelog.Encoding) onelog.Logger {
+			return NewAdapter(newLogger(out), WithoutPooling(), WithEncoding(enc))
+		},
+	})
+}
+
+// addTypedFields adds the typed fields measured by the allocation tests.
+func addTypedFields(c onelog.LoggerContext) onelog.LoggerContext {
+	return c.Str("str", "value").Int("int", 42).Int64("int64", 1234).Uint("uint", 42).Float64("float", 1.5).
+		Bool("bool", true)
+}
+
+// TestAllocs tests if records with typed fields from pooled contexts allocate no more than writing them with zap
+// directly in the steady state, if fresh contexts without pooling allocate no more than themselves and their field
+// buffer on top, and if records of disabled levels do not allocate at all.
+//
+//nolint:paralleltest // AllocsPerRun counts the allocations of all goroutines
+func TestAllocs(t *testing.T) {
+	if race.Enabled {
+		t.Skip("allocations are not reliable with the race detector")
+	}
+
+	logger := newLogger(io.Discard).WithOptions(zap.IncreaseLevel(zapcore.InfoLevel))
+	pooled := NewAdapter(logger)
+	unpooled := NewAdapter(logger, WithoutPooling())
+	reused := unpooled.Info()
+
+	raw := testing.AllocsPerRun(100, func() {
+		logger.Info("message",
+			zap.String("str", "value"), zap.Int("int", 42), zap.Int64("int64", 1234), zap.Uint("uint", 42),
+			zap.Float64("float", 1.5), zap.Bool("bool", true),
+		)
+	})
+
+	allocs := testing.AllocsPerRun(100, func() { addTypedFields(unpooled.Info()).Msg("message") })
+	assert.LessOrEqual(t, allocs, raw+2, "fresh contexts should allocate no more than themselves and their buffer")
+
+	allocs = testing.AllocsPerRun(100, func() { addTypedFields(pooled.Info()).Msg("message") })
+	assert.LessOrEqual(t, allocs, raw, "pooled contexts should not allocate beyond zap")
+
+	allocs = testing.AllocsPerRun(100, func() { addTypedFields(reused).Msg("message") })
+	assert.LessOrEqual(t, allocs, raw, "reused contexts should not allocate beyond zap")
+
+	allocs = testing.AllocsPerRun(100, func() { addTypedFields(pooled.Debug()).Msg("message") })
+	assert.Zero(t, allocs, "records of disabled levels should not allocate")
+}
+
+// TestFatalNoExit tests if FatalNoExit writes the log without terminating the process.
+func TestFatalNoExit(t *testing.T) {
 	t.Parallel()
 
 	buff := new(bytes.Buffer)
 	adapter := newAdapter(buff)
 
-	testutils.TestingMethods(t, adapter, buff)
+	fatalWriter, ok := adapter.(onelog.FatalWriter)
+	require.True(t, ok, "the adapter should implement onelog.FatalWriter")
+
+	fatalWriter.FatalNoExit().Str("Test", "Value").Msg("Test message")
+
+	assert.Contains(t, buff.String(), "Test message", "the log should have been written")
 }
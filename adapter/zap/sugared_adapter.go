@@ -2,7 +2,11 @@ package zapadapter
 
 import (
 	"fmt"
+	"math/big"
 	"net"
+	"net/netip"
+	"net/url"
+	"sync"
 	"time"
 
 	"go.uber.org/zap/zapcore"
@@ -10,46 +14,92 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/nikoksr/onelog"
+	nopadapter "github.com/nikoksr/onelog/adapter/nop"
+	"github.com/nikoksr/onelog/internal/keys"
+	"github.com/nikoksr/onelog/internal/nilsafe"
 )
 
-// Compile-time check that Adapter and Context implements onelog.Logger and onelog.LoggerContext respectively
+// Compile-time check that SugarAdapter and SugarContext implements onelog.Logger and onelog.LoggerContext respectively
 var (
-	_ onelog.Logger        = (*Adapter)(nil)
-	_ onelog.LoggerContext = (*Context)(nil)
+	_ onelog.Logger        = (*SugarAdapter)(nil)
+	_ onelog.LoggerContext = (*SugarContext)(nil)
+	_ onelog.Template      = (*SugarTemplate)(nil)
+	_ onelog.FatalWriter   = (*SugarAdapter)(nil)
+	_ onelog.Syncer        = (*SugarAdapter)(nil)
 )
 
 type (
 	// SugarAdapter is a zap-sugared adapter for onelog. It implements the onelog.Logger interface.
 	SugarAdapter struct {
 		logger *zap.SugaredLogger
+		opts   options
 	}
 
-	// SugarContext is the zap-sugared logging context. It implements the onelog.LoggerContext interface.
+	// SugarContext is the zap-sugared logging context. It implements the onelog.LoggerContext interface. A
+	// SugarContext is not safe for concurrent use; use Template to share its fields between goroutines. Unless
+	// WithoutPooling is set, it is returned to a pool once sent or discarded and must not be used afterwards.
 	SugarContext struct {
 		level  zapcore.Level
 		logger *zap.SugaredLogger
 		fields []any
+		fatal  bool
+		opts   options
+	}
+
+	// SugarTemplate is an immutable snapshot of a SugarContext. Its fields are encoded into a child logger once the
+	// template is taken, so it is safe for concurrent use and unaffected by later changes to the values. It implements
+	// the onelog.Template interface.
+	SugarTemplate struct {
+		level  zapcore.Level
+		logger *zap.SugaredLogger
+		fatal  bool
+		opts   options
 	}
 )
 
 // NewSugarAdapter creates a new zap-sugared adapter for onelog.
-func NewSugarAdapter(l *zap.SugaredLogger) onelog.Logger {
+func NewSugarAdapter(l *zap.SugaredLogger, opts ...Option) onelog.Logger {
 	return &SugarAdapter{
 		logger: l,
+		opts:   newOptions(opts),
 	}
 }
 
+// sugarContextPool holds the contexts of sent records for reuse.
+var sugarContextPool = sync.Pool{
+	New: func() any { return new(SugarContext) },
+}
+
+// newContext returns a context for records of the given level, or a nop context if the level is disabled, so that
+// records of disabled levels do not allocate.
 func (a *SugarAdapter) newContext(level zapcore.Level) onelog.LoggerContext {
-	return &SugarContext{
-		level:  level,
-		logger: a.logger,
-		fields: make([]any, 0),
+	if !a.logger.Level().Enabled(level) {
+		return nopadapter.Context{}
 	}
+
+	return newSugarContext(SugarTemplate{level: level, logger: a.logger, opts: a.opts})
+}
+
+// newSugarContext returns a context with the level and settings of t, taken from the pool unless pooling is disabled.
+func newSugarContext(t SugarTemplate) *SugarContext {
+	if t.opts.noPool {
+		return &SugarContext{level: t.level, logger: t.logger, fatal: t.fatal, opts: t.opts}
+	}
+
+	c, _ := sugarContextPool.Get().(*SugarContext)
+	c.level, c.logger, c.fatal, c.opts = t.level, t.logger, t.fatal, t.opts
+
+	return c
 }
 
 // With returns the logger with the given fields.
 func (a *SugarAdapter) With(fields ...any) onelog.Logger {
-	return &SugarAdapter{logger: a.logger.With(fields...)}
+	return &SugarAdapter{logger: a.logger.With(a.opts.enc.EncodeKeyValues(fields)...), opts: a.opts}
+}
+
+// Sync flushes the buffered records of the underlying zap logger.
+func (a *SugarAdapter) Sync() error {
+	return a.logger.Sync()
 }
 
 // Debug returns a LoggerContext for a debug log. To send the log, use the Msg or Msgf methods.
@@ -72,25 +122,51 @@ func (a *SugarAdapter) Error() onelog.LoggerContext {
 	return a.newContext(zapcore.ErrorLevel)
 }
 
-// Fatal returns a LoggerContext for a fatal log. To send the log, use the Msg or Msgf methods.
+// Fatal returns a LoggerContext for a fatal log. To send the log, use the Msg or Msgf methods. Once the record is
+// written, the logger is synced, the exit hooks run and the process exits; see onelog.Exit.
 func (a *SugarAdapter) Fatal() onelog.LoggerContext {
-	return a.newContext(zapcore.FatalLevel)
+	ctx := a.newFatalContext()
+	ctx.fatal = true
+
+	return ctx
+}
+
+// FatalNoExit returns a LoggerContext for a fatal log that does not exit once sent.
+func (a *SugarAdapter) FatalNoExit() onelog.LoggerContext {
+	return a.newFatalContext()
+}
+
+func (a *SugarAdapter) newFatalContext() *SugarContext {
+	return newSugarContext(SugarTemplate{
+		level:  zapcore.FatalLevel,
+		logger: a.logger.WithOptions(zap.WithFatalHook(noExitHook{})),
+		opts:   a.opts,
+	})
+}
+
+// add appends values to the fields of the record, allocating their buffer first if the context has none yet. Every
+// field takes two elements, its key and its value.
+func (c *SugarContext) add(values ...any) {
+	if c.fields == nil {
+		c.fields = make([]any, 0, 2*defaultFields)
+	}
+
+	c.fields = append(c.fields, values...)
 }
 
 func (c *SugarContext) addField(key string, value any) {
-	c.fields = append(c.fields, key)
-	c.fields = append(c.fields, value)
+	c.add(key, value)
 }
 
 func (c *SugarContext) addFields(fields onelog.Fields) {
-	for key, value := range fields {
-		c.addField(key, value)
+	for _, key := range keys.Sorted(fields) {
+		c.Any(key, fields[key])
 	}
 }
 
 // Bytes adds the field key with val as a []byte to the logger context.
 func (c *SugarContext) Bytes(key string, value []byte) onelog.LoggerContext {
-	c.addField(key, string(value))
+	c.addField(key, c.opts.enc.EncodeBytes(value))
 
 	return c
 }
@@ -102,9 +178,10 @@ func (c *SugarContext) Hex(key string, value []byte) onelog.LoggerContext {
 	return c
 }
 
-// RawJSON adds the field key with val as a json.RawMessage to the logger context.
+// RawJSON adds the field key with val as a json.RawMessage to the logger context. Invalid JSON is added as a string,
+// so that the record stays valid.
 func (c *SugarContext) RawJSON(key string, value []byte) onelog.LoggerContext {
-	c.addField(key, string(value))
+	c.add(rawJSONField(key, value))
 
 	return c
 }
@@ -125,18 +202,19 @@ func (c *SugarContext) Strs(key string, value []string) onelog.LoggerContext {
 
 // Stringer adds the field key with val as a fmt.Stringer to the logger context.
 func (c *SugarContext) Stringer(key string, val fmt.Stringer) onelog.LoggerContext {
+	if nilsafe.IsNil(val) {
+		c.addField(key, nil)
+		return c
+	}
+
 	return c.Str(key, val.String())
 }
 
 // Stringers adds the field key with val as a []fmt.Stringer to the logger context.
 func (c *SugarContext) Stringers(key string, vals []fmt.Stringer) onelog.LoggerContext {
-	// Todo: Better way to do this?
-	strings := make([]string, len(vals))
-	for i, val := range vals {
-		strings[i] = val.String()
-	}
+	c.add(zap.Array(key, stringerArray(vals)))
 
-	return c.Strs(key, strings)
+	return c
 }
 
 // Int adds the field key with val as a int to the logger context.
@@ -330,42 +408,44 @@ func (c *SugarContext) Bools(key string, value []bool) onelog.LoggerContext {
 
 // Time adds the field key with val as a time.Time to the logger context.
 func (c *SugarContext) Time(key string, value time.Time) onelog.LoggerContext {
-	c.addField(key, value)
+	c.addField(key, c.opts.enc.EncodeTime(value))
 
 	return c
 }
 
 // Times adds the field key with val as a []time.Time to the logger context.
 func (c *SugarContext) Times(key string, value []time.Time) onelog.LoggerContext {
-	c.addField(key, value)
+	c.addField(key, c.opts.enc.EncodeTimes(value))
 
 	return c
 }
 
 // Dur adds the field key with val as a time.Duration to the logger context.
 func (c *SugarContext) Dur(key string, value time.Duration) onelog.LoggerContext {
-	c.addField(key, value)
+	c.addField(key, c.opts.enc.EncodeDuration(value))
 
 	return c
 }
 
 // Durs adds the field key with val as a []time.Duration to the logger context.
 func (c *SugarContext) Durs(key string, value []time.Duration) onelog.LoggerContext {
-	c.addField(key, value)
+	c.addField(key, c.opts.enc.EncodeDurations(value))
 
 	return c
 }
 
-// TimeDiff adds the field key with begin and end as a time.Time to the logger context.
-func (c *SugarContext) TimeDiff(key string, begin, end time.Time) onelog.LoggerContext {
-	diff := end.Sub(begin)
-	c.addField(key, diff)
-
-	return c
+// TimeDiff adds the field key with the duration between t and start to the logger context.
+func (c *SugarContext) TimeDiff(key string, t, start time.Time) onelog.LoggerContext {
+	return c.Dur(key, t.Sub(start))
 }
 
 // IPAddr adds the field key with val as a net.IP to the logger context.
 func (c *SugarContext) IPAddr(key string, value net.IP) onelog.LoggerContext {
+	if len(value) == 0 {
+		c.addField(key, nil)
+		return c
+	}
+
 	c.addField(key, value.String())
 
 	return c
@@ -373,6 +453,11 @@ func (c *SugarContext) IPAddr(key string, value net.IP) onelog.LoggerContext {
 
 // IPPrefix adds the field key with val as a net.IPNet to the logger context.
 func (c *SugarContext) IPPrefix(key string, value net.IPNet) onelog.LoggerContext {
+	if len(value.IP) == 0 {
+		c.addField(key, nil)
+		return c
+	}
+
 	c.addField(key, value.String())
 
 	return c
@@ -380,14 +465,72 @@ func (c *SugarContext) IPPrefix(key string, value net.IPNet) onelog.LoggerContex
 
 // MACAddr adds the field key with val as a net.HardwareAddr to the logger context.
 func (c *SugarContext) MACAddr(key string, value net.HardwareAddr) onelog.LoggerContext {
+	if len(value) == 0 {
+		c.addField(key, nil)
+		return c
+	}
+
 	c.addField(key, value.String())
 
 	return c
 }
 
+// Addr adds the field key with val as a netip.Addr to the logger context.
+func (c *SugarContext) Addr(key string, value netip.Addr) onelog.LoggerContext {
+	c.add(addrField(key, value))
+
+	return c
+}
+
+// Prefix adds the field key with val as a netip.Prefix to the logger context.
+func (c *SugarContext) Prefix(key string, value netip.Prefix) onelog.LoggerContext {
+	c.add(prefixField(key, value))
+
+	return c
+}
+
+// AddrPort adds the field key with val as a netip.AddrPort to the logger context.
+func (c *SugarContext) AddrPort(key string, value netip.AddrPort) onelog.LoggerContext {
+	c.add(addrPortField(key, value))
+
+	return c
+}
+
+// URL adds the field key with val as a *url.URL to the logger context. The user information is redacted.
+func (c *SugarContext) URL(key string, value *url.URL) onelog.LoggerContext {
+	c.add(urlField(key, value))
+
+	return c
+}
+
+// BigInt adds the field key with val as a *big.Int to the logger context.
+func (c *SugarContext) BigInt(key string, value *big.Int) onelog.LoggerContext {
+	c.add(bigIntField(key, value))
+
+	return c
+}
+
+// BigFloat adds the field key with val as a *big.Float to the logger context.
+func (c *SugarContext) BigFloat(key string, value *big.Float) onelog.LoggerContext {
+	c.add(bigFloatField(key, value))
+
+	return c
+}
+
+// Decimal adds the field key with val as a decimal number to the logger context.
+func (c *SugarContext) Decimal(key string, value fmt.Stringer) onelog.LoggerContext {
+	c.add(decimalField(key, value))
+
+	return c
+}
+
 // AnErr adds the field "error" with err as a string to the logger context.
 func (c *SugarContext) AnErr(key string, err error) onelog.LoggerContext {
-	c.addField(key, err.Error())
+	if nilsafe.IsNil(err) {
+		return c
+	}
+
+	c.addField(key, c.opts.enc.EncodeError(err))
 
 	return c
 }
@@ -401,13 +544,14 @@ func (c *SugarContext) Err(err error) onelog.LoggerContext {
 
 // Errs adds the field key with val as a []error to the logger context.
 func (c *SugarContext) Errs(key string, errs []error) onelog.LoggerContext {
-	c.addField(key, errs)
+	c.addField(key, c.opts.enc.EncodeErrors(errs))
 
 	return c
 }
 
 // Any adds the field key with val as a any to the logger context.
 func (c *SugarContext) Any(key string, value any) onelog.LoggerContext {
+	value, _ = c.opts.enc.EncodeValue(nilsafe.Value(value))
 	c.addField(key, value)
 
 	return c
@@ -432,9 +576,36 @@ func (c *SugarContext) Msg(msg string) {
 	case zapcore.ErrorLevel:
 		c.logger.Errorw(msg, c.fields...)
 	case zapcore.FatalLevel:
-		c.logger.Fatalw(msg, c.fields...)
+		c.logger.Fatalw(msg, c.fields...) // Does not exit, see newFatalContext
+	}
+
+	logger, fatal, exit := c.logger, c.fatal, c.opts.exit
+	c.release()
+
+	if fatal {
+		_ = logger.Sync() // The process exits either way; there is nobody left to report the error to
+		onelog.Exit(exit)
+	}
+}
+
+// release ends the record. Its fields are cleared, keeping their buffer, since the sugared logger converts them into
+// fields of its own. Unless pooling is disabled, the context is returned to the pool.
+func (c *SugarContext) release() {
+	for i := range c.fields {
+		c.fields[i] = nil // Drop the references to the values
+	}
+	c.fields = c.fields[:0]
+
+	if c.opts.noPool {
+		return
+	}
+
+	fields := c.fields
+	if cap(fields) > 2*maxPooledFields {
+		fields = nil
 	}
-	c.fields = make([]any, 0) // reset fields
+	*c = SugarContext{fields: fields}
+	sugarContextPool.Put(c)
 }
 
 // Msgf sends the LoggerContext with formatted msg to the logger.
@@ -442,3 +613,23 @@ func (c *SugarContext) Msgf(format string, v ...any) {
 	msg := fmt.Sprintf(format, v...)
 	c.Msg(msg)
 }
+
+// Send sends the LoggerContext without a message to the logger.
+func (c *SugarContext) Send() {
+	c.Msg("")
+}
+
+// Discard abandons the LoggerContext without writing the record.
+func (c *SugarContext) Discard() {
+	c.release()
+}
+
+// Template returns an immutable snapshot of the level and fields of the LoggerContext.
+func (c *SugarContext) Template() onelog.Template {
+	return &SugarTemplate{level: c.level, logger: c.logger.With(c.fields...), fatal: c.fatal, opts: c.opts}
+}
+
+// Context returns a new LoggerContext holding the level and fields of the template.
+func (t *SugarTemplate) Context() onelog.LoggerContext {
+	return newSugarContext(*t)
+}
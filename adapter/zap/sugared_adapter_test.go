@@ -6,11 +6,14 @@ import (
 	"testing"
 
 	"github.com/nikoksr/onelog"
+	"github.com/nikoksr/onelog/internal/race"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 
-	"github.com/nikoksr/onelog/internal/testutils"
+	"github.com/nikoksr/onelog/onelogtest"
 )
 
 func newSugarAdapter(out io.Writer) onelog.Logger {
@@ -63,12 +66,97 @@ func TestSugarContexts(t *testing.T) {
 	assert.Equal(t, logContext.(*SugarContext).level, zap.FatalLevel, "the returned context should have the correct log level")
 }
 
-// TestSugarMethods tests if each method returns a non-nil *Context and if the log is written correctly.
-func TestSugarMethods(t *testing.T) {
+// TestSugarConformance tests if the adapter passes the onelog conformance suite.
+func TestSugarConformance(t *testing.T) {
+	t.Parallel()
+
+	onelogtest.Run(t, onelogtest.Config{
+		NewLogger: newSugarAdapter,
+		NewFatalLogger: func(out io.Writer, exit onelog.ExitFunc) onelog.Logger {
+			return NewSugarAdapter(newLogger(out).Sugar(), WithExitFunc(exit))
+		},
+		NewEncodingLogger: func(out io.Writer, enc onelog.Encoding) onelog.Logger {
+			return NewSugarAdapter(newLogger(out).Sugar(), WithEncoding(enc))
+		},
+		Pooled: true,
+	})
+}
+
+// TestSugarProductionEncoding tests if the default encoding renders values the same with zap's production encoder,
+// whose time and duration settings differ from onelog's.
+func TestSugarProductionEncoding(t *testing.T) {
+	t.Parallel()
+
+	onelogtest.RunEncoding(t, onelogtest.Config{
+		NewLogger: func(out io.Writer) onelog.Logger {
+			core := zapcore.NewCore(
+				zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), zapcore.AddSync(out), zapcore.DebugLevel,
+			)
+			return NewSugarAdapter(zap.New(core).Sugar())
+		},
+	})
+}
+
+// TestSugarUnpooledConformance tests if the adapter passes the onelog conformance suite with pooling disabled,
+// including the reuse of contexts after Msg.
+func TestSugarUnpooledConformance(t *testing.T) {
+	t.Parallel()
+
+	onelogtest.Run(t, onelogtest.Config{
+		NewLogger: func(out io.Writer) onelog.Logger {
+			return NewSugarAdapter(newLogger(out).Sugar(), WithoutPooling())
+		},
+		NewFatalLogger: func(out io.Writer, exit onelog.ExitFunc) onelog.Logger {
+			return NewSugarAdapter(newLogger(out).Sugar(), WithoutPooling(), WithExitFunc(exit))
+		},
+		NewEncodingLogger: func(out io.Writer, enc onelog.Encoding) onelog.Logger {
+			return NewSugarAdapter(newLogger(out).Sugar(), WithoutPooling(), WithEncoding(enc))
+		},
+	})
+}
+
+// TestSugarAllocs tests if records with typed fields allocate no more than boxing their keys and values and the
+// fields the sugared logger converts them into, plus the context and its field buffer for fresh contexts without
+// pooling, and if records of disabled levels do not allocate at all.
+//
+//nolint:paralleltest // AllocsPerRun counts the allocations of all goroutines
+func TestSugarAllocs(t *testing.T) {
+	if race.Enabled {
+		t.Skip("allocations are not reliable with the race detector")
+	}
+
+	const fields = 6 // Number of fields added by addTypedFields
+
+	logger := newLogger(io.Discard).Sugar()
+	unpooled := NewSugarAdapter(logger, WithoutPooling())
+	pooled := NewSugarAdapter(logger.WithOptions(zap.IncreaseLevel(zapcore.InfoLevel)))
+	reused := unpooled.Info()
+	sugared := float64(2*fields + 1)
+
+	allocs := testing.AllocsPerRun(100, func() { addTypedFields(unpooled.Info()).Msg("message") })
+	assert.LessOrEqual(t, allocs, sugared+2, "fresh contexts should allocate no more than themselves and their buffer")
+
+	allocs = testing.AllocsPerRun(100, func() { addTypedFields(reused).Msg("message") })
+	assert.LessOrEqual(t, allocs, sugared, "reused contexts should not allocate beyond the sugared logger")
+
+	allocs = testing.AllocsPerRun(100, func() { addTypedFields(pooled.Info()).Msg("message") })
+	assert.LessOrEqual(t, allocs, sugared, "pooled contexts should not allocate beyond the sugared logger")
+
+	allocs = testing.AllocsPerRun(100, func() { addTypedFields(pooled.Debug()).Msg("message") })
+	assert.Zero(t, allocs, "records of disabled levels should not allocate")
+}
+
+// TestSugarFatalNoExit tests if FatalNoExit writes the log without terminating the process.
+func TestSugarFatalNoExit(t *testing.T) {
 	t.Parallel()
 
 	buff := new(bytes.Buffer)
 	adapter := newSugarAdapter(buff)
 
-	testutils.TestingMethods(t, adapter, buff)
+	fatalWriter, ok := adapter.(onelog.FatalWriter)
+	require.True(t, ok, "the adapter should implement onelog.FatalWriter")
+
+	fatalWriter.FatalNoExit().Str("Test", "Value").Msg("Test message")
+
+	assert.Contains(t, buff.String(), "Test message", "the log should have been written")
 }
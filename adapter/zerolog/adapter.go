@@ -1,427 +1,795 @@
 package zerologadapter
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
+	"math/big"
 	"net"
+	"net/netip"
+	"net/url"
 	"time"
 
 	"github.com/rs/zerolog"
 
 	"github.com/nikoksr/onelog"
+	nopadapter "github.com/nikoksr/onelog/adapter/nop"
+	"github.com/nikoksr/onelog/internal/keys"
+	"github.com/nikoksr/onelog/internal/nilsafe"
+	"github.com/nikoksr/onelog/internal/numeric"
+	"github.com/nikoksr/onelog/internal/record"
+	"github.com/nikoksr/onelog/internal/urlsafe"
 )
 
 // Compile-time check that Adapter and Context implements onelog.Logger and onelog.LoggerContext respectively
 var (
 	_ onelog.Logger        = (*Adapter)(nil)
 	_ onelog.LoggerContext = (*Context)(nil)
+	_ onelog.Template      = (*Template)(nil)
+	_ onelog.FatalWriter   = (*Adapter)(nil)
 )
 
 type (
 	// Adapter is a zerolog adapter for onelog. It implements the onelog.Logger interface.
 	Adapter struct {
 		logger *zerolog.Logger
+		exit   onelog.ExitFunc
+		enc    onelog.Encoding
 	}
 
-	// Context is the zerolog logging context. It implements the onelog.LoggerContext interface.
+	// Context is the zerolog logging context. It implements the onelog.LoggerContext interface. The fields are
+	// encoded into the zerolog.Event of the record right away; a journal of them is kept on the side, so that Template
+	// can replay them. A Context is not safe for concurrent use; use Template to share its fields between goroutines.
 	Context struct {
-		logger       *zerolog.Logger
-		event        *zerolog.Event
-		resetEventFn func() *zerolog.Event
+		logger  *zerolog.Logger
+		event   *zerolog.Event
+		level   zerolog.Level
+		sent    bool // The event was sent; the next field or message starts a new one
+		fatal   bool
+		exit    onelog.ExitFunc
+		enc     onelog.Encoding
+		journal *journal
+		replay  bool // The context replays a journal; see replay
 	}
+
+	// Template is an immutable snapshot of a Context. It holds a child logger with the fields encoded into it, so it is
+	// safe for concurrent use and unaffected by later changes to the values. It implements the onelog.Template
+	// interface.
+	Template struct {
+		logger *zerolog.Logger
+		level  zerolog.Level
+		fatal  bool
+		exit   onelog.ExitFunc
+		enc    onelog.Encoding
+	}
+
+	// Option configures an Adapter.
+	Option func(*Adapter)
 )
 
+// WithExitFunc sets the function used to terminate the process after fatal records. By default, the process-wide exit
+// function of onelog is used; see onelog.SetExitFunc.
+func WithExitFunc(fn onelog.ExitFunc) Option {
+	return func(a *Adapter) {
+		a.exit = fn
+	}
+}
+
+// WithEncoding sets the policy for rendering times, durations, bytes and errors. By default, the zero
+// onelog.Encoding is used.
+func WithEncoding(enc onelog.Encoding) Option {
+	return func(a *Adapter) {
+		a.enc = enc
+	}
+}
+
 // NewAdapter creates a new zerolog adapter for onelog.
-func NewAdapter(l *zerolog.Logger) onelog.Logger {
-	return &Adapter{
+func NewAdapter(l *zerolog.Logger, opts ...Option) onelog.Logger {
+	a := &Adapter{
 		logger: l,
 	}
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	return a
 }
 
 // With returns the logger with the given fields.
 func (a *Adapter) With(fields ...any) onelog.Logger {
-	logger := a.logger.With().Fields(fields).Logger()
-	return &Adapter{logger: &logger}
+	logger := a.logger.With().Fields(a.enc.EncodeKeyValues(fields)).Logger()
+	return &Adapter{logger: &logger, exit: a.exit, enc: a.enc}
 }
 
 // Debug returns a LoggerContext for a debug log. To send the log, use the Msg or Msgf methods.
 func (a *Adapter) Debug() onelog.LoggerContext {
-	return &Context{
-		logger:       a.logger,
-		event:        a.logger.Debug(),
-		resetEventFn: a.logger.Debug,
-	}
+	return a.newContext(zerolog.DebugLevel)
 }
 
 // Info returns a LoggerContext for a info log. To send the log, use the Msg or Msgf methods.
 func (a *Adapter) Info() onelog.LoggerContext {
-	return &Context{
-		logger:       a.logger,
-		event:        a.logger.Info(),
-		resetEventFn: a.logger.Info,
-	}
+	return a.newContext(zerolog.InfoLevel)
 }
 
 // Warn returns a LoggerContext for a warn log. To send the log, use the Msg or Msgf methods.
 func (a *Adapter) Warn() onelog.LoggerContext {
-	return &Context{
-		logger:       a.logger,
-		event:        a.logger.Warn(),
-		resetEventFn: a.logger.Warn,
-	}
+	return a.newContext(zerolog.WarnLevel)
 }
 
 // Error returns a LoggerContext for a error log. To send the log, use the Msg or Msgf methods.
 func (a *Adapter) Error() onelog.LoggerContext {
-	return &Context{
-		logger:       a.logger,
-		event:        a.logger.Error(),
-		resetEventFn: a.logger.Error,
-	}
+	return a.newContext(zerolog.ErrorLevel)
 }
 
-// Fatal returns a LoggerContext for a fatal log. To send the log, use the Msg or Msgf methods.
+// Fatal returns a LoggerContext for a fatal log. To send the log, use the Msg or Msgf methods. Once the record is
+// written, the exit hooks run and the process exits; see onelog.Exit. Zerolog writes records synchronously, so there
+// is nothing to flush.
 func (a *Adapter) Fatal() onelog.LoggerContext {
+	ctx := a.context(zerolog.FatalLevel) // Exits even if the level is disabled
+	ctx.fatal = true
+
+	return ctx
+}
+
+// FatalNoExit returns a LoggerContext for a fatal log that does not exit once sent.
+func (a *Adapter) FatalNoExit() onelog.LoggerContext {
+	return a.newContext(zerolog.FatalLevel)
+}
+
+// newContext returns a context for records of the given level, or a nop context if the level is disabled, so that
+// records of disabled levels do not allocate.
+func (a *Adapter) newContext(level zerolog.Level) onelog.LoggerContext {
+	if level < a.logger.GetLevel() || level < zerolog.GlobalLevel() {
+		return nopadapter.Context{}
+	}
+
+	return a.context(level)
+}
+
+// context returns a context for records of the given level. Records are written using WithLevel, which, unlike
+// zerolog's own fatal events, never exits. The event of a disabled level is nil, so its fields are not encoded.
+func (a *Adapter) context(level zerolog.Level) *Context {
 	return &Context{
-		logger:       a.logger,
-		event:        a.logger.Fatal(),
-		resetEventFn: a.logger.Fatal,
+		logger: a.logger,
+		event:  a.logger.WithLevel(level),
+		level:  level,
+		exit:   a.exit,
+		enc:    a.enc,
 	}
 }
 
+// ev returns the event of the record, starting a new one if the previous record was sent. Starting it lazily keeps
+// contexts that are dropped after Msg from holding on to a pooled event.
+func (c *Context) ev() *zerolog.Event {
+	if c.sent {
+		c.event = c.logger.WithLevel(c.level)
+		c.sent = false
+	}
+
+	return c.event
+}
+
+// reset ends the current record and releases its journal.
 func (c *Context) reset() {
-	c.event = c.resetEventFn()
+	c.event = nil
+	c.sent = true
+
+	if c.journal != nil {
+		c.journal.release()
+		c.journal = nil
+	}
+}
+
+// record adds e to the journal, unless the level is disabled or the journal is being replayed.
+func (c *Context) record(e entry) {
+	if c.event == nil || c.replay {
+		return
+	}
+
+	if c.journal == nil {
+		c.journal, _ = journalPool.Get().(*journal)
+	}
+	*c.journal = append(*c.journal, e)
+}
+
+// null adds the field key with a null value.
+func (c *Context) null(key string) {
+	c.ev().Interface(key, nil)
+	c.record(entry{kind: record.KindAny, key: key})
+}
+
+// str adds the field key with a string value.
+func (c *Context) str(key, value string) {
+	c.ev().Str(key, value)
+	c.record(entry{kind: record.KindStr, key: key, str: value})
+}
+
+// boolBits returns b as the num of an entry.
+func boolBits(b bool) uint64 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+// encoded adds a value returned by onelog.Encoding to the logger context.
+func (c *Context) encoded(key string, value any) {
+	switch v := value.(type) {
+	case string:
+		c.ev().Str(key, v)
+	case int64:
+		c.ev().Int64(key, v)
+	case float64:
+		c.ev().Float64(key, v)
+	default:
+		c.ev().Interface(key, v)
+	}
 }
 
 // Bytes adds the field key with val as a []byte to the logger context.
 func (c *Context) Bytes(key string, value []byte) onelog.LoggerContext {
-	c.event.Bytes(key, value)
+	c.str(key, c.enc.EncodeBytes(value))
 
 	return c
 }
 
 // Hex adds the field key with val as a hex string to the logger context.
 func (c *Context) Hex(key string, value []byte) onelog.LoggerContext {
-	c.event.Hex(key, value)
+	c.ev().Hex(key, value)
+	c.record(entry{kind: record.KindHex, key: key, bytes: value})
 
 	return c
 }
 
-// RawJSON adds the field key with val as a json.RawMessage to the logger context.
+// RawJSON adds the field key with val as a json.RawMessage to the logger context. Invalid JSON is added as a string,
+// so that the record stays valid.
 func (c *Context) RawJSON(key string, value []byte) onelog.LoggerContext {
-	c.event.RawJSON(key, value)
+	switch {
+	case len(value) == 0:
+		c.null(key)
+	case !json.Valid(value):
+		c.str(key, string(value))
+	default:
+		c.ev().RawJSON(key, value)
+		c.record(entry{kind: record.KindRawJSON, key: key, bytes: value})
+	}
 
 	return c
 }
 
 // Str adds the field key with val as a string to the logger context.
 func (c *Context) Str(key, value string) onelog.LoggerContext {
-	c.event.Str(key, value)
+	c.ev().Str(key, value)
+	c.record(entry{kind: record.KindStr, key: key, str: value})
 
 	return c
 }
 
 // Strs adds the field key with val as a []string to the logger context.
 func (c *Context) Strs(key string, value []string) onelog.LoggerContext {
-	c.event.Strs(key, value)
+	c.ev().Strs(key, value)
+	c.record(entry{kind: record.KindStrs, key: key, val: value})
 
 	return c
 }
 
 // Stringer adds the field key with val as a fmt.Stringer to the logger context.
 func (c *Context) Stringer(key string, val fmt.Stringer) onelog.LoggerContext {
-	c.event.Stringer(key, val)
+	if nilsafe.IsNil(val) {
+		c.null(key)
+		return c
+	}
+
+	c.ev().Stringer(key, val)
+	c.record(entry{kind: record.KindStringer, key: key, val: val})
 
 	return c
 }
 
 // Stringers adds the field key with val as a []fmt.Stringer to the logger context.
 func (c *Context) Stringers(key string, vals []fmt.Stringer) onelog.LoggerContext {
-	c.event.Stringers(key, vals)
+	arr := zerolog.Arr()
+	for _, val := range vals {
+		if nilsafe.IsNil(val) {
+			arr.Interface(nil)
+		} else {
+			arr.Str(val.String())
+		}
+	}
+	c.ev().Array(key, arr)
+	c.record(entry{kind: record.KindStringers, key: key, val: vals})
 
 	return c
 }
 
 // Int adds the field key with i as a int to the logger context.
 func (c *Context) Int(key string, value int) onelog.LoggerContext {
-	c.event.Int(key, value)
+	c.ev().Int(key, value)
+	c.record(entry{kind: record.KindInt, key: key, num: uint64(value)})
 
 	return c
 }
 
 // Ints adds the field key with i as a []int to the logger context.
 func (c *Context) Ints(key string, value []int) onelog.LoggerContext {
-	c.event.Ints(key, value)
+	c.ev().Ints(key, value)
+	c.record(entry{kind: record.KindInts, key: key, val: value})
 
 	return c
 }
 
 // Int8 adds the field key with i as a int8 to the logger context.
 func (c *Context) Int8(key string, value int8) onelog.LoggerContext {
-	c.event.Int8(key, value)
+	c.ev().Int8(key, value)
+	c.record(entry{kind: record.KindInt8, key: key, num: uint64(value)})
 
 	return c
 }
 
 // Ints8 adds the field key with i as a []int8 to the logger context.
 func (c *Context) Ints8(key string, value []int8) onelog.LoggerContext {
-	c.event.Ints8(key, value)
+	c.ev().Ints8(key, value)
+	c.record(entry{kind: record.KindInts8, key: key, val: value})
 
 	return c
 }
 
 // Int16 adds the field key with i as a int16 to the logger context.
 func (c *Context) Int16(key string, value int16) onelog.LoggerContext {
-	c.event.Int16(key, value)
+	c.ev().Int16(key, value)
+	c.record(entry{kind: record.KindInt16, key: key, num: uint64(value)})
 
 	return c
 }
 
 // Ints16 adds the field key with i as a []int16 to the logger context.
 func (c *Context) Ints16(key string, value []int16) onelog.LoggerContext {
-	c.event.Ints16(key, value)
+	c.ev().Ints16(key, value)
+	c.record(entry{kind: record.KindInts16, key: key, val: value})
 
 	return c
 }
 
 // Int32 adds the field key with i as a int32 to the logger context.
 func (c *Context) Int32(key string, value int32) onelog.LoggerContext {
-	c.event.Int32(key, value)
+	c.ev().Int32(key, value)
+	c.record(entry{kind: record.KindInt32, key: key, num: uint64(value)})
 
 	return c
 }
 
 // Ints32 adds the field key with i as a []int32 to the logger context.
 func (c *Context) Ints32(key string, value []int32) onelog.LoggerContext {
-	c.event.Ints32(key, value)
+	c.ev().Ints32(key, value)
+	c.record(entry{kind: record.KindInts32, key: key, val: value})
 
 	return c
 }
 
 // Int64 adds the field key with i as a int64 to the logger context.
 func (c *Context) Int64(key string, value int64) onelog.LoggerContext {
-	c.event.Int64(key, value)
+	c.ev().Int64(key, value)
+	c.record(entry{kind: record.KindInt64, key: key, num: uint64(value)})
 
 	return c
 }
 
 // Ints64 adds the field key with i as a []int64 to the logger context.
 func (c *Context) Ints64(key string, value []int64) onelog.LoggerContext {
-	c.event.Ints64(key, value)
+	c.ev().Ints64(key, value)
+	c.record(entry{kind: record.KindInts64, key: key, val: value})
 
 	return c
 }
 
 // Uint adds the field key with i as a uint to the logger context.
 func (c *Context) Uint(key string, value uint) onelog.LoggerContext {
-	c.event.Uint(key, value)
+	c.ev().Uint(key, value)
+	c.record(entry{kind: record.KindUint, key: key, num: uint64(value)})
 
 	return c
 }
 
 // Uints adds the field key with i as a []uint to the logger context.
 func (c *Context) Uints(key string, value []uint) onelog.LoggerContext {
-	c.event.Uints(key, value)
+	c.ev().Uints(key, value)
+	c.record(entry{kind: record.KindUints, key: key, val: value})
 
 	return c
 }
 
 // Uint8 adds the field key with i as a uint8 to the logger context.
 func (c *Context) Uint8(key string, value uint8) onelog.LoggerContext {
-	c.event.Uint8(key, value)
+	c.ev().Uint8(key, value)
+	c.record(entry{kind: record.KindUint8, key: key, num: uint64(value)})
 
 	return c
 }
 
 // Uints8 adds the field key with i as a []uint8 to the logger context.
 func (c *Context) Uints8(key string, value []uint8) onelog.LoggerContext {
-	c.event.Uints8(key, value)
+	c.ev().Uints8(key, value)
+	c.record(entry{kind: record.KindUints8, key: key, val: value})
 
 	return c
 }
 
 // Uint16 adds the field key with i as a uint16 to the logger context.
 func (c *Context) Uint16(key string, value uint16) onelog.LoggerContext {
-	c.event.Uint16(key, value)
+	c.ev().Uint16(key, value)
+	c.record(entry{kind: record.KindUint16, key: key, num: uint64(value)})
 
 	return c
 }
 
 // Uints16 adds the field key with i as a []uint16 to the logger context.
 func (c *Context) Uints16(key string, value []uint16) onelog.LoggerContext {
-	c.event.Uints16(key, value)
+	c.ev().Uints16(key, value)
+	c.record(entry{kind: record.KindUints16, key: key, val: value})
 
 	return c
 }
 
 // Uint32 adds the field key with i as a uint32 to the logger context.
 func (c *Context) Uint32(key string, value uint32) onelog.LoggerContext {
-	c.event.Uint32(key, value)
+	c.ev().Uint32(key, value)
+	c.record(entry{kind: record.KindUint32, key: key, num: uint64(value)})
 
 	return c
 }
 
 // Uints32 adds the field key with i as a []uint32 to the logger context.
 func (c *Context) Uints32(key string, value []uint32) onelog.LoggerContext {
-	c.event.Uints32(key, value)
+	c.ev().Uints32(key, value)
+	c.record(entry{kind: record.KindUints32, key: key, val: value})
 
 	return c
 }
 
 // Uint64 adds the field key with i as a uint64 to the logger context.
 func (c *Context) Uint64(key string, value uint64) onelog.LoggerContext {
-	c.event.Uint64(key, value)
+	c.ev().Uint64(key, value)
+	c.record(entry{kind: record.KindUint64, key: key, num: uint64(value)})
 
 	return c
 }
 
 // Uints64 adds the field key with i as a []uint64 to the logger context.
 func (c *Context) Uints64(key string, value []uint64) onelog.LoggerContext {
-	c.event.Uints64(key, value)
+	c.ev().Uints64(key, value)
+	c.record(entry{kind: record.KindUints64, key: key, val: value})
 
 	return c
 }
 
 // Float32 adds the field key with f as a float32 to the logger context.
 func (c *Context) Float32(key string, value float32) onelog.LoggerContext {
-	c.event.Float32(key, value)
+	c.ev().Float32(key, value)
+	c.record(entry{kind: record.KindFloat32, key: key, num: uint64(math.Float32bits(value))})
 
 	return c
 }
 
 // Floats32 adds the field key with f as a []float32 to the logger context.
 func (c *Context) Floats32(key string, value []float32) onelog.LoggerContext {
-	c.event.Floats32(key, value)
+	c.ev().Floats32(key, value)
+	c.record(entry{kind: record.KindFloats32, key: key, val: value})
 
 	return c
 }
 
 // Float64 adds the field key with f as a float64 to the logger context.
 func (c *Context) Float64(key string, value float64) onelog.LoggerContext {
-	c.event.Float64(key, value)
+	c.ev().Float64(key, value)
+	c.record(entry{kind: record.KindFloat64, key: key, num: math.Float64bits(value)})
 
 	return c
 }
 
 // Floats64 adds the field key with f as a []float64 to the logger context.
 func (c *Context) Floats64(key string, value []float64) onelog.LoggerContext {
-	c.event.Floats64(key, value)
+	c.ev().Floats64(key, value)
+	c.record(entry{kind: record.KindFloats64, key: key, val: value})
 
 	return c
 }
 
 // Bool adds the field key with b as a bool to the logger context.
 func (c *Context) Bool(key string, value bool) onelog.LoggerContext {
-	c.event.Bool(key, value)
+	c.ev().Bool(key, value)
+	c.record(entry{kind: record.KindBool, key: key, num: boolBits(value)})
 
 	return c
 }
 
 // Bools adds the field key with b as a []bool to the logger context.
 func (c *Context) Bools(key string, value []bool) onelog.LoggerContext {
-	c.event.Bools(key, value)
+	c.ev().Bools(key, value)
+	c.record(entry{kind: record.KindBools, key: key, val: value})
 
 	return c
 }
 
 // Time adds the field key with t as a time.Time to the logger context.
 func (c *Context) Time(key string, value time.Time) onelog.LoggerContext {
-	c.event.Time(key, value)
+	switch c.enc.TimeFormat {
+	case "":
+		// Format into a stack buffer; zerolog writes byte slices as strings without keeping them.
+		var buf [64]byte
+		c.ev().Bytes(key, value.AppendFormat(buf[:0], time.RFC3339Nano))
+	case onelog.TimeFormatNative:
+		c.ev().Time(key, value)
+	default:
+		c.encoded(key, c.enc.EncodeTime(value))
+	}
+	c.record(entry{kind: record.KindTime, key: key, t: value})
 
 	return c
 }
 
 // Times adds the field key with t as a []time.Time to the logger context.
 func (c *Context) Times(key string, value []time.Time) onelog.LoggerContext {
-	c.event.Times(key, value)
+	c.ev().Interface(key, c.enc.EncodeTimes(value))
+	c.record(entry{kind: record.KindTimes, key: key, val: value})
 
 	return c
 }
 
 // Dur adds the field key with d as a time.Duration to the logger context.
 func (c *Context) Dur(key string, value time.Duration) onelog.LoggerContext {
-	c.event.Dur(key, value)
+	switch c.enc.DurationFormat {
+	case onelog.DurationNanos:
+		c.ev().Int64(key, int64(value))
+	case onelog.DurationNative:
+		c.ev().Dur(key, value)
+	default:
+		c.encoded(key, c.enc.EncodeDuration(value))
+	}
+	c.record(entry{kind: record.KindDur, key: key, num: uint64(value)})
 
 	return c
 }
 
 // Durs adds the field key with d as a []time.Duration to the logger context.
 func (c *Context) Durs(key string, value []time.Duration) onelog.LoggerContext {
-	c.event.Durs(key, value)
+	c.ev().Interface(key, c.enc.EncodeDurations(value))
+	c.record(entry{kind: record.KindDurs, key: key, val: value})
 
 	return c
 }
 
-// TimeDiff adds the field key with begin and end as a time.Time to the logger context.
-func (c *Context) TimeDiff(key string, begin, end time.Time) onelog.LoggerContext {
-	c.event.TimeDiff(key, begin, end)
-
-	return c
+// TimeDiff adds the field key with the duration between t and start to the logger context.
+func (c *Context) TimeDiff(key string, t, start time.Time) onelog.LoggerContext {
+	return c.Dur(key, t.Sub(start))
 }
 
 // IPAddr adds the field key with ip as a net.IP to the logger context.
 func (c *Context) IPAddr(key string, value net.IP) onelog.LoggerContext {
-	c.event.IPAddr(key, value)
+	if len(value) == 0 {
+		c.null(key)
+		return c
+	}
+
+	c.ev().IPAddr(key, value)
+	c.record(entry{kind: record.KindIPAddr, key: key, bytes: value})
 
 	return c
 }
 
 // IPPrefix adds the field key with ip as a net.IPNet to the logger context.
 func (c *Context) IPPrefix(key string, value net.IPNet) onelog.LoggerContext {
-	c.event.IPPrefix(key, value)
+	if len(value.IP) == 0 {
+		c.null(key)
+		return c
+	}
+
+	c.ev().IPPrefix(key, value)
+	c.record(entry{kind: record.KindIPPrefix, key: key, val: value})
 
 	return c
 }
 
 // MACAddr adds the field key with ip as a net.HardwareAddr to the logger context.
 func (c *Context) MACAddr(key string, value net.HardwareAddr) onelog.LoggerContext {
-	c.event.MACAddr(key, value)
+	if len(value) == 0 {
+		c.null(key)
+		return c
+	}
+
+	c.ev().MACAddr(key, value)
+	c.record(entry{kind: record.KindMACAddr, key: key, bytes: value})
 
 	return c
 }
 
-// Err adds the field "error" with err as a error to the logger context.
-func (c *Context) Err(err error) onelog.LoggerContext {
-	c.event.Err(err)
+// Addr adds the field key with val as a netip.Addr to the logger context.
+func (c *Context) Addr(key string, value netip.Addr) onelog.LoggerContext {
+	if !value.IsValid() {
+		c.null(key)
+		return c
+	}
+
+	c.str(key, value.String())
+
+	return c
+}
+
+// Prefix adds the field key with val as a netip.Prefix to the logger context.
+func (c *Context) Prefix(key string, value netip.Prefix) onelog.LoggerContext {
+	if !value.IsValid() {
+		c.null(key)
+		return c
+	}
+
+	c.str(key, value.String())
+
+	return c
+}
+
+// AddrPort adds the field key with val as a netip.AddrPort to the logger context.
+func (c *Context) AddrPort(key string, value netip.AddrPort) onelog.LoggerContext {
+	if !value.IsValid() {
+		c.null(key)
+		return c
+	}
+
+	c.str(key, value.String())
+
+	return c
+}
+
+// URL adds the field key with val as a *url.URL to the logger context. The user information is redacted.
+func (c *Context) URL(key string, value *url.URL) onelog.LoggerContext {
+	if value == nil {
+		c.null(key)
+		return c
+	}
+
+	c.str(key, urlsafe.Redacted(value))
+
+	return c
+}
+
+// BigInt adds the field key with val as a *big.Int to the logger context.
+func (c *Context) BigInt(key string, value *big.Int) onelog.LoggerContext {
+	if value == nil {
+		c.null(key)
+		return c
+	}
+
+	return c.number(key, value.String())
+}
+
+// BigFloat adds the field key with val as a *big.Float to the logger context.
+func (c *Context) BigFloat(key string, value *big.Float) onelog.LoggerContext {
+	if value == nil {
+		c.null(key)
+		return c
+	}
+
+	return c.number(key, numeric.BigFloat(value))
+}
+
+// Decimal adds the field key with val as a decimal number to the logger context.
+func (c *Context) Decimal(key string, value fmt.Stringer) onelog.LoggerContext {
+	if nilsafe.IsNil(value) {
+		c.null(key)
+		return c
+	}
+
+	return c.number(key, value.String())
+}
+
+// number adds the field key with s embedded verbatim if it is a JSON number, or as a string otherwise.
+func (c *Context) number(key, s string) onelog.LoggerContext {
+	if !numeric.IsJSON(s) {
+		c.str(key, s)
+		return c
+	}
+
+	raw := []byte(s)
+	c.ev().RawJSON(key, raw)
+	c.record(entry{kind: record.KindRawJSON, key: key, bytes: raw})
 
 	return c
 }
 
+// Err adds the field "error" with err as a error to the logger context.
+func (c *Context) Err(err error) onelog.LoggerContext {
+	return c.AnErr(zerolog.ErrorFieldName, err)
+}
+
 // Errs adds the field key with errs as a []error to the logger context.
 func (c *Context) Errs(key string, errs []error) onelog.LoggerContext {
-	c.event.Errs(key, errs)
+	c.ev().Interface(key, c.enc.EncodeErrors(errs))
+	c.record(entry{kind: record.KindErrs, key: key, val: errs})
 
 	return c
 }
 
 // AnErr adds the field key with err as a error to the logger context.
 func (c *Context) AnErr(key string, err error) onelog.LoggerContext {
-	c.event.AnErr(key, err)
+	if nilsafe.IsNil(err) {
+		return c
+	}
+
+	if c.enc.ErrorFormat == onelog.ErrorString {
+		c.ev().Str(key, err.Error())
+		c.record(entry{kind: record.KindAnErr, key: key, val: err})
+
+		return c
+	}
+
+	c.encoded(key, c.enc.EncodeError(err))
+	c.record(entry{kind: record.KindAnErr, key: key, val: err})
 
 	return c
 }
 
 // Any adds the field key with val as a arbitrary value to the logger context.
 func (c *Context) Any(key string, value any) onelog.LoggerContext {
-	c.event.Any(key, value)
+	value = nilsafe.Value(value)
+	encoded, _ := c.enc.EncodeValue(value)
+	c.ev().Interface(key, encoded)
+	c.record(entry{kind: record.KindAny, key: key, val: value})
 
 	return c
 }
 
 // Fields adds the fields to the logger context.
 func (c *Context) Fields(fields onelog.Fields) onelog.LoggerContext {
-	c.event.Fields(fields)
+	for _, key := range keys.Sorted(fields) {
+		c.Any(key, fields[key])
+	}
 
 	return c
 }
 
 // Msg sends the LoggerContext with msg to the logger.
 func (c *Context) Msg(msg string) {
-	c.event.Msg(msg)
+	c.ev().Msg(msg)
 	c.reset()
+
+	if c.fatal {
+		onelog.Exit(c.exit)
+	}
 }
 
 // Msgf sends the LoggerContext with formatted msg to the logger.
 func (c *Context) Msgf(format string, v ...any) {
-	c.event.Msgf(format, v...)
+	c.Msg(fmt.Sprintf(format, v...))
+}
+
+// Send sends the LoggerContext without a message to the logger.
+func (c *Context) Send() {
+	c.Msg("")
+}
+
+// Discard abandons the LoggerContext without writing the record.
+func (c *Context) Discard() {
 	c.reset()
 }
+
+// Template returns an immutable snapshot of the level and fields of the LoggerContext. The recorded fields are encoded
+// into a child logger, so that templates cost nothing until they are taken.
+func (c *Context) Template() onelog.Template {
+	logger := *c.logger
+	if c.journal != nil {
+		logger = c.logger.With().EmbedObject(replay{fields: c.journal.fields(), enc: c.enc}).Logger()
+	}
+
+	return &Template{logger: &logger, level: c.level, fatal: c.fatal, exit: c.exit, enc: c.enc}
+}
+
+// Context returns a new LoggerContext holding the level and fields of the template.
+func (t *Template) Context() onelog.LoggerContext {
+	return &Context{
+		logger: t.logger,
+		event:  t.logger.WithLevel(t.level),
+		level:  t.level,
+		fatal:  t.fatal,
+		exit:   t.exit,
+		enc:    t.enc,
+	}
+}
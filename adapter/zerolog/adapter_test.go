@@ -2,7 +2,15 @@ package zerologadapter
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"math"
+	"math/big"
+	"net"
+	"net/netip"
+	"net/url"
 	"os"
 	"testing"
 	"time"
@@ -11,35 +19,32 @@ import (
 
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
-	"github.com/nikoksr/onelog/internal/testutils"
+	"github.com/nikoksr/onelog/internal/race"
+	"github.com/nikoksr/onelog/onelogtest"
 )
 
 // Setting global zerolog settings to make sure the tests are deterministic.
 func TestMain(m *testing.M) {
-	originalTimeFieldFormat := zerolog.TimeFieldFormat
-	originalDurationFieldInteger := zerolog.DurationFieldInteger
-	originalDurationFieldUnit := zerolog.DurationFieldUnit
 	originalMessageFieldName := zerolog.MessageFieldName
 
 	defer func() {
-		zerolog.TimeFieldFormat = originalTimeFieldFormat
-		zerolog.DurationFieldInteger = originalDurationFieldInteger
-		zerolog.DurationFieldUnit = originalDurationFieldUnit
 		zerolog.MessageFieldName = originalMessageFieldName
 	}()
 
-	zerolog.TimeFieldFormat = time.RFC3339Nano
-	zerolog.DurationFieldInteger = true
-	zerolog.DurationFieldUnit = time.Nanosecond
 	zerolog.MessageFieldName = "msg"
 
 	os.Exit(m.Run())
 }
 
 func newAdapter(out io.Writer) onelog.Logger {
+	return newAdapterWithOptions(out)
+}
+
+func newAdapterWithOptions(out io.Writer, opts ...Option) onelog.Logger {
 	logger := zerolog.New(out).With().Timestamp().Logger()
-	return NewAdapter(&logger)
+	return NewAdapter(&logger, opts...)
 }
 
 // TestNewAdapter tests if NewAdapter returns a non-nil *Adapter.
@@ -83,12 +88,145 @@ func TestContexts(t *testing.T) {
 	assert.IsType(t, new(Context), logContext, "the returned context should be of type *Context")
 }
 
-// TestMethods tests if each method returns a non-nil *Context and if the log is written correctly.
-func TestMethods(t *testing.T) {
+// TestConformance tests if the adapter passes the onelog conformance suite.
+func TestConformance(t *testing.T) {
+	t.Parallel()
+
+	onelogtest.Run(t, onelogtest.Config{
+		NewLogger: newAdapter,
+		NewFatalLogger: func(out io.Writer, exit onelog.ExitFunc) onelog.Logger {
+			return newAdapterWithOptions(out, WithExitFunc(exit))
+		},
+		NewEncodingLogger: func(out io.Writer, enc onelog.Encoding) onelog.Logger {
+			return newAdapterWithOptions(out, WithEncoding(enc))
+		},
+	})
+}
+
+// TestProductionEncoding tests if the default encoding renders values the same with zerolog's default time and
+// duration settings, which differ from onelog's.
+func TestProductionEncoding(t *testing.T) {
+	t.Parallel()
+
+	onelogtest.RunEncoding(t, onelogtest.Config{
+		NewLogger: func(out io.Writer) onelog.Logger {
+			logger := zerolog.New(out)
+			return NewAdapter(&logger)
+		},
+	})
+}
+
+// addAllFields adds a field with every typed method to c.
+func addAllFields(c onelog.LoggerContext) onelog.LoggerContext {
+	start := time.Date(2023, 4, 5, 6, 7, 8, 9, time.UTC)
+	_, prefix, _ := net.ParseCIDR("10.0.0.0/8")
+
+	return c.Bytes("bytes", []byte("bytes")).Hex("hex", []byte{0xab}).RawJSON("raw", []byte(`{"a":1}`)).
+		RawJSON("invalid-raw", []byte("{")).Str("str", "value").Strs("strs", []string{"a", "b"}).
+		Stringer("stringer", netip.MustParseAddr("::1")).Stringer("nil-stringer", nil).
+		Stringers("stringers", []fmt.Stringer{netip.MustParseAddr("::1"), nil}).
+		Int("int", -42).Ints("ints", []int{-1}).Int8("int8", -8).Ints8("ints8", []int8{-8}).
+		Int16("int16", -16).Ints16("ints16", []int16{-16}).Int32("int32", -32).Ints32("ints32", []int32{-32}).
+		Int64("int64", -64).Ints64("ints64", []int64{-64}).Uint("uint", 42).Uints("uints", []uint{42}).
+		Uint8("uint8", 8).Uints8("uints8", []uint8{8}).Uint16("uint16", 16).Uints16("uints16", []uint16{16}).
+		Uint32("uint32", 32).Uints32("uints32", []uint32{32}).Uint64("uint64", math.MaxUint64).
+		Uints64("uints64", []uint64{64}).Float32("float32", -1.5).Floats32("floats32", []float32{1.5}).
+		Float64("float64", -2.5).Floats64("floats64", []float64{2.5}).Bool("bool", true).Bool("false", false).
+		Bools("bools", []bool{true}).Time("time", start).Times("times", []time.Time{start}).
+		Dur("dur", -time.Second).Durs("durs", []time.Duration{time.Second}).
+		TimeDiff("diff", start.Add(time.Minute), start).IPAddr("ip", net.IPv4(10, 0, 0, 1)).IPAddr("nil-ip", nil).
+		IPPrefix("prefix", *prefix).MACAddr("mac", net.HardwareAddr{1, 2, 3, 4, 5, 6}).
+		Addr("addr", netip.MustParseAddr("10.0.0.1")).Addr("invalid-addr", netip.Addr{}).
+		Prefix("netip-prefix", netip.MustParsePrefix("10.0.0.0/8")).
+		AddrPort("addr-port", netip.MustParseAddrPort("10.0.0.1:80")).URL("url", &url.URL{Scheme: "https", Host: "a"}).
+		BigInt("big-int", big.NewInt(-7)).BigFloat("big-float", big.NewFloat(1.25)).BigInt("nil-big-int", nil).
+		Err(errors.New("err")).Errs("errs", []error{errors.New("errs"), nil}).AnErr("an-err", errors.New("an")).
+		Any("any", map[string]int{"a": 1}).Any("any-nil", nil).
+		Fields(onelog.Fields{"field": 1, "field-dur": time.Second})
+}
+
+// TestTemplateReplay tests if records of contexts created from a template hold the same fields as records written
+// directly, for every typed method.
+func TestTemplateReplay(t *testing.T) {
+	t.Parallel()
+
+	for name, enc := range map[string]onelog.Encoding{
+		"Default": {},
+		"Custom": {
+			TimeFormat:     onelog.TimeFormatUnix,
+			DurationFormat: onelog.DurationString,
+			ErrorFormat:    onelog.ErrorObject,
+		},
+	} {
+		enc := enc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			buff := new(bytes.Buffer)
+			logger := zerolog.New(buff)
+			adapter := NewAdapter(&logger, WithEncoding(enc))
+
+			addAllFields(adapter.Info()).Msg("record")
+			addAllFields(adapter.Info()).Template().Context().Msg("record")
+
+			lines := bytes.Split(bytes.TrimSpace(buff.Bytes()), []byte("\n"))
+			require.Len(t, lines, 2)
+
+			var direct, replayed map[string]any
+			require.NoError(t, json.Unmarshal(lines[0], &direct))
+			require.NoError(t, json.Unmarshal(lines[1], &replayed))
+			assert.Equal(t, direct, replayed, "replayed fields should be rendered like the original ones")
+		})
+	}
+}
+
+// TestAllocs tests if records allocate no more than the context itself beyond writing them with zerolog directly, if
+// reused contexts do not allocate beyond zerolog at all, and if records of disabled levels do not allocate.
+//
+//nolint:paralleltest // AllocsPerRun counts the allocations of all goroutines
+func TestAllocs(t *testing.T) {
+	if race.Enabled {
+		t.Skip("allocations are not reliable with the race detector")
+	}
+
+	logger := zerolog.New(io.Discard).Level(zerolog.InfoLevel)
+	adapter := NewAdapter(&logger)
+	reused := adapter.Info()
+	start := time.Now()
+	err := errors.New("failed")
+
+	raw := testing.AllocsPerRun(100, func() {
+		logger.Info().Str("str", "value").Int("int", 42).Int64("int64", 1234).Uint("uint", 42).
+			Float64("float", 1.5).Bool("bool", true).Time("time", start).Dur("dur", time.Second).Err(err).
+			Msg("message")
+	})
+
+	addTypedFields := func(c onelog.LoggerContext) onelog.LoggerContext {
+		return c.Str("str", "value").Int("int", 42).Int64("int64", 1234).Uint("uint", 42).Float64("float", 1.5).
+			Bool("bool", true).Time("time", start).Dur("dur", time.Second).Err(err)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() { addTypedFields(adapter.Info()).Msg("message") })
+	assert.LessOrEqual(t, allocs, raw+1, "records should not allocate beyond zerolog and the context")
+
+	allocs = testing.AllocsPerRun(100, func() { addTypedFields(reused).Msg("message") })
+	assert.LessOrEqual(t, allocs, raw, "reused contexts should not allocate beyond zerolog")
+
+	allocs = testing.AllocsPerRun(100, func() { addTypedFields(adapter.Debug()).Msg("message") })
+	assert.Zero(t, allocs, "records of disabled levels should not allocate")
+}
+
+// TestFatalNoExit tests if FatalNoExit writes the log without terminating the process.
+func TestFatalNoExit(t *testing.T) {
 	t.Parallel()
 
 	buff := new(bytes.Buffer)
 	adapter := newAdapter(buff)
 
-	testutils.TestingMethods(t, adapter, buff)
+	fatalWriter, ok := adapter.(onelog.FatalWriter)
+	require.True(t, ok, "the adapter should implement onelog.FatalWriter")
+
+	fatalWriter.FatalNoExit().Str("Test", "Value").Msg("Test message")
+
+	assert.Contains(t, buff.String(), "Test message", "the log should have been written")
 }
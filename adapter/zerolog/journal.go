@@ -0,0 +1,121 @@
+package zerologadapter
+
+import (
+	"math"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/nikoksr/onelog"
+	"github.com/nikoksr/onelog/internal/record"
+)
+
+// maxPooledEntries caps the capacity of journals returned to the pool, so that a single huge record does not pin its
+// memory.
+const maxPooledEntries = 64
+
+// journalPool holds the journals of sent records for reuse.
+var journalPool = sync.Pool{
+	New: func() any { return new(journal) },
+}
+
+// entry is a field recorded by a Context, so that Template can replay it. Strings, byte slices, numbers and times are
+// held in typed slots, so that recording them does not allocate.
+type entry struct {
+	kind  record.Kind
+	key   string
+	str   string
+	bytes []byte
+	num   uint64
+	t     time.Time
+	val   any
+}
+
+// journal is the list of fields added to a Context since its last record.
+type journal []entry
+
+// release clears the journal and returns it to the pool.
+func (j *journal) release() {
+	if cap(*j) > maxPooledEntries {
+		return
+	}
+
+	for i := range *j {
+		(*j)[i] = entry{}
+	}
+	*j = (*j)[:0]
+	journalPool.Put(j)
+}
+
+// fields returns the recorded fields.
+func (j journal) fields() []record.Field {
+	fields := make([]record.Field, len(j))
+	for i, e := range j {
+		fields[i] = e.field()
+	}
+
+	return fields
+}
+
+// field returns the entry as a record.Field holding the value it was added with.
+func (e entry) field() record.Field {
+	f := record.Field{Kind: e.kind, Key: e.key}
+
+	switch e.kind {
+	case record.KindStr:
+		f.Value = e.str
+	case record.KindHex, record.KindRawJSON:
+		f.Value = e.bytes
+	case record.KindIPAddr:
+		f.Value = net.IP(e.bytes)
+	case record.KindMACAddr:
+		f.Value = net.HardwareAddr(e.bytes)
+	case record.KindInt:
+		f.Value = int(int64(e.num))
+	case record.KindInt8:
+		f.Value = int8(int64(e.num))
+	case record.KindInt16:
+		f.Value = int16(int64(e.num))
+	case record.KindInt32:
+		f.Value = int32(int64(e.num))
+	case record.KindInt64:
+		f.Value = int64(e.num)
+	case record.KindUint:
+		f.Value = uint(e.num)
+	case record.KindUint8:
+		f.Value = uint8(e.num)
+	case record.KindUint16:
+		f.Value = uint16(e.num)
+	case record.KindUint32:
+		f.Value = uint32(e.num)
+	case record.KindUint64:
+		f.Value = e.num
+	case record.KindFloat32:
+		f.Value = math.Float32frombits(uint32(e.num))
+	case record.KindFloat64:
+		f.Value = math.Float64frombits(e.num)
+	case record.KindBool:
+		f.Value = e.num != 0
+	case record.KindTime:
+		f.Value = e.t
+	case record.KindDur:
+		f.Value = time.Duration(e.num)
+	default:
+		f.Value = e.val
+	}
+
+	return f
+}
+
+// replay embeds recorded fields into a zerolog.Context by adding them to a Context that writes to the embedding event.
+type replay struct {
+	fields []record.Field
+	enc    onelog.Encoding
+}
+
+// MarshalZerologObject implements zerolog.LogObjectMarshaler.
+func (r replay) MarshalZerologObject(e *zerolog.Event) {
+	record.Apply(&Context{event: e, enc: r.enc, replay: true}, r.fields)
+}
@@ -0,0 +1,4 @@
+// Package async provides a onelog.Logger wrapper that moves the actual writing of records to a background goroutine,
+// so that slow outputs do not stall the callers. Records are buffered in a bounded queue with a configurable
+// overflow policy.
+package async
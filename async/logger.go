@@ -0,0 +1,169 @@
+package async
+
+import (
+	"context"
+
+	"github.com/nikoksr/onelog"
+	"github.com/nikoksr/onelog/internal/record"
+)
+
+// Compile-time check that Logger implements onelog.Logger, onelog.FatalWriter and onelog.Syncer
+var (
+	_ onelog.Logger      = (*Logger)(nil)
+	_ onelog.FatalWriter = (*Logger)(nil)
+	_ onelog.Syncer      = (*Logger)(nil)
+)
+
+// defaultBufferSize is the number of records buffered if no size is configured.
+const defaultBufferSize = 1024
+
+// OverflowPolicy decides what happens to records when the buffer is full.
+type OverflowPolicy int
+
+const (
+	// Block makes callers wait until there is room in the buffer. No records get lost.
+	Block OverflowPolicy = iota
+	// DropNewest drops the record that does not fit into the buffer anymore.
+	DropNewest
+	// DropOldest drops the oldest buffered record to make room for the new one.
+	DropOldest
+)
+
+type (
+	// Logger is a onelog.Logger that snapshots every record and writes it to the wrapped logger on a background
+	// goroutine. Loggers derived using With share the buffer of their parent.
+	//
+	// Fatal records are written synchronously, after all buffered records have been written. Call Close on shutdown to
+	// write the remaining buffered records.
+	Logger struct {
+		logger onelog.Logger
+		queue  *queue
+	}
+
+	// Option configures a Logger.
+	Option func(*options)
+
+	options struct {
+		bufferSize int
+		policy     OverflowPolicy
+	}
+)
+
+// WithBufferSize sets the number of records that can be buffered. The default is 1024.
+func WithBufferSize(size int) Option {
+	return func(o *options) {
+		if size > 0 {
+			o.bufferSize = size
+		}
+	}
+}
+
+// WithOverflowPolicy sets what happens to records when the buffer is full. The default is Block.
+func WithOverflowPolicy(policy OverflowPolicy) Option {
+	return func(o *options) {
+		o.policy = policy
+	}
+}
+
+// New returns a Logger that writes the records to l on a background goroutine. The goroutine runs until Close is
+// called.
+func New(l onelog.Logger, opts ...Option) *Logger {
+	o := &options{
+		bufferSize: defaultBufferSize,
+		policy:     Block,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return &Logger{
+		logger: l,
+		queue:  newQueue(o.bufferSize, o.policy),
+	}
+}
+
+// Flush blocks until all records buffered so far have been written or ctx is done, in which case ctx.Err() is
+// returned.
+func (l *Logger) Flush(ctx context.Context) error {
+	return l.queue.flush(ctx)
+}
+
+// Sync blocks until all records buffered so far have been written and then flushes the wrapped logger, if it
+// implements onelog.Syncer.
+func (l *Logger) Sync() error {
+	_ = l.queue.flush(context.Background()) // Only fails once the context is done
+	return onelog.Sync(l.logger)
+}
+
+// Close writes all buffered records and stops the background goroutine. Records sent after Close are written
+// synchronously.
+func (l *Logger) Close() {
+	l.queue.close()
+}
+
+// Dropped returns the number of records dropped due to the overflow policy.
+func (l *Logger) Dropped() uint64 {
+	return l.queue.dropped.Load()
+}
+
+func (l *Logger) newContext(level onelog.Level) onelog.LoggerContext {
+	return record.NewContext(func(msg string, fields []record.Field) {
+		l.queue.push(entry{
+			logger: l.logger,
+			level:  level,
+			msg:    msg,
+			fields: record.Snapshot(fields),
+		})
+	})
+}
+
+// newSyncContext returns a context that writes its record synchronously once all buffered records have been written.
+func (l *Logger) newSyncContext(newBackendContext func() onelog.LoggerContext) onelog.LoggerContext {
+	return record.NewContext(func(msg string, fields []record.Field) {
+		_ = l.queue.flush(context.Background())
+		record.Apply(newBackendContext(), fields).Msg(msg)
+	})
+}
+
+// With returns the logger with the given fields.
+func (l *Logger) With(fields ...any) onelog.Logger {
+	return &Logger{
+		logger: l.logger.With(fields...),
+		queue:  l.queue,
+	}
+}
+
+// Debug returns a LoggerContext for a debug log. To send the log, use the Msg or Msgf methods.
+func (l *Logger) Debug() onelog.LoggerContext {
+	return l.newContext(onelog.DebugLevel)
+}
+
+// Info returns a LoggerContext for an info log. To send the log, use the Msg or Msgf methods.
+func (l *Logger) Info() onelog.LoggerContext {
+	return l.newContext(onelog.InfoLevel)
+}
+
+// Warn returns a LoggerContext for a warn log. To send the log, use the Msg or Msgf methods.
+func (l *Logger) Warn() onelog.LoggerContext {
+	return l.newContext(onelog.WarnLevel)
+}
+
+// Error returns a LoggerContext for an error log. To send the log, use the Msg or Msgf methods.
+func (l *Logger) Error() onelog.LoggerContext {
+	return l.newContext(onelog.ErrorLevel)
+}
+
+// Fatal returns a LoggerContext for a fatal log. Once sent, all buffered records are written before the fatal record
+// is written synchronously.
+func (l *Logger) Fatal() onelog.LoggerContext {
+	return l.newSyncContext(l.logger.Fatal)
+}
+
+// FatalNoExit returns a LoggerContext for a fatal log that does not exit once sent.
+func (l *Logger) FatalNoExit() onelog.LoggerContext {
+	return l.newSyncContext(func() onelog.LoggerContext {
+		ctx, _ := onelog.FatalNoExit(l.logger)
+		return ctx
+	})
+}
@@ -0,0 +1,267 @@
+package async
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikoksr/onelog/internal/testutil"
+)
+
+// gateWriter blocks every write until the gate is opened.
+type gateWriter struct {
+	out     io.Writer
+	started chan struct{}
+	gate    chan struct{}
+	once    sync.Once
+}
+
+func newGateWriter(out io.Writer) *gateWriter {
+	return &gateWriter{out: out, started: make(chan struct{}), gate: make(chan struct{})}
+}
+
+func (w *gateWriter) Write(p []byte) (int, error) {
+	w.once.Do(func() { close(w.started) })
+	<-w.gate
+
+	return w.out.Write(p)
+}
+
+func parseMessages(t *testing.T, s string) []string {
+	t.Helper()
+
+	var messages []string
+	for _, line := range strings.Split(strings.TrimSpace(s), "\n") {
+		if line == "" {
+			continue
+		}
+
+		result := make(map[string]any)
+		require.NoError(t, json.Unmarshal([]byte(line), &result), "the log should be valid json")
+		messages = append(messages, result["message"].(string))
+	}
+
+	return messages
+}
+
+// TestLoggerOrder tests if records are written in order, with all their fields, once flushed.
+func TestLoggerOrder(t *testing.T) {
+	t.Parallel()
+
+	buff := new(testutil.SyncBuffer)
+	logger := New(testutil.NewAdapter(buff))
+	defer logger.Close()
+
+	derived := logger.With("test-with", "test")
+	for i := 0; i < 100; i++ {
+		derived.Info().Int("i", i).Msgf("record %d", i)
+	}
+
+	require.NoError(t, logger.Flush(context.Background()))
+
+	messages := parseMessages(t, buff.String())
+	require.Len(t, messages, 100, "all records should be written")
+	for i, msg := range messages {
+		assert.Equal(t, "record "+strconv.Itoa(i), msg, "records should keep their order")
+	}
+	assert.Contains(t, buff.String(), `"i":99`, "records should contain their fields")
+	assert.Contains(t, buff.String(), `"test-with":"test"`, "records should contain inherited fields")
+}
+
+// TestLoggerSnapshot tests if records are not affected by changes to the values made after Msg was called.
+func TestLoggerSnapshot(t *testing.T) {
+	t.Parallel()
+
+	buff := new(testutil.SyncBuffer)
+	out := newGateWriter(buff)
+	logger := New(testutil.NewAdapter(out))
+	defer logger.Close()
+
+	values := []string{"a", "b"}
+	logger.Info().Strs("values", values).Msg("test")
+	values[0] = "changed"
+
+	close(out.gate)
+	require.NoError(t, logger.Flush(context.Background()))
+
+	assert.Contains(t, buff.String(), `"values":["a","b"]`, "records should be snapshotted when sent")
+}
+
+// TestLoggerDropNewest tests if new records are dropped when the buffer is full.
+func TestLoggerDropNewest(t *testing.T) {
+	t.Parallel()
+
+	buff := new(testutil.SyncBuffer)
+	out := newGateWriter(buff)
+	logger := New(testutil.NewAdapter(out), WithBufferSize(2), WithOverflowPolicy(DropNewest))
+	defer logger.Close()
+
+	logger.Info().Msg("0")
+	<-out.started // The worker holds record 0, the buffer is empty
+	for i := 1; i <= 4; i++ {
+		logger.Info().Msg(strconv.Itoa(i))
+	}
+
+	close(out.gate)
+	require.NoError(t, logger.Flush(context.Background()))
+
+	assert.Equal(t, []string{"0", "1", "2"}, parseMessages(t, buff.String()))
+	assert.EqualValues(t, 2, logger.Dropped())
+}
+
+// TestLoggerDropOldest tests if the oldest records are dropped when the buffer is full.
+func TestLoggerDropOldest(t *testing.T) {
+	t.Parallel()
+
+	buff := new(testutil.SyncBuffer)
+	out := newGateWriter(buff)
+	logger := New(testutil.NewAdapter(out), WithBufferSize(2), WithOverflowPolicy(DropOldest))
+	defer logger.Close()
+
+	logger.Info().Msg("0")
+	<-out.started
+	for i := 1; i <= 4; i++ {
+		logger.Info().Msg(strconv.Itoa(i))
+	}
+
+	close(out.gate)
+	require.NoError(t, logger.Flush(context.Background()))
+
+	assert.Equal(t, []string{"0", "3", "4"}, parseMessages(t, buff.String()))
+	assert.EqualValues(t, 2, logger.Dropped())
+}
+
+// TestLoggerBlock tests if callers wait for room in the buffer instead of dropping records.
+func TestLoggerBlock(t *testing.T) {
+	t.Parallel()
+
+	buff := new(testutil.SyncBuffer)
+	out := newGateWriter(buff)
+	logger := New(testutil.NewAdapter(out), WithBufferSize(1))
+	defer logger.Close()
+
+	logger.Info().Msg("0")
+	<-out.started
+	logger.Info().Msg("1")
+
+	sent := make(chan struct{})
+	go func() {
+		logger.Info().Msg("2")
+		close(sent)
+	}()
+
+	select {
+	case <-sent:
+		t.Fatal("the caller should block while the buffer is full")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(out.gate)
+	<-sent
+	require.NoError(t, logger.Flush(context.Background()))
+
+	assert.Equal(t, []string{"0", "1", "2"}, parseMessages(t, buff.String()))
+	assert.Zero(t, logger.Dropped())
+}
+
+// TestLoggerFlushContext tests if Flush gives up once its context is done.
+func TestLoggerFlushContext(t *testing.T) {
+	t.Parallel()
+
+	out := newGateWriter(io.Discard)
+	logger := New(testutil.NewAdapter(out))
+	defer logger.Close()
+	defer close(out.gate)
+
+	logger.Info().Msg("test")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, logger.Flush(ctx), context.DeadlineExceeded)
+}
+
+// TestLoggerClose tests if Close writes all buffered records and records sent afterwards are written synchronously.
+func TestLoggerClose(t *testing.T) {
+	t.Parallel()
+
+	buff := new(testutil.SyncBuffer)
+	logger := New(testutil.NewAdapter(buff))
+
+	for i := 0; i < 10; i++ {
+		logger.Info().Msg(strconv.Itoa(i))
+	}
+
+	logger.Close()
+	assert.Len(t, parseMessages(t, buff.String()), 10, "all buffered records should be written on close")
+
+	logger.Close() // Closing twice should be harmless
+	logger.Info().Msg("after close")
+	assert.Contains(t, buff.String(), "after close", "records sent after close should be written synchronously")
+}
+
+// TestLoggerSync tests if Sync writes all buffered records before it returns.
+func TestLoggerSync(t *testing.T) {
+	t.Parallel()
+
+	buff := new(testutil.SyncBuffer)
+	logger := New(testutil.NewAdapter(buff))
+	defer logger.Close()
+
+	for i := 0; i < 10; i++ {
+		logger.Info().Msg(strconv.Itoa(i))
+	}
+
+	require.NoError(t, logger.Sync())
+	assert.Len(t, parseMessages(t, buff.String()), 10, "all buffered records should be written on sync")
+}
+
+// TestLoggerFatalNoExit tests if fatal records are written synchronously after all buffered records.
+func TestLoggerFatalNoExit(t *testing.T) {
+	t.Parallel()
+
+	buff := new(testutil.SyncBuffer)
+	logger := New(testutil.NewAdapter(buff))
+	defer logger.Close()
+
+	for i := 0; i < 10; i++ {
+		logger.Info().Msg(strconv.Itoa(i))
+	}
+	logger.FatalNoExit().Str("test", "value").Msg("fatal")
+
+	messages := parseMessages(t, buff.String())
+	require.Len(t, messages, 11, "buffered records should be written before the fatal record")
+	assert.Equal(t, "fatal", messages[10])
+	assert.Contains(t, buff.String(), `"level":"fatal"`)
+}
+
+// TestLoggerConcurrent tests if the logger is safe for concurrent use.
+func TestLoggerConcurrent(t *testing.T) {
+	t.Parallel()
+
+	buff := new(testutil.SyncBuffer)
+	logger := New(testutil.NewAdapter(buff), WithBufferSize(8))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				logger.Info().Int("j", j).Msg("test")
+			}
+		}()
+	}
+	wg.Wait()
+	logger.Close()
+
+	assert.Len(t, parseMessages(t, buff.String()), 400, "no record should be lost")
+}
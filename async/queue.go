@@ -0,0 +1,157 @@
+package async
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/nikoksr/onelog"
+	"github.com/nikoksr/onelog/internal/record"
+)
+
+// entry is an immutable snapshot of a record waiting to be written.
+type entry struct {
+	logger onelog.Logger
+	level  onelog.Level
+	msg    string
+	fields []record.Field
+}
+
+// write replays the entry onto its logger.
+func (e *entry) write() {
+	record.Apply(onelog.AtLevel(e.logger, e.level), e.fields).Msg(e.msg)
+}
+
+// queue is a bounded ring buffer of entries drained by a single background goroutine.
+type queue struct {
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+
+	entries []entry
+	head    int
+	size    int
+	policy  OverflowPolicy
+
+	writing bool          // Whether the worker is currently writing an entry
+	drained chan struct{} // Closed once the queue is drained; created on demand by flush
+	closed  bool
+	done    chan struct{} // Closed once the worker has exited
+
+	dropped atomic.Uint64
+}
+
+func newQueue(size int, policy OverflowPolicy) *queue {
+	q := &queue{
+		entries: make([]entry, size),
+		policy:  policy,
+		done:    make(chan struct{}),
+	}
+	q.notEmpty = sync.NewCond(&q.mu)
+	q.notFull = sync.NewCond(&q.mu)
+
+	go q.run()
+
+	return q
+}
+
+// push enqueues e according to the overflow policy. Once the queue is closed, e is written synchronously instead.
+func (q *queue) push(e entry) {
+	q.mu.Lock()
+
+	for q.size == len(q.entries) && !q.closed {
+		switch q.policy {
+		case DropNewest:
+			q.mu.Unlock()
+			q.dropped.Add(1)
+
+			return
+		case DropOldest:
+			q.entries[q.head] = entry{}
+			q.head = (q.head + 1) % len(q.entries)
+			q.size--
+			q.dropped.Add(1)
+		default:
+			q.notFull.Wait()
+		}
+	}
+
+	if q.closed {
+		q.mu.Unlock()
+		e.write()
+
+		return
+	}
+
+	q.entries[(q.head+q.size)%len(q.entries)] = e
+	q.size++
+	q.notEmpty.Signal()
+	q.mu.Unlock()
+}
+
+// run writes entries until the queue is closed and drained.
+func (q *queue) run() {
+	defer close(q.done)
+
+	q.mu.Lock()
+	for {
+		for q.size == 0 && !q.closed {
+			q.notEmpty.Wait()
+		}
+		if q.size == 0 {
+			q.mu.Unlock()
+			return // Closed and drained
+		}
+
+		e := q.entries[q.head]
+		q.entries[q.head] = entry{}
+		q.head = (q.head + 1) % len(q.entries)
+		q.size--
+		q.writing = true
+		q.notFull.Signal()
+		q.mu.Unlock()
+
+		e.write()
+
+		q.mu.Lock()
+		q.writing = false
+		if q.size == 0 && q.drained != nil {
+			close(q.drained)
+			q.drained = nil
+		}
+	}
+}
+
+// flush blocks until all entries enqueued so far have been written or ctx is done.
+func (q *queue) flush(ctx context.Context) error {
+	q.mu.Lock()
+	if q.size == 0 && !q.writing {
+		q.mu.Unlock()
+		return nil
+	}
+	if q.drained == nil {
+		q.drained = make(chan struct{})
+	}
+	drained := q.drained
+	q.mu.Unlock()
+
+	select {
+	case <-drained:
+		return nil
+	case <-q.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// close stops accepting entries, waits until all buffered entries have been written and stops the worker.
+func (q *queue) close() {
+	q.mu.Lock()
+	q.closed = true
+	q.notEmpty.Broadcast()
+	q.notFull.Broadcast()
+	q.mu.Unlock()
+
+	<-q.done
+}
@@ -0,0 +1,343 @@
+package onelog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Compile-time check that AtomicLevel and Levels implement LevelEnabler and http.Handler
+var (
+	_ LevelEnabler = (*AtomicLevel)(nil)
+	_ http.Handler = (*AtomicLevel)(nil)
+	_ http.Handler = (*Levels)(nil)
+)
+
+// AtomicLevel is a minimum level that can be changed at runtime, safely for concurrent use. Pass it to NewLevelFilter
+// to adjust the verbosity of a logger without rebuilding it.
+type AtomicLevel struct {
+	level atomic.Int32
+}
+
+// NewAtomicLevel returns an AtomicLevel set to level.
+func NewAtomicLevel(level Level) *AtomicLevel {
+	a := new(AtomicLevel)
+	a.SetLevel(level)
+
+	return a
+}
+
+// Level returns the current minimum level.
+func (a *AtomicLevel) Level() Level {
+	return Level(a.level.Load())
+}
+
+// SetLevel changes the minimum level.
+func (a *AtomicLevel) SetLevel(level Level) {
+	a.level.Store(int32(level))
+}
+
+// Enabled reports whether level is at least as severe as the current minimum level.
+func (a *AtomicLevel) Enabled(level Level) bool {
+	return a.Level().Enabled(level)
+}
+
+// maxLevelPayloadSize limits the size of the bodies accepted by the HTTP handlers.
+const maxLevelPayloadSize = 64 << 10
+
+// levelPayload is the JSON representation of a level used by the HTTP handlers.
+type levelPayload struct {
+	Level     *Level            `json:"level,omitempty"`
+	Overrides map[string]*Level `json:"overrides,omitempty"`
+}
+
+// ServeHTTP reads the level on GET and changes it on PUT. Both use the JSON body {"level":"info"}.
+func (a *AtomicLevel) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var payload levelPayload
+		if err := decodeLevelPayload(w, r, &payload); err != nil {
+			writeLevelDecodeError(w, err)
+			return
+		}
+		if payload.Level == nil {
+			writeLevelError(w, http.StatusBadRequest, errors.New("missing level"))
+			return
+		}
+		a.SetLevel(*payload.Level)
+	default:
+		writeLevelError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed, use GET or PUT", r.Method))
+		return
+	}
+
+	level := a.Level()
+	writeLevelPayload(w, levelPayload{Level: &level})
+}
+
+// Levels holds a default minimum level and per-name overrides that can be changed at runtime, safely for concurrent
+// use. Names are hierarchical: the override of "db" applies to "db.postgres" too, unless "db.postgres" has its own.
+type Levels struct {
+	mu    sync.Mutex // Serializes writers; readers load the state without locking
+	state atomic.Pointer[levelsState]
+}
+
+// levelsState is an immutable snapshot of Levels. Changes replace it as a whole, so that readers never see a change
+// half-applied.
+type levelsState struct {
+	defaultLevel Level
+	overrides    map[string]Level
+}
+
+// NewLevels returns Levels with the given default level and no overrides.
+func NewLevels(defaultLevel Level) *Levels {
+	lv := new(Levels)
+	lv.state.Store(&levelsState{defaultLevel: defaultLevel, overrides: map[string]Level{}})
+
+	return lv
+}
+
+// DefaultLevel returns the level used for names without an override.
+func (lv *Levels) DefaultLevel() Level {
+	return lv.state.Load().defaultLevel
+}
+
+// SetDefaultLevel changes the level used for names without an override.
+func (lv *Levels) SetDefaultLevel(level Level) {
+	lv.update(func(state *levelsState) {
+		state.defaultLevel = level
+	})
+}
+
+// Level returns the minimum level of the given name: its own override, the override of its closest parent or the
+// default level.
+func (lv *Levels) Level(name string) Level {
+	state := lv.state.Load()
+
+	for name != "" {
+		if level, ok := state.overrides[name]; ok {
+			return level
+		}
+
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+
+	return state.defaultLevel
+}
+
+// Overrides returns a copy of the per-name overrides.
+func (lv *Levels) Overrides() map[string]Level {
+	return lv.state.Load().copyOverrides()
+}
+
+// copyOverrides returns a copy of the overrides of the snapshot.
+func (s *levelsState) copyOverrides() map[string]Level {
+	result := make(map[string]Level, len(s.overrides))
+	for name, level := range s.overrides {
+		result[name] = level
+	}
+
+	return result
+}
+
+// SetLevel sets the override of a name.
+func (lv *Levels) SetLevel(name string, level Level) {
+	lv.update(func(state *levelsState) {
+		state.overrides[name] = level
+	})
+}
+
+// Unset removes the override of a name, so that it falls back to its parent or the default level again.
+func (lv *Levels) Unset(name string) {
+	lv.update(func(state *levelsState) {
+		delete(state.overrides, name)
+	})
+}
+
+// update replaces the state by a modified copy.
+func (lv *Levels) update(modify func(state *levelsState)) {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+
+	current := lv.state.Load()
+	state := &levelsState{defaultLevel: current.defaultLevel, overrides: current.copyOverrides()}
+	modify(state)
+	lv.state.Store(state)
+}
+
+// Set applies a comma-separated list of levels, such as "info,db=debug,http=warn". An entry without a name sets the
+// default level. Either all entries are applied or, if one of them is invalid, none.
+func (lv *Levels) Set(spec string) error {
+	var defaultLevel Level
+	var hasDefault bool
+	overrides := make(map[string]Level)
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, levelName, found := strings.Cut(entry, "=")
+		if !found {
+			levelName, name = name, ""
+		}
+
+		level, err := ParseLevel(levelName)
+		if err != nil {
+			return fmt.Errorf("invalid entry %q: %w", entry, err)
+		}
+
+		name = strings.TrimSpace(name)
+		if found && name == "" {
+			return fmt.Errorf("invalid entry %q: missing name", entry)
+		}
+
+		if name == "" {
+			defaultLevel, hasDefault = level, true
+		} else {
+			overrides[name] = level
+		}
+	}
+
+	lv.update(func(state *levelsState) {
+		if hasDefault {
+			state.defaultLevel = defaultLevel
+		}
+		for name, level := range overrides {
+			state.overrides[name] = level
+		}
+	})
+
+	return nil
+}
+
+// String returns the levels in the format accepted by Set, with the overrides sorted by name.
+func (lv *Levels) String() string {
+	state := lv.state.Load()
+	overrides := state.overrides
+
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	entries := make([]string, 0, len(names)+1)
+	entries = append(entries, state.defaultLevel.String())
+	for _, name := range names {
+		entries = append(entries, name+"="+overrides[name].String())
+	}
+
+	return strings.Join(entries, ",")
+}
+
+// Enabler returns a LevelEnabler for the given name that follows all changes to the levels.
+func (lv *Levels) Enabler(name string) LevelEnabler {
+	return namedEnabler{levels: lv, name: name}
+}
+
+// Logger returns l filtered by the minimum level of the given name.
+func (lv *Levels) Logger(l Logger, name string) Logger {
+	return NewLevelFilter(l, lv.Enabler(name))
+}
+
+// ServeHTTP reads the levels on GET and changes them on PUT. Both use the JSON body
+// {"level":"info","overrides":{"db":"debug"}}. On PUT, both keys are optional and an override set to null is removed.
+func (lv *Levels) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var payload levelPayload
+		if err := decodeLevelPayload(w, r, &payload); err != nil {
+			writeLevelDecodeError(w, err)
+			return
+		}
+
+		for name := range payload.Overrides {
+			if strings.TrimSpace(name) == "" {
+				writeLevelError(w, http.StatusBadRequest, errors.New("invalid override: missing name"))
+				return
+			}
+		}
+
+		// The whole body has been validated, so apply it in a single step
+		lv.update(func(state *levelsState) {
+			if payload.Level != nil {
+				state.defaultLevel = *payload.Level
+			}
+			for name, level := range payload.Overrides {
+				if level == nil {
+					delete(state.overrides, name)
+				} else {
+					state.overrides[name] = *level
+				}
+			}
+		})
+	default:
+		writeLevelError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed, use GET or PUT", r.Method))
+		return
+	}
+
+	state := lv.state.Load()
+	payload := levelPayload{Level: &state.defaultLevel, Overrides: make(map[string]*Level, len(state.overrides))}
+	for name, override := range state.overrides {
+		override := override
+		payload.Overrides[name] = &override
+	}
+
+	writeLevelPayload(w, payload)
+}
+
+// namedEnabler enables levels according to the minimum level of a name.
+type namedEnabler struct {
+	levels *Levels
+	name   string
+}
+
+// Enabled reports whether level is at least as severe as the minimum level of the name.
+func (e namedEnabler) Enabled(level Level) bool {
+	return e.levels.Level(e.name).Enabled(level)
+}
+
+func decodeLevelPayload(w http.ResponseWriter, r *http.Request, payload *levelPayload) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxLevelPayloadSize))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(payload); err != nil {
+		return fmt.Errorf("decode request body: %w", err)
+	}
+
+	return nil
+}
+
+func writeLevelPayload(w http.ResponseWriter, payload levelPayload) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+// writeLevelDecodeError reports a body that could not be decoded, distinguishing bodies that are too large.
+func writeLevelDecodeError(w http.ResponseWriter, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		writeLevelError(w, http.StatusRequestEntityTooLarge, err)
+		return
+	}
+
+	writeLevelError(w, http.StatusBadRequest, err)
+}
+
+func writeLevelError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,186 @@
+package onelog_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikoksr/onelog"
+	"github.com/nikoksr/onelog/internal/testutil"
+)
+
+// TestAtomicLevel tests if a level filter follows changes to an atomic level.
+func TestAtomicLevel(t *testing.T) {
+	t.Parallel()
+
+	buff := new(bytes.Buffer)
+	level := onelog.NewAtomicLevel(onelog.ErrorLevel)
+	logger := onelog.NewLevelFilter(testutil.NewAdapter(buff), level)
+
+	logger.Info().Msg("dropped")
+	assert.Zero(t, buff.Len(), "records below the minimum level should be dropped")
+
+	level.SetLevel(onelog.DebugLevel)
+	logger.Debug().Msg("written")
+
+	assert.Equal(t, onelog.DebugLevel, level.Level())
+	assert.Len(t, testutil.ParseRecords(t, buff.String()), 1, "records should be written once the level is lowered")
+}
+
+// TestLevelsOverrides tests if per-name overrides, including the ones of parent names, take precedence over the
+// default level.
+func TestLevelsOverrides(t *testing.T) {
+	t.Parallel()
+
+	levels := onelog.NewLevels(onelog.InfoLevel)
+	require.NoError(t, levels.Set("warn, db=debug,http=error"))
+
+	assert.Equal(t, onelog.WarnLevel, levels.DefaultLevel())
+	assert.Equal(t, onelog.WarnLevel, levels.Level("cache"))
+	assert.Equal(t, onelog.DebugLevel, levels.Level("db"))
+	assert.Equal(t, onelog.DebugLevel, levels.Level("db.postgres"), "children should inherit the override of parents")
+	assert.Equal(t, onelog.ErrorLevel, levels.Level("http"))
+	assert.Equal(t, "warn,db=debug,http=error", levels.String())
+
+	levels.SetLevel("db.postgres", onelog.ErrorLevel)
+	assert.Equal(t, onelog.ErrorLevel, levels.Level("db.postgres"))
+
+	levels.Unset("db")
+	assert.Equal(t, onelog.WarnLevel, levels.Level("db"))
+
+	buff := new(bytes.Buffer)
+	logger := levels.Logger(testutil.NewAdapter(buff), "http")
+	logger.Warn().Msg("dropped")
+	levels.SetLevel("http", onelog.DebugLevel)
+	logger.Debug().Msg("written")
+	assert.Len(t, testutil.ParseRecords(t, buff.String()), 1, "filtered loggers should follow changes to the overrides")
+}
+
+// TestLevelsSetInvalid tests if invalid specs are rejected without applying any of their entries.
+func TestLevelsSetInvalid(t *testing.T) {
+	t.Parallel()
+
+	levels := onelog.NewLevels(onelog.InfoLevel)
+
+	assert.ErrorContains(t, levels.Set("db=debug,http=loud"), `invalid entry "http=loud"`)
+	assert.ErrorContains(t, levels.Set("=debug"), "missing name")
+	assert.Empty(t, levels.Overrides(), "no entry of an invalid spec should be applied")
+}
+
+// TestParseLevel tests if levels are parsed from their names.
+func TestParseLevel(t *testing.T) {
+	t.Parallel()
+
+	for _, level := range []onelog.Level{
+		onelog.DebugLevel, onelog.InfoLevel, onelog.WarnLevel, onelog.ErrorLevel, onelog.FatalLevel,
+	} {
+		parsed, err := onelog.ParseLevel(strings.ToUpper(level.String()))
+		require.NoError(t, err)
+		assert.Equal(t, level, parsed)
+	}
+
+	parsed, err := onelog.ParseLevel("warning")
+	require.NoError(t, err)
+	assert.Equal(t, onelog.WarnLevel, parsed)
+
+	_, err = onelog.ParseLevel("loud")
+	assert.Error(t, err)
+}
+
+func serve(t *testing.T, handler http.Handler, method, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(method, "/level", strings.NewReader(body)))
+
+	return recorder
+}
+
+// TestAtomicLevelServeHTTP tests if the level can be read and changed via HTTP.
+func TestAtomicLevelServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	level := onelog.NewAtomicLevel(onelog.InfoLevel)
+
+	resp := serve(t, level, http.MethodGet, "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"level":"info"}`, resp.Body.String())
+
+	resp = serve(t, level, http.MethodPut, `{"level":"debug"}`)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"level":"debug"}`, resp.Body.String())
+	assert.Equal(t, onelog.DebugLevel, level.Level())
+
+	resp = serve(t, level, http.MethodPut, `{"level":"loud"}`)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Contains(t, resp.Body.String(), "unknown level")
+
+	resp = serve(t, level, http.MethodPut, `{}`)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	resp = serve(t, level, http.MethodPut, `{"level":"info"`+strings.Repeat(" ", 1<<20)+`}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code, "oversized bodies should be rejected")
+
+	resp = serve(t, level, http.MethodPost, `{"level":"info"}`)
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+	assert.Equal(t, onelog.DebugLevel, level.Level(), "failed requests should not change the level")
+}
+
+// TestLevelsServeHTTP tests if the default level and overrides can be read and changed via HTTP.
+func TestLevelsServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	levels := onelog.NewLevels(onelog.InfoLevel)
+	levels.SetLevel("db", onelog.DebugLevel)
+
+	resp := serve(t, levels, http.MethodGet, "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"level":"info","overrides":{"db":"debug"}}`, resp.Body.String())
+
+	resp = serve(t, levels, http.MethodPut, `{"overrides":{"db":null,"http":"warn"}}`)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"level":"info","overrides":{"http":"warn"}}`, resp.Body.String())
+
+	resp = serve(t, levels, http.MethodPut, `{"level":"error","unknown":true}`)
+	assert.Equal(t, http.StatusBadRequest, resp.Code, "unknown keys should be rejected")
+	assert.Equal(t, onelog.InfoLevel, levels.DefaultLevel())
+
+	resp = serve(t, levels, http.MethodPut, `{"level":"error","overrides":{"db":"debug","http":"loud"}}`)
+	assert.Equal(t, http.StatusBadRequest, resp.Code, "invalid levels should be rejected")
+	resp = serve(t, levels, http.MethodPut, `{"level":"error","overrides":{"db":"debug","":"warn"}}`)
+	assert.Equal(t, http.StatusBadRequest, resp.Code, "overrides without a name should be rejected")
+	resp = serve(t, levels, http.MethodPut, `{"level":"error"`+strings.Repeat(" ", 1<<20)+`}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code, "oversized bodies should be rejected")
+	assert.Equal(t, "info,http=warn", levels.String(), "failed requests should not change any level")
+}
+
+// TestLevelsConcurrent tests if levels can be changed while being read.
+func TestLevelsConcurrent(t *testing.T) {
+	t.Parallel()
+
+	levels := onelog.NewLevels(onelog.InfoLevel)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				levels.SetLevel("db", onelog.Level(j%5))
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_ = levels.Enabler("db.postgres").Enabled(onelog.InfoLevel)
+			}
+		}()
+	}
+	wg.Wait()
+}
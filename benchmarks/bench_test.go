@@ -0,0 +1,185 @@
+package benchmarks
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/exp/slog"
+
+	"github.com/nikoksr/onelog"
+)
+
+// BenchmarkDisabledLevel measures records below the minimum level, which should be close to free.
+func BenchmarkDisabledLevel(b *testing.B) {
+	zl, zp, sl := newZerolog(), newZap(), newSlog()
+	sugar := zp.Sugar()
+
+	runRaw(b, "zerolog", func() {
+		zl.Debug().Str("str", "value").Int("int", 42).Msg(message)
+	})
+	runRaw(b, "zap", func() {
+		zp.Debug(message, zap.String("str", "value"), zap.Int("int", 42))
+	})
+	runRaw(b, "zap-sugar", func() {
+		sugar.Debugw(message, "str", "value", "int", 42)
+	})
+	runRaw(b, "slog", func() {
+		sl.Debug(message, slog.String("str", "value"), slog.Int("int", 42))
+	})
+	runAdapters(b, func(l onelog.Logger) {
+		l.Debug().Str("str", "value").Int("int", 42).Msg(message)
+	})
+}
+
+// BenchmarkTenFields measures a record with ten typed fields.
+func BenchmarkTenFields(b *testing.B) {
+	zl, zp, sl := newZerolog(), newZap(), newSlog()
+	sugar := zp.Sugar()
+
+	runRaw(b, "zerolog", func() {
+		zl.Info().
+			Str("str", "value").Int("int", 42).Int64("int64", 42).Float64("float", 4.2).Bool("bool", true).
+			Time("time", timeExample).Dur("dur", time.Second).Uint("uint", 42).Str("other", "value").Err(errExample).
+			Msg(message)
+	})
+	runRaw(b, "zap", func() {
+		zp.Info(message,
+			zap.String("str", "value"), zap.Int("int", 42), zap.Int64("int64", 42), zap.Float64("float", 4.2),
+			zap.Bool("bool", true), zap.Time("time", timeExample), zap.Duration("dur", time.Second),
+			zap.Uint("uint", 42), zap.String("other", "value"), zap.Error(errExample),
+		)
+	})
+	runRaw(b, "zap-sugar", func() {
+		sugar.Infow(message,
+			"str", "value", "int", 42, "int64", int64(42), "float", 4.2, "bool", true, "time", timeExample,
+			"dur", time.Second, "uint", uint(42), "other", "value", "error", errExample,
+		)
+	})
+	runRaw(b, "slog", func() {
+		sl.Info(message,
+			slog.String("str", "value"), slog.Int("int", 42), slog.Int64("int64", 42), slog.Float64("float", 4.2),
+			slog.Bool("bool", true), slog.Time("time", timeExample), slog.Duration("dur", time.Second),
+			slog.Uint64("uint", 42), slog.String("other", "value"), slog.Any("error", errExample),
+		)
+	})
+	runAdapters(b, func(l onelog.Logger) {
+		l.Info().
+			Str("str", "value").Int("int", 42).Int64("int64", 42).Float64("float", 4.2).Bool("bool", true).
+			Time("time", timeExample).Dur("dur", time.Second).Uint("uint", 42).Str("other", "value").Err(errExample).
+			Msg(message)
+	})
+}
+
+// BenchmarkWithContext measures a record without fields of its own, written by a logger with ten fields added using
+// With.
+func BenchmarkWithContext(b *testing.B) {
+	zl := newZerolog().With().Fields(withFields).Logger()
+	zp := newZap().Sugar().With(withFields...).Desugar()
+	sugar := newZap().Sugar().With(withFields...)
+	sl := newSlog().With(withFields...)
+
+	runRaw(b, "zerolog", func() {
+		zl.Info().Msg(message)
+	})
+	runRaw(b, "zap", func() {
+		zp.Info(message)
+	})
+	runRaw(b, "zap-sugar", func() {
+		sugar.Infow(message)
+	})
+	runRaw(b, "slog", func() {
+		sl.Info(message)
+	})
+
+	for _, adapter := range adapters() {
+		logger := adapter.logger.With(withFields...)
+		b.Run(adapter.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				logger.Info().Msg(message)
+			}
+		})
+	}
+}
+
+// BenchmarkFieldsMap measures a record with fields passed as a map.
+func BenchmarkFieldsMap(b *testing.B) {
+	zl, zp, sl := newZerolog(), newZap(), newSlog()
+	sugar := zp.Sugar()
+
+	runRaw(b, "zerolog", func() {
+		zl.Info().Fields(map[string]any(fieldsExample)).Msg(message)
+	})
+	runRaw(b, "zap", func() {
+		fields := make([]zap.Field, 0, len(fieldsExample))
+		for key, value := range fieldsExample {
+			fields = append(fields, zap.Any(key, value))
+		}
+		zp.Info(message, fields...)
+	})
+	runRaw(b, "zap-sugar", func() {
+		keysAndValues := make([]any, 0, 2*len(fieldsExample))
+		for key, value := range fieldsExample {
+			keysAndValues = append(keysAndValues, key, value)
+		}
+		sugar.Infow(message, keysAndValues...)
+	})
+	runRaw(b, "slog", func() {
+		attrs := make([]any, 0, len(fieldsExample))
+		for key, value := range fieldsExample {
+			attrs = append(attrs, slog.Any(key, value))
+		}
+		sl.Info(message, attrs...)
+	})
+	runAdapters(b, func(l onelog.Logger) {
+		l.Info().Fields(fieldsExample).Msg(message)
+	})
+}
+
+// BenchmarkAnyStruct measures a record with a struct passed as an arbitrary value.
+func BenchmarkAnyStruct(b *testing.B) {
+	zl, zp, sl := newZerolog(), newZap(), newSlog()
+	sugar := zp.Sugar()
+
+	runRaw(b, "zerolog", func() {
+		zl.Info().Interface("user", userExample).Msg(message)
+	})
+	runRaw(b, "zap", func() {
+		zp.Info(message, zap.Any("user", userExample))
+	})
+	runRaw(b, "zap-sugar", func() {
+		sugar.Infow(message, "user", userExample)
+	})
+	runRaw(b, "slog", func() {
+		sl.Info(message, slog.Any("user", userExample))
+	})
+	runAdapters(b, func(l onelog.Logger) {
+		l.Info().Any("user", userExample).Msg(message)
+	})
+}
+
+// BenchmarkSlices measures a record with slices of strings, ints and floats.
+func BenchmarkSlices(b *testing.B) {
+	zl, zp, sl := newZerolog(), newZap(), newSlog()
+	sugar := zp.Sugar()
+
+	runRaw(b, "zerolog", func() {
+		zl.Info().Strs("strs", stringsExample).Ints("ints", intsExample).Floats64("floats", floatsExample).Msg(message)
+	})
+	runRaw(b, "zap", func() {
+		zp.Info(message, zap.Strings("strs", stringsExample), zap.Ints("ints", intsExample),
+			zap.Float64s("floats", floatsExample))
+	})
+	runRaw(b, "zap-sugar", func() {
+		sugar.Infow(message, "strs", stringsExample, "ints", intsExample, "floats", floatsExample)
+	})
+	runRaw(b, "slog", func() {
+		sl.Info(message, slog.Any("strs", stringsExample), slog.Any("ints", intsExample),
+			slog.Any("floats", floatsExample))
+	})
+	runAdapters(b, func(l onelog.Logger) {
+		l.Info().Strs("strs", stringsExample).Ints("ints", intsExample).Floats64("floats", floatsExample).Msg(message)
+	})
+}
@@ -0,0 +1,5 @@
+// Package benchmarks compares the onelog adapters with each other and with their raw backends, with and without
+// context pooling. It contains no code besides the benchmarks; run them with:
+//
+//	go test -run=^$ -bench=. -benchmem ./benchmarks
+package benchmarks
@@ -0,0 +1,123 @@
+package benchmarks
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/exp/slog"
+
+	"github.com/nikoksr/onelog"
+	slogadapter "github.com/nikoksr/onelog/adapter/slog"
+	zapadapter "github.com/nikoksr/onelog/adapter/zap"
+	zerologadapter "github.com/nikoksr/onelog/adapter/zerolog"
+)
+
+const message = "benchmark message"
+
+var (
+	errExample  = errors.New("fail")
+	timeExample = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	stringsExample = []string{"a", "b", "c", "d", "e"}
+	intsExample    = []int{1, 2, 3, 4, 5}
+	floatsExample  = []float64{1.1, 2.2, 3.3, 4.4, 5.5}
+
+	fieldsExample = onelog.Fields{
+		"str":   "value",
+		"int":   42,
+		"float": 4.2,
+		"bool":  true,
+		"time":  timeExample,
+	}
+)
+
+// user is the struct logged by the Any scenario.
+type user struct {
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+	Roles     []string  `json:"roles"`
+}
+
+var userExample = user{Name: "Jane Doe", Email: "jane@example.com", CreatedAt: timeExample, Roles: stringsExample}
+
+// withFields are the fields added using With in the With scenario.
+var withFields = []any{
+	"str", "value", "int", 42, "int64", int64(42), "float", 4.2, "bool", true,
+	"time", timeExample, "dur", time.Second, "uint", uint(42), "other", "value", "err", "fail",
+}
+
+// newZerolog returns a zerolog logger writing JSON to io.Discard at info level.
+func newZerolog() *zerolog.Logger {
+	logger := zerolog.New(io.Discard).Level(zerolog.InfoLevel).With().Timestamp().Logger()
+	return &logger
+}
+
+// newZap returns a zap logger writing JSON to io.Discard at info level.
+func newZap() *zap.Logger {
+	encoderConfig := zap.NewProductionEncoderConfig()
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), zapcore.AddSync(io.Discard), zapcore.InfoLevel)
+
+	return zap.New(core)
+}
+
+// newSlog returns a slog logger writing JSON to io.Discard at info level.
+func newSlog() *slog.Logger {
+	return slog.New(slog.NewJSONHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelInfo}))
+}
+
+// adapters returns the onelog adapters under test, each wrapping a backend at info level.
+func adapters() []struct {
+	name   string
+	logger onelog.Logger
+} {
+	return []struct {
+		name   string
+		logger onelog.Logger
+	}{
+		{name: "onelog/zerolog", logger: zerologadapter.NewAdapter(newZerolog())},
+		{name: "onelog/zap", logger: zapadapter.NewAdapter(newZap())},
+		{name: "onelog/zap-sugar", logger: zapadapter.NewSugarAdapter(newZap().Sugar())},
+		{name: "onelog/slog", logger: slogadapter.NewAdapter(newSlog())},
+		{name: "onelog/zap-unpooled", logger: zapadapter.NewAdapter(newZap(), zapadapter.WithoutPooling())},
+		{
+			name:   "onelog/zap-sugar-unpooled",
+			logger: zapadapter.NewSugarAdapter(newZap().Sugar(), zapadapter.WithoutPooling()),
+		},
+		{name: "onelog/slog-unpooled", logger: slogadapter.NewAdapter(newSlog(), slogadapter.WithoutPooling())},
+	}
+}
+
+// runAdapters runs fn against every adapter as a sub-benchmark.
+func runAdapters(b *testing.B, fn func(l onelog.Logger)) {
+	b.Helper()
+
+	for _, adapter := range adapters() {
+		logger := adapter.logger
+		b.Run(adapter.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				fn(logger)
+			}
+		})
+	}
+}
+
+// runRaw runs fn as a sub-benchmark of a raw backend.
+func runRaw(b *testing.B, name string, fn func()) {
+	b.Helper()
+
+	b.Run(name, func(b *testing.B) {
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			fn()
+		}
+	})
+}
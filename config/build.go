@@ -0,0 +1,213 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/exp/slog"
+
+	"github.com/nikoksr/onelog"
+	nopadapter "github.com/nikoksr/onelog/adapter/nop"
+	slogadapter "github.com/nikoksr/onelog/adapter/slog"
+	zapadapter "github.com/nikoksr/onelog/adapter/zap"
+	zerologadapter "github.com/nikoksr/onelog/adapter/zerolog"
+	"github.com/nikoksr/onelog/redact"
+	"github.com/nikoksr/onelog/sampling"
+)
+
+// defaultTick is the sampling tick used if none is configured.
+const defaultTick = time.Second
+
+// Build validates the configuration and builds the logger it describes. The returned function closes the output files
+// opened for the logger; call it once the logger is no longer used.
+//
+// The wrappers are stacked in this order, from the outside in: level filter, sampling, static fields, redaction, so
+// that static fields get redacted too and records below the minimum level are dropped before any other work is done.
+func (c *Config) Build() (logger onelog.Logger, closeOutputs func() error, err error) {
+	if err = c.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	out, files, err := c.output()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch c.backend() {
+	case BackendZap:
+		logger = c.newZap(out)
+	case BackendSlog:
+		logger = c.newSlog(out)
+	case BackendNop:
+		logger = nopadapter.NewAdapter()
+	default:
+		logger = c.newZerolog(out)
+	}
+
+	if c.Redaction != nil {
+		logger = redact.New(logger, c.Redaction.options()...)
+	}
+
+	if len(c.Fields) > 0 {
+		logger = logger.With(sortedFields(c.Fields)...)
+	}
+
+	if s := c.Sampling; s != nil {
+		tick := time.Duration(s.Tick)
+		if tick == 0 {
+			tick = defaultTick
+		}
+		logger = sampling.New(logger, sampling.NewTickSampler(tick, s.Initial, s.Thereafter))
+	}
+
+	level := onelog.InfoLevel
+	if c.Level != "" {
+		level, _ = onelog.ParseLevel(c.Level) // Validated already
+	}
+
+	return onelog.NewLevelFilter(logger, level), func() error { return closeFiles(files) }, nil
+}
+
+// output opens the configured outputs. It returns the opened files along with the writer, so that they can be closed
+// once the logger is no longer used. If an output cannot be opened, the files opened before are closed.
+func (c *Config) output() (io.Writer, []*os.File, error) {
+	if len(c.Outputs) == 0 {
+		return os.Stderr, nil, nil
+	}
+
+	writers := make([]io.Writer, 0, len(c.Outputs))
+	var files []*os.File
+	for i, output := range c.Outputs {
+		switch output {
+		case OutputStdout:
+			writers = append(writers, os.Stdout)
+		case OutputStderr:
+			writers = append(writers, os.Stderr)
+		default:
+			f, err := os.OpenFile(output, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644) //nolint:gosec // Configured path
+			if err != nil {
+				_ = closeFiles(files) // The open error is the one worth reporting
+				return nil, nil, &FieldError{Key: fmt.Sprintf("outputs[%d]", i), Err: err}
+			}
+			writers = append(writers, f)
+			files = append(files, f)
+		}
+	}
+
+	if len(writers) == 1 {
+		return writers[0], files, nil
+	}
+
+	return io.MultiWriter(writers...), files, nil
+}
+
+// closeFiles closes all files, returning the errors of all of them.
+func closeFiles(files []*os.File) error {
+	var errs []error
+	for _, f := range files {
+		if err := f.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *Config) newZerolog(out io.Writer) onelog.Logger {
+	if c.Format == FormatConsole {
+		out = zerolog.ConsoleWriter{Out: out, NoColor: true}
+	}
+
+	// The level is filtered by onelog, so the backend lets everything through
+	logger := zerolog.New(out).Level(zerolog.DebugLevel).With().Timestamp().Logger()
+
+	return zerologadapter.NewAdapter(&logger)
+}
+
+func (c *Config) newZap(out io.Writer) onelog.Logger {
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.EncodeTime = zapcore.RFC3339NanoTimeEncoder
+
+	var encoder zapcore.Encoder
+	if c.Format == FormatConsole {
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	} else {
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	}
+
+	core := zapcore.NewCore(encoder, zapcore.AddSync(out), zapcore.DebugLevel)
+
+	return zapadapter.NewAdapter(zap.New(core))
+}
+
+func (c *Config) newSlog(out io.Writer) onelog.Logger {
+	opts := slog.HandlerOptions{Level: slog.LevelDebug}
+
+	var handler slog.Handler
+	if c.Format == FormatJSON || c.Format == "" {
+		handler = slog.NewJSONHandler(out, &opts)
+	} else {
+		// slog's text handler writes logfmt, which doubles as its console format
+		handler = slog.NewTextHandler(out, &opts)
+	}
+
+	return slogadapter.NewAdapter(slog.New(handler))
+}
+
+// options returns the redaction options described by r.
+func (r *Redaction) options() []redact.Option {
+	var strategy redact.Strategy
+	switch r.Strategy {
+	case StrategyPartial:
+		strategy = redact.Partial(4)
+	case StrategyHash:
+		strategy = redact.Hash(nil)
+	default:
+		strategy = redact.Full
+	}
+
+	var opts []redact.Option
+	if len(r.Keys) > 0 {
+		opts = append(opts, redact.WithKeys(strategy, r.Keys...))
+	}
+
+	detectors := make([]redact.Detector, 0, len(r.Values))
+	for _, name := range r.Values {
+		switch name {
+		case DetectorJWT:
+			detectors = append(detectors, redact.JWT)
+		case DetectorEmail:
+			detectors = append(detectors, redact.Email)
+		case DetectorCardNumber:
+			detectors = append(detectors, redact.CardNumber)
+		}
+	}
+	if len(detectors) > 0 {
+		opts = append(opts, redact.WithValues(strategy, detectors...))
+	}
+
+	return opts
+}
+
+// sortedFields returns the fields as key-value pairs, sorted by key.
+func sortedFields(fields map[string]any) []any {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]any, 0, 2*len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key, fields[key])
+	}
+
+	return pairs
+}
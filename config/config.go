@@ -0,0 +1,214 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/nikoksr/onelog"
+)
+
+// Supported backends.
+const (
+	BackendZerolog = "zerolog"
+	BackendZap     = "zap"
+	BackendSlog    = "slog"
+	BackendNop     = "nop"
+)
+
+// Supported formats. Not every backend supports every format; see Config.Format.
+const (
+	FormatJSON    = "json"
+	FormatConsole = "console"
+	FormatLogfmt  = "logfmt"
+)
+
+// Special outputs; all other outputs are file paths.
+const (
+	OutputStdout = "stdout"
+	OutputStderr = "stderr"
+)
+
+// Supported redaction strategies.
+const (
+	StrategyFull    = "full"
+	StrategyPartial = "partial"
+	StrategyHash    = "hash"
+)
+
+// Supported value detectors of the redaction rules.
+const (
+	DetectorJWT        = "jwt"
+	DetectorEmail      = "email"
+	DetectorCardNumber = "card_number"
+)
+
+type (
+	// Config describes a logger. The zero value is valid and describes a zerolog logger writing JSON records at info
+	// level and above to stderr. Its tags allow decoding it from JSON, YAML and TOML.
+	Config struct {
+		// Backend is the backend logger: "zerolog", "zap", "slog" or "nop". The default is "zerolog".
+		Backend string `json:"backend" yaml:"backend" toml:"backend"`
+		// Level is the minimum level: "debug", "info", "warn", "error" or "fatal". The default is "info".
+		Level string `json:"level" yaml:"level" toml:"level"`
+		// Format is the record format: "json", "console" or "logfmt". The default is "json". Only slog supports
+		// logfmt.
+		Format string `json:"format" yaml:"format" toml:"format"`
+		// Outputs are the destinations of the records: "stdout", "stderr" or file paths, which are opened for
+		// appending and created if necessary. The default is stderr.
+		Outputs []string `json:"outputs" yaml:"outputs" toml:"outputs"`
+		// Sampling enables tick sampling if set.
+		Sampling *Sampling `json:"sampling" yaml:"sampling" toml:"sampling"`
+		// Redaction enables redaction if set.
+		Redaction *Redaction `json:"redaction" yaml:"redaction" toml:"redaction"`
+		// Fields are added to every record.
+		Fields map[string]any `json:"fields" yaml:"fields" toml:"fields"`
+	}
+
+	// Sampling configures tick sampling; see sampling.NewTickSampler.
+	Sampling struct {
+		// Tick is the interval the counters are reset in. The default is one second.
+		Tick Duration `json:"tick" yaml:"tick" toml:"tick"`
+		// Initial is the number of records with the same level and message written per tick.
+		Initial int `json:"initial" yaml:"initial" toml:"initial"`
+		// Thereafter makes every thereafter-th record after the initial ones get written. Zero drops all of them.
+		Thereafter int `json:"thereafter" yaml:"thereafter" toml:"thereafter"`
+	}
+
+	// Redaction configures redaction; see the redact package.
+	Redaction struct {
+		// Strategy is the masking strategy: "full", "partial" or "hash". The default is "full".
+		Strategy string `json:"strategy" yaml:"strategy" toml:"strategy"`
+		// Keys are the patterns of the keys whose values are masked, e.g. "*password*".
+		Keys []string `json:"keys" yaml:"keys" toml:"keys"`
+		// Values are the detectors of values that are masked regardless of their key: "jwt", "email" or
+		// "card_number".
+		Values []string `json:"values" yaml:"values" toml:"values"`
+	}
+
+	// Duration is a time.Duration that is encoded as a string such as "1s" or "500ms".
+	Duration time.Duration
+
+	// FieldError is a validation error of a single configuration key.
+	FieldError struct {
+		// Key is the offending key, e.g. "sampling.tick" or "outputs[1]". Errors of configurations read from the
+		// environment use the name of the environment variable instead.
+		Key string
+		// Err describes the problem.
+		Err error
+	}
+)
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using time.ParseDuration.
+func (d *Duration) UnmarshalText(text []byte) error {
+	duration, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(duration)
+
+	return nil
+}
+
+// Error returns the error message, prefixed with the offending key.
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("invalid %s: %v", e.Key, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Validate checks the configuration. It returns all problems found, joined, as *FieldError values.
+func (c *Config) Validate() error {
+	var errs []error
+	fail := func(key string, format string, args ...any) {
+		errs = append(errs, &FieldError{Key: key, Err: fmt.Errorf(format, args...)})
+	}
+
+	switch c.Backend {
+	case "", BackendZerolog, BackendZap, BackendSlog, BackendNop:
+	default:
+		fail("backend", "unknown backend %q", c.Backend)
+	}
+
+	if c.Level != "" {
+		if _, err := onelog.ParseLevel(c.Level); err != nil {
+			fail("level", "%w", err)
+		}
+	}
+
+	switch c.Format {
+	case "", FormatJSON, FormatConsole:
+	case FormatLogfmt:
+		if c.backend() != BackendSlog && c.backend() != BackendNop {
+			fail("format", "format %q is not supported by backend %q", c.Format, c.backend())
+		}
+	default:
+		fail("format", "unknown format %q", c.Format)
+	}
+
+	for i, output := range c.Outputs {
+		if output == "" {
+			fail(fmt.Sprintf("outputs[%d]", i), "empty output")
+		}
+	}
+
+	if s := c.Sampling; s != nil {
+		if s.Tick < 0 {
+			fail("sampling.tick", "negative tick %s", time.Duration(s.Tick))
+		}
+		if s.Initial < 0 {
+			fail("sampling.initial", "negative count %d", s.Initial)
+		}
+		if s.Thereafter < 0 {
+			fail("sampling.thereafter", "negative count %d", s.Thereafter)
+		}
+	}
+
+	if r := c.Redaction; r != nil {
+		switch r.Strategy {
+		case "", StrategyFull, StrategyPartial, StrategyHash:
+		default:
+			fail("redaction.strategy", "unknown strategy %q", r.Strategy)
+		}
+
+		for i, pattern := range r.Keys {
+			if _, err := path.Match(pattern, ""); err != nil {
+				fail(fmt.Sprintf("redaction.keys[%d]", i), "invalid pattern %q: %w", pattern, err)
+			}
+		}
+
+		for i, detector := range r.Values {
+			switch detector {
+			case DetectorJWT, DetectorEmail, DetectorCardNumber:
+			default:
+				fail(fmt.Sprintf("redaction.values[%d]", i), "unknown detector %q", detector)
+			}
+		}
+	}
+
+	for key := range c.Fields {
+		if key == "" {
+			fail("fields", "empty field key")
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// backend returns the configured backend or the default one.
+func (c *Config) backend() string {
+	if c.Backend == "" {
+		return BackendZerolog
+	}
+
+	return c.Backend
+}
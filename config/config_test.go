@@ -0,0 +1,231 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readLines(t *testing.T, path string) []string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+// TestBuildFromJSON tests if a logger is built from a decoded JSON configuration.
+func TestBuildFromJSON(t *testing.T) {
+	t.Parallel()
+
+	output := filepath.Join(t.TempDir(), "app.log")
+
+	var cfg Config
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"backend": "zerolog",
+		"level": "warn",
+		"outputs": [`+jsonQuote(output)+`],
+		"sampling": {"tick": "1h", "initial": 2, "thereafter": 0},
+		"redaction": {"keys": ["*password*"], "values": ["email"]},
+		"fields": {"service": "api", "owner": "bob@example.com"}
+	}`), &cfg))
+	assert.Equal(t, Duration(time.Hour), cfg.Sampling.Tick)
+
+	logger, closeOutputs, err := cfg.Build()
+	require.NoError(t, err)
+
+	logger.Info().Msg("dropped")
+	for i := 0; i < 3; i++ {
+		logger.Warn().Str("user_password", "hunter2").Msg("sampled")
+	}
+
+	lines := readLines(t, output)
+	require.Len(t, lines, 2, "records below the level and exceeding the sample should be dropped")
+
+	record := make(map[string]any)
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &record))
+	assert.Equal(t, "warn", record["level"])
+	assert.Equal(t, "api", record["service"], "static fields should be added")
+	assert.Equal(t, "[REDACTED]", record["owner"], "static fields should be redacted")
+	assert.Equal(t, "[REDACTED]", record["user_password"])
+	assert.Contains(t, record, "time", "records should carry a timestamp")
+
+	require.NoError(t, closeOutputs())
+	assert.ErrorIs(t, closeOutputs(), os.ErrClosed, "the output file should be closed")
+}
+
+func jsonQuote(s string) string {
+	quoted, _ := json.Marshal(s)
+	return string(quoted)
+}
+
+// TestBuildBackends tests if every backend and format combination writes records.
+func TestBuildBackends(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		backend  string
+		format   string
+		contains string
+	}{
+		{backend: BackendZerolog, format: FormatJSON, contains: `"message":"test"`},
+		{backend: BackendZerolog, format: FormatConsole, contains: "INF test"},
+		{backend: BackendZap, format: FormatJSON, contains: `"msg":"test"`},
+		{backend: BackendZap, format: FormatConsole, contains: "info\ttest"},
+		{backend: BackendSlog, format: FormatJSON, contains: `"msg":"test"`},
+		{backend: BackendSlog, format: FormatLogfmt, contains: "msg=test"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.backend+"/"+tc.format, func(t *testing.T) {
+			t.Parallel()
+
+			output := filepath.Join(t.TempDir(), "app.log")
+			cfg := Config{Backend: tc.backend, Format: tc.format, Outputs: []string{output}}
+
+			logger, closeOutputs, err := cfg.Build()
+			require.NoError(t, err)
+			defer func() { assert.NoError(t, closeOutputs()) }()
+
+			logger.Debug().Msg("dropped")
+			logger.Info().Str("key", "value").Msg("test")
+
+			lines := readLines(t, output)
+			require.Len(t, lines, 1, "the default level should be info")
+			assert.Contains(t, lines[0], tc.contains)
+			assert.Contains(t, lines[0], "value")
+		})
+	}
+}
+
+// TestBuildNop tests if the nop backend writes nothing.
+func TestBuildNop(t *testing.T) {
+	t.Parallel()
+
+	logger, closeOutputs, err := (&Config{Backend: BackendNop}).Build()
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, closeOutputs()) }()
+
+	logger.Error().Msg("test")
+}
+
+// TestValidate tests if validation errors point at the offending keys.
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	cfg := Config{
+		Backend:   "log4j",
+		Level:     "loud",
+		Format:    "xml",
+		Outputs:   []string{"stdout", ""},
+		Sampling:  &Sampling{Tick: Duration(-time.Second), Thereafter: -1},
+		Redaction: &Redaction{Strategy: "shred", Keys: []string{"["}, Values: []string{"jwt", "ssn"}},
+		Fields:    map[string]any{"": "value"},
+	}
+
+	err := cfg.Validate()
+	require.Error(t, err)
+
+	var keys []string
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() { //nolint:errorlint // Inspecting errors.Join
+		var fieldErr *FieldError
+		require.True(t, errors.As(e, &fieldErr))
+		keys = append(keys, fieldErr.Key)
+	}
+
+	assert.Equal(t, []string{
+		"backend", "level", "format", "outputs[1]", "sampling.tick", "sampling.thereafter", "redaction.strategy",
+		"redaction.keys[0]", "redaction.values[1]", "fields",
+	}, keys)
+	assert.Contains(t, err.Error(), `invalid level: unknown level "loud"`)
+
+	_, _, err = cfg.Build()
+	assert.Error(t, err, "invalid configurations should not be built")
+
+	assert.NoError(t, new(Config).Validate(), "the zero value should be valid")
+	assert.ErrorContains(t, (&Config{Backend: BackendZap, Format: FormatLogfmt}).Validate(), "invalid format")
+}
+
+// TestBuildOutputError tests if errors opening outputs point at the offending output.
+func TestBuildOutputError(t *testing.T) {
+	t.Parallel()
+
+	cfg := Config{Outputs: []string{"stderr", filepath.Join(t.TempDir(), "missing", "app.log")}}
+
+	_, closeOutputs, err := cfg.Build()
+	assert.Nil(t, closeOutputs, "nothing should be left to close")
+
+	var fieldErr *FieldError
+	require.ErrorAs(t, err, &fieldErr)
+	assert.Equal(t, "outputs[1]", fieldErr.Key)
+}
+
+// TestFromEnv tests if a configuration is read from environment variables.
+func TestFromEnv(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]string{
+		"LOG_BACKEND":            "slog",
+		"LOG_LEVEL":              "debug",
+		"LOG_FORMAT":             "logfmt",
+		"LOG_OUTPUTS":            "stdout, stderr",
+		"LOG_SAMPLING_TICK":      "2s",
+		"LOG_SAMPLING_INITIAL":   "10",
+		"LOG_REDACTION_KEYS":     "*password*,token",
+		"LOG_REDACTION_STRATEGY": "hash",
+		"LOG_FIELDS":             "service=api, env=prod",
+	}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+
+	cfg, err := fromEnv("LOG", lookup)
+	require.NoError(t, err)
+
+	assert.Equal(t, &Config{
+		Backend:   BackendSlog,
+		Level:     "debug",
+		Format:    FormatLogfmt,
+		Outputs:   []string{"stdout", "stderr"},
+		Sampling:  &Sampling{Tick: Duration(2 * time.Second), Initial: 10},
+		Redaction: &Redaction{Strategy: StrategyHash, Keys: []string{"*password*", "token"}},
+		Fields:    map[string]any{"service": "api", "env": "prod"},
+	}, cfg)
+}
+
+// TestFromEnvErrors tests if errors of configurations read from the environment point at the offending variables.
+func TestFromEnvErrors(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]string{
+		"APP_LOG_LEVEL":             "loud",
+		"APP_LOG_SAMPLING_INITIAL":  "many",
+		"APP_LOG_REDACTION_VALUES":  "jwt,ssn",
+		"APP_LOG_FIELDS":            "service",
+		"APP_LOG_SAMPLING_TICK":     "soon",
+		"APP_LOG_UNRELATED_SETTING": "ignored",
+	}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+
+	_, err := fromEnv("APP_LOG", lookup)
+	require.Error(t, err)
+
+	for _, name := range []string{
+		"APP_LOG_LEVEL", "APP_LOG_SAMPLING_INITIAL", "APP_LOG_REDACTION_VALUES", "APP_LOG_FIELDS", "APP_LOG_SAMPLING_TICK",
+	} {
+		assert.Contains(t, err.Error(), "invalid "+name+":")
+	}
+}
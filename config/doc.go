@@ -0,0 +1,4 @@
+// Package config builds onelog loggers declaratively, from a Config struct that can be decoded from JSON, YAML or TOML
+// files, or from environment variables. It wires the backend logger, its adapter and the wrappers of this module, such
+// as sampling and redaction, so that services do not have to.
+package config
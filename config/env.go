@@ -0,0 +1,163 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FromEnv reads a configuration from environment variables named after the keys of Config, upper-cased and prefixed,
+// e.g. for the prefix "LOG":
+//
+//	LOG_BACKEND=zap
+//	LOG_LEVEL=debug
+//	LOG_FORMAT=console
+//	LOG_OUTPUTS=stdout,/var/log/app.log
+//	LOG_SAMPLING_TICK=1s
+//	LOG_SAMPLING_INITIAL=100
+//	LOG_SAMPLING_THEREAFTER=100
+//	LOG_REDACTION_STRATEGY=partial
+//	LOG_REDACTION_KEYS=*password*,token
+//	LOG_REDACTION_VALUES=jwt,email
+//	LOG_FIELDS=service=api,env=prod
+//
+// Lists are comma-separated. Sampling and redaction are enabled if any of their variables is set. The configuration is
+// validated; errors point at the offending variable.
+func FromEnv(prefix string) (*Config, error) {
+	return fromEnv(prefix, os.LookupEnv)
+}
+
+func fromEnv(prefix string, lookup func(string) (string, bool)) (*Config, error) {
+	var errs []error
+	get := func(key string) (string, bool) {
+		value, ok := lookup(envName(prefix, key))
+		return strings.TrimSpace(value), ok
+	}
+	getInt := func(key string) int {
+		value, ok := get(key)
+		if !ok {
+			return 0
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			errs = append(errs, &FieldError{Key: envName(prefix, key), Err: fmt.Errorf("invalid number %q", value)})
+		}
+		return n
+	}
+
+	c := new(Config)
+	c.Backend, _ = get("backend")
+	c.Level, _ = get("level")
+	c.Format, _ = get("format")
+	if value, ok := get("outputs"); ok {
+		c.Outputs = splitList(value)
+	}
+
+	if hasAny(get, "sampling.tick", "sampling.initial", "sampling.thereafter") {
+		c.Sampling = &Sampling{
+			Initial:    getInt("sampling.initial"),
+			Thereafter: getInt("sampling.thereafter"),
+		}
+		if value, ok := get("sampling.tick"); ok {
+			tick, err := time.ParseDuration(value)
+			if err != nil {
+				errs = append(errs, &FieldError{Key: envName(prefix, "sampling.tick"), Err: err})
+			}
+			c.Sampling.Tick = Duration(tick)
+		}
+	}
+
+	if hasAny(get, "redaction.strategy", "redaction.keys", "redaction.values") {
+		c.Redaction = new(Redaction)
+		c.Redaction.Strategy, _ = get("redaction.strategy")
+		if value, ok := get("redaction.keys"); ok {
+			c.Redaction.Keys = splitList(value)
+		}
+		if value, ok := get("redaction.values"); ok {
+			c.Redaction.Values = splitList(value)
+		}
+	}
+
+	if value, ok := get("fields"); ok {
+		c.Fields = make(map[string]any)
+		for _, pair := range splitList(value) {
+			key, fieldValue, found := strings.Cut(pair, "=")
+			if !found {
+				errs = append(errs, &FieldError{Key: envName(prefix, "fields"), Err: fmt.Errorf("missing '=' in %q", pair)})
+				continue
+			}
+			c.Fields[strings.TrimSpace(key)] = strings.TrimSpace(fieldValue)
+		}
+	}
+
+	if err := c.Validate(); err != nil {
+		errs = append(errs, renameKeys(err, prefix))
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return c, nil
+}
+
+// envName returns the name of the environment variable of a configuration key, e.g. "LOG_SAMPLING_TICK" for
+// "sampling.tick". Indexes of list elements are dropped, since lists are read from a single variable.
+func envName(prefix, key string) string {
+	if i := strings.IndexByte(key, '['); i >= 0 {
+		key = key[:i]
+	}
+
+	name := strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+	if prefix == "" {
+		return name
+	}
+
+	return prefix + "_" + name
+}
+
+// renameKeys replaces the configuration keys of the validation errors in err by the names of their environment
+// variables.
+func renameKeys(err error, prefix string) error {
+	joined, ok := err.(interface{ Unwrap() []error }) //nolint:errorlint // Inspecting the errors of errors.Join
+	if !ok {
+		return err
+	}
+
+	errs := joined.Unwrap()
+	renamed := make([]error, len(errs))
+	for i, e := range errs {
+		var fieldErr *FieldError
+		if errors.As(e, &fieldErr) {
+			e = &FieldError{Key: envName(prefix, fieldErr.Key), Err: fieldErr.Err}
+		}
+		renamed[i] = e
+	}
+
+	return errors.Join(renamed...)
+}
+
+func hasAny(get func(string) (string, bool), keys ...string) bool {
+	for _, key := range keys {
+		if _, ok := get(key); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// splitList splits a comma-separated list, dropping empty elements.
+func splitList(value string) []string {
+	var list []string
+	for _, elem := range strings.Split(value, ",") {
+		if elem = strings.TrimSpace(elem); elem != "" {
+			list = append(list, elem)
+		}
+	}
+
+	return list
+}
@@ -0,0 +1,22 @@
+package onelog
+
+import "context"
+
+// ctxKey is the key under which a Logger is stored in a context.Context.
+type ctxKey struct{}
+
+// NewContext returns a copy of ctx that carries the given logger. Use FromContext to retrieve it.
+func NewContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// FromContext returns the logger stored in ctx by NewContext. The returned bool reports whether a logger was found.
+func FromContext(ctx context.Context) (Logger, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+
+	l, ok := ctx.Value(ctxKey{}).(Logger)
+
+	return l, ok && l != nil
+}
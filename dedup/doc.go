@@ -0,0 +1,3 @@
+// Package dedup provides a onelog.Logger wrapper that collapses identical records, such as the same error reported over
+// and over by a flapping dependency, into a single summary record per time window.
+package dedup
@@ -0,0 +1,265 @@
+package dedup
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nikoksr/onelog"
+	"github.com/nikoksr/onelog/internal/record"
+)
+
+// Compile-time check that Logger implements onelog.Logger, onelog.FatalWriter and onelog.Syncer
+var (
+	_ onelog.Logger      = (*Logger)(nil)
+	_ onelog.FatalWriter = (*Logger)(nil)
+	_ onelog.Syncer      = (*Logger)(nil)
+)
+
+// Keys of the fields added to summary records.
+const (
+	RepeatedKey  = "repeated"
+	FirstSeenKey = "first_seen"
+	LastSeenKey  = "last_seen"
+)
+
+type (
+	// Logger is a onelog.Logger that collapses identical records within a time window. Records are identical if they
+	// share level, message and the values of the selected fields. The first record of a window is written right away;
+	// its duplicates are counted and, once the window closes, summarized by a single record with the same level, fields
+	// and message, suffixed with "(repeated N times)". The summary also carries the repeat count and the timestamps of
+	// the first and last occurrence.
+	//
+	// Loggers derived using With share the windows of their parent. Selected fields added using With count towards the
+	// identity of a record. Fatal records are never collapsed.
+	Logger struct {
+		logger  onelog.Logger
+		withKey string // Selected fields added using With, encoded as part of the identity
+		state   *state
+	}
+
+	// Option configures a Logger.
+	Option func(*state)
+
+	// state holds the open windows, shared by a Logger and all loggers derived from it.
+	state struct {
+		period time.Duration
+		keys   map[string]bool
+		now    func() time.Time
+
+		mu      sync.Mutex
+		windows map[string]*window
+	}
+
+	// window tracks the duplicates of a record.
+	window struct {
+		logger    onelog.Logger
+		level     onelog.Level
+		msg       string
+		fields    []record.Field
+		firstSeen time.Time
+		lastSeen  time.Time
+		repeated  int
+		timer     *time.Timer
+	}
+)
+
+// WithKeys selects the fields whose values are part of the identity of a record. By default, only level and message
+// are compared.
+func WithKeys(keys ...string) Option {
+	return func(s *state) {
+		for _, key := range keys {
+			s.keys[key] = true
+		}
+	}
+}
+
+// New returns a Logger that writes the records of l, collapsing identical records within the given period.
+func New(l onelog.Logger, period time.Duration, opts ...Option) *Logger {
+	s := &state{
+		period:  period,
+		keys:    make(map[string]bool),
+		now:     time.Now,
+		windows: make(map[string]*window),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return &Logger{
+		logger: l,
+		state:  s,
+	}
+}
+
+// Flush closes all open windows, writing a summary record for each window that collapsed duplicates. Call it on
+// shutdown to not lose the pending counts.
+func (l *Logger) Flush() {
+	l.state.mu.Lock()
+	windows := make([]*window, 0, len(l.state.windows))
+	for key, w := range l.state.windows {
+		w.timer.Stop()
+		delete(l.state.windows, key)
+		windows = append(windows, w)
+	}
+	l.state.mu.Unlock()
+
+	for _, w := range windows {
+		w.summarize()
+	}
+}
+
+// identity returns the key identifying records with the given level, message and fields.
+func (l *Logger) identity(level onelog.Level, msg string, fields []record.Field) string {
+	var b strings.Builder
+	b.WriteString(level.String())
+	b.WriteByte(0)
+	b.WriteString(msg)
+	b.WriteString(l.withKey)
+
+	for _, f := range fields {
+		if l.state.keys[f.Key] {
+			writeField(&b, f.Key, f.Value)
+		}
+	}
+
+	return b.String()
+}
+
+// writeField appends a selected field to an identity.
+func writeField(b *strings.Builder, key string, value any) {
+	b.WriteByte(0)
+	b.WriteString(key)
+	b.WriteByte('=')
+	fmt.Fprint(b, value)
+}
+
+func (l *Logger) newContext(level onelog.Level) onelog.LoggerContext {
+	return record.NewContext(func(msg string, fields []record.Field) {
+		if !l.state.track(l, level, msg, fields) {
+			return
+		}
+
+		record.Apply(onelog.AtLevel(l.logger, level), fields).Msg(msg)
+	})
+}
+
+// track records an occurrence of a record. It reports whether the record opens a new window and should be written.
+func (s *state) track(l *Logger, level onelog.Level, msg string, fields []record.Field) bool {
+	key := l.identity(level, msg, fields)
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if w, ok := s.windows[key]; ok {
+		w.repeated++
+		w.lastSeen = now
+
+		return false
+	}
+
+	w := &window{
+		logger:    l.logger,
+		level:     level,
+		msg:       msg,
+		fields:    record.Snapshot(fields),
+		firstSeen: now,
+		lastSeen:  now,
+	}
+	s.windows[key] = w
+	w.timer = time.AfterFunc(s.period, func() { s.close(key, w) })
+
+	return true
+}
+
+// close closes the window w once its time is up.
+func (s *state) close(key string, w *window) {
+	s.mu.Lock()
+	if s.windows[key] != w {
+		s.mu.Unlock()
+		return // Flushed already
+	}
+	delete(s.windows, key)
+	s.mu.Unlock()
+
+	w.summarize()
+}
+
+// summarize writes the summary record of a closed window if it collapsed any duplicates.
+func (w *window) summarize() {
+	if w.repeated == 0 {
+		return
+	}
+
+	record.Apply(onelog.AtLevel(w.logger, w.level), w.fields).
+		Int(RepeatedKey, w.repeated).
+		Time(FirstSeenKey, w.firstSeen).
+		Time(LastSeenKey, w.lastSeen).
+		Msgf("%s (repeated %d times)", w.msg, w.repeated)
+}
+
+// With returns the logger with the given fields.
+func (l *Logger) With(fields ...any) onelog.Logger {
+	withKey := l.withKey
+	if len(l.state.keys) > 0 {
+		var b strings.Builder
+		b.WriteString(withKey)
+		for i := 0; i+1 < len(fields); i += 2 {
+			if key, ok := fields[i].(string); ok && l.state.keys[key] {
+				writeField(&b, key, fields[i+1])
+			}
+		}
+		withKey = b.String()
+	}
+
+	return &Logger{
+		logger:  l.logger.With(fields...),
+		withKey: withKey,
+		state:   l.state,
+	}
+}
+
+// Sync flushes the buffered records of the wrapped logger, if it implements onelog.Syncer.
+func (l *Logger) Sync() error {
+	return onelog.Sync(l.logger)
+}
+
+// Debug returns a LoggerContext for a debug log. To send the log, use the Msg or Msgf methods.
+func (l *Logger) Debug() onelog.LoggerContext {
+	return l.newContext(onelog.DebugLevel)
+}
+
+// Info returns a LoggerContext for an info log. To send the log, use the Msg or Msgf methods.
+func (l *Logger) Info() onelog.LoggerContext {
+	return l.newContext(onelog.InfoLevel)
+}
+
+// Warn returns a LoggerContext for a warn log. To send the log, use the Msg or Msgf methods.
+func (l *Logger) Warn() onelog.LoggerContext {
+	return l.newContext(onelog.WarnLevel)
+}
+
+// Error returns a LoggerContext for an error log. To send the log, use the Msg or Msgf methods.
+func (l *Logger) Error() onelog.LoggerContext {
+	return l.newContext(onelog.ErrorLevel)
+}
+
+// Fatal returns a LoggerContext for a fatal log. Pending summaries are written before the fatal record.
+func (l *Logger) Fatal() onelog.LoggerContext {
+	return record.NewContext(func(msg string, fields []record.Field) {
+		l.Flush()
+		record.Apply(l.logger.Fatal(), fields).Msg(msg)
+	})
+}
+
+// FatalNoExit returns a LoggerContext for a fatal log that does not exit once sent.
+func (l *Logger) FatalNoExit() onelog.LoggerContext {
+	return record.NewContext(func(msg string, fields []record.Field) {
+		l.Flush()
+		ctx, _ := onelog.FatalNoExit(l.logger)
+		record.Apply(ctx, fields).Msg(msg)
+	})
+}
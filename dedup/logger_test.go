@@ -0,0 +1,147 @@
+package dedup
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikoksr/onelog/internal/testutil"
+)
+
+// TestLoggerSummary tests if duplicates are collapsed into a summary record with count and timestamps.
+func TestLoggerSummary(t *testing.T) {
+	t.Parallel()
+
+	buff := new(testutil.SyncBuffer)
+	logger := New(testutil.NewAdapter(buff), time.Hour)
+
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	calls := 0
+	logger.state.now = func() time.Time {
+		calls++
+		return start.Add(time.Duration(calls-1) * time.Second)
+	}
+
+	for i := 0; i < 5; i++ {
+		logger.Error().Err(errors.New("connection refused")).Msg("db unreachable")
+	}
+	logger.Warn().Msg("db unreachable")
+
+	records := testutil.ParseRecords(t, buff.String())
+	require.Len(t, records, 2, "only the first record of each window should be written right away")
+	assert.Equal(t, "error", records[0]["level"])
+	assert.Equal(t, "warn", records[1]["level"], "records with different levels should not be collapsed")
+
+	logger.Flush()
+
+	records = testutil.ParseRecords(t, buff.String())
+	require.Len(t, records, 3, "a summary should only be written for windows with duplicates")
+	summary := records[2]
+	assert.Equal(t, "error", summary["level"])
+	assert.Equal(t, "db unreachable (repeated 4 times)", summary["message"])
+	assert.Equal(t, "connection refused", summary["error"], "the summary should carry the fields of the first record")
+	assert.EqualValues(t, 4, summary[RepeatedKey])
+	assert.Equal(t, start.Format(time.RFC3339), summary[FirstSeenKey])
+	assert.Equal(t, start.Add(4*time.Second).Format(time.RFC3339), summary[LastSeenKey])
+
+	logger.Flush()
+	assert.Len(t, testutil.ParseRecords(t, buff.String()), 3, "flushed windows should not be summarized again")
+}
+
+// TestLoggerWindow tests if the summary is written once the window closes and a new window opens afterwards.
+func TestLoggerWindow(t *testing.T) {
+	t.Parallel()
+
+	buff := new(testutil.SyncBuffer)
+	logger := New(testutil.NewAdapter(buff), 20*time.Millisecond)
+
+	logger.Info().Msg("test")
+	logger.Info().Msg("test")
+
+	assert.Eventually(t, func() bool {
+		return strings.Contains(buff.String(), "test (repeated 1 times)")
+	}, time.Second, 5*time.Millisecond, "the summary should be written once the window closes")
+
+	logger.Info().Msg("test")
+	assert.Len(t, testutil.ParseRecords(t, buff.String()), 3, "the first record of a new window should be written")
+}
+
+// TestLoggerKeys tests if the selected fields, including fields added using With, count towards the identity.
+func TestLoggerKeys(t *testing.T) {
+	t.Parallel()
+
+	buff := new(testutil.SyncBuffer)
+	logger := New(testutil.NewAdapter(buff), time.Hour, WithKeys("host", "service"))
+
+	logger.Info().Str("host", "a").Int("attempt", 1).Msg("retry")
+	logger.Info().Str("host", "a").Int("attempt", 2).Msg("retry")
+	logger.Info().Str("host", "b").Int("attempt", 1).Msg("retry")
+	logger.With("service", "api").Info().Str("host", "a").Msg("retry")
+	logger.With("service", "api").Info().Str("host", "a").Msg("retry")
+
+	records := testutil.ParseRecords(t, buff.String())
+	assert.Len(t, records, 3, "records should only be collapsed if the selected fields match")
+
+	logger.Flush()
+
+	records = testutil.ParseRecords(t, buff.String())
+	require.Len(t, records, 5)
+	for _, summary := range records[3:] {
+		assert.EqualValues(t, 1, summary[RepeatedKey])
+		assert.Equal(t, "a", summary["host"])
+	}
+}
+
+// TestLoggerFatalNoExit tests if fatal records are never collapsed and pending summaries are written before them.
+func TestLoggerFatalNoExit(t *testing.T) {
+	t.Parallel()
+
+	buff := new(testutil.SyncBuffer)
+	logger := New(testutil.NewAdapter(buff), time.Hour)
+
+	logger.Info().Msg("test")
+	logger.Info().Msg("test")
+	logger.FatalNoExit().Msg("fatal")
+	logger.FatalNoExit().Msg("fatal")
+
+	records := testutil.ParseRecords(t, buff.String())
+	require.Len(t, records, 4)
+	assert.Equal(t, "test (repeated 1 times)", records[1]["message"])
+	assert.Equal(t, "fatal", records[2]["message"])
+	assert.Equal(t, "fatal", records[3]["message"])
+}
+
+// TestLoggerConcurrent tests if the logger is safe for concurrent use.
+func TestLoggerConcurrent(t *testing.T) {
+	t.Parallel()
+
+	buff := new(testutil.SyncBuffer)
+	logger := New(testutil.NewAdapter(buff), time.Millisecond)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				logger.Info().Msg("test")
+			}
+		}()
+	}
+	wg.Wait()
+	logger.Flush()
+
+	total := 0
+	for _, r := range testutil.ParseRecords(t, buff.String()) {
+		total++
+		if repeated, ok := r[RepeatedKey].(float64); ok {
+			total += int(repeated) - 1 // The summary itself was counted already
+		}
+	}
+	assert.Equal(t, 800, total, "every record should be written or counted")
+}
@@ -0,0 +1,5 @@
+// Package dupkey provides a onelog.Logger wrapper that resolves duplicate keys, such as a key added using With and added
+// again to a single record, before records reach the backend logger. Backends disagree on duplicate keys: zap and
+// zerolog write the key twice, which many JSON parsers reject or resolve differently, while slog handlers may behave
+// differently again. The wrapper applies one Policy regardless of the backend.
+package dupkey
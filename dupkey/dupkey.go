@@ -0,0 +1,244 @@
+package dupkey
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/nikoksr/onelog"
+	"github.com/nikoksr/onelog/internal/keys"
+	"github.com/nikoksr/onelog/internal/nilsafe"
+	"github.com/nikoksr/onelog/internal/record"
+)
+
+// Compile-time check that logger implements onelog.Logger, onelog.FatalWriter and onelog.Syncer
+var (
+	_ onelog.Logger      = (*logger)(nil)
+	_ onelog.FatalWriter = (*logger)(nil)
+	_ onelog.Syncer      = (*logger)(nil)
+)
+
+// Policy defines how duplicate keys are resolved.
+type Policy uint8
+
+const (
+	// KeepFirst keeps the first field with a key and drops all later ones. Fields added using With come before the
+	// fields of the record.
+	KeepFirst Policy = iota
+	// KeepLast keeps the last field with a key and drops all earlier ones, so that fields of a record override those
+	// added using With.
+	KeepLast
+	// Rename keeps all fields and renames the later ones of a key, by default by appending "_1", "_2" and so on; see
+	// WithRename.
+	Rename
+	// Error reports every duplicate key as a *DuplicateKeyError to the error handler and keeps the first field, like
+	// KeepFirst. The default error handler panics, which makes the policy suited for development and tests; see
+	// WithErrorHandler.
+	Error
+)
+
+type (
+	// Option configures a Logger.
+	Option func(*options)
+
+	options struct {
+		policy  Policy
+		rename  func(key string, n int) string
+		onError func(err error)
+	}
+
+	// logger is a onelog.Logger that resolves duplicate keys before passing records on.
+	logger struct {
+		logger onelog.Logger
+		with   []record.Field // Fields added using With, applied to every record
+		opts   *options
+	}
+
+	// DuplicateKeyError is reported by the Error policy for every duplicate key of a record.
+	DuplicateKeyError struct {
+		// Key is the duplicate key.
+		Key string
+		// Msg is the message of the record.
+		Msg string
+	}
+)
+
+// Error returns the error message.
+func (e *DuplicateKeyError) Error() string {
+	return fmt.Sprintf("duplicate key %q in record %q", e.Key, e.Msg)
+}
+
+// WithRename sets the function returning the new key of the n-th duplicate of key, starting at 1, for the Rename
+// policy. If the new key is taken as well, the function is called again with the next n.
+func WithRename(fn func(key string, n int) string) Option {
+	return func(o *options) {
+		o.rename = fn
+	}
+}
+
+// WithErrorHandler sets the function the Error policy reports duplicate keys to. It is called synchronously, before
+// the record is written.
+func WithErrorHandler(fn func(err error)) Option {
+	return func(o *options) {
+		o.onError = fn
+	}
+}
+
+// New returns a Logger that resolves duplicate keys using policy before passing records on to l. Fields added using
+// With are kept by the Logger and applied to every record, so that they can be resolved along with the fields of the
+// record; fields added to l before wrapping it are not seen. Fields added using Fields are resolved key by key, in the
+// order of their sorted keys, and nil errors, which are omitted from records, do not take up their key.
+func New(l onelog.Logger, policy Policy, opts ...Option) onelog.Logger {
+	o := &options{
+		policy: policy,
+		rename: func(key string, n int) string {
+			return key + "_" + strconv.Itoa(n)
+		},
+		onError: func(err error) {
+			panic(err)
+		},
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return &logger{
+		logger: l,
+		opts:   o,
+	}
+}
+
+func (l *logger) newContext(newBackendContext func() onelog.LoggerContext) onelog.LoggerContext {
+	return record.NewContext(func(msg string, fields []record.Field) {
+		all := make([]record.Field, 0, len(l.with)+len(fields))
+		all = append(all, l.with...)
+		all = appendFlat(all, fields)
+
+		record.Apply(newBackendContext(), l.opts.resolve(msg, all)).Msg(msg)
+	})
+}
+
+// appendFlat appends fields to dst, expanding Fields maps into one field per key and dropping nil errors.
+func appendFlat(dst, fields []record.Field) []record.Field {
+	for _, f := range fields {
+		switch f.Kind {
+		case record.KindFields:
+			m, _ := f.Value.(onelog.Fields)
+			for _, key := range keys.Sorted(m) {
+				dst = append(dst, record.Field{Kind: record.KindAny, Key: key, Value: m[key]})
+			}
+		case record.KindErr, record.KindAnErr:
+			if !nilsafe.IsNil(f.Value) {
+				dst = append(dst, f)
+			}
+		default:
+			dst = append(dst, f)
+		}
+	}
+
+	return dst
+}
+
+// resolve returns fields with all duplicate keys resolved according to the policy. Fields is modified in place.
+func (o *options) resolve(msg string, fields []record.Field) []record.Field {
+	count := make(map[string]int, len(fields))
+	for _, f := range fields {
+		count[f.Key]++
+	}
+	if len(count) == len(fields) {
+		return fields // No duplicates
+	}
+
+	resolved := fields[:0]
+	seen := make(map[string]int, len(count))
+	for _, f := range fields {
+		seen[f.Key]++
+		n := seen[f.Key]
+
+		switch {
+		case n == 1 && o.policy != KeepLast:
+			resolved = append(resolved, f)
+		case o.policy == KeepLast:
+			if n == count[f.Key] {
+				resolved = append(resolved, f)
+			}
+		case o.policy == Rename:
+			f.Key = o.newKey(f.Key, count)
+			resolved = append(resolved, f)
+		case o.policy == Error:
+			o.onError(&DuplicateKeyError{Key: f.Key, Msg: msg})
+		}
+	}
+
+	return resolved
+}
+
+// newKey returns the first key returned by the rename function that is not taken, and marks it as taken.
+func (o *options) newKey(key string, taken map[string]int) string {
+	for n := 1; ; n++ {
+		newKey := o.rename(key, n)
+		if taken[newKey] == 0 {
+			taken[newKey] = 1
+			return newKey
+		}
+	}
+}
+
+// With returns the logger with the given fields. Keys that are not strings are formatted using fmt.Sprint; a trailing
+// key without a value is dropped.
+func (l *logger) With(fields ...any) onelog.Logger {
+	with := make([]record.Field, len(l.with), len(l.with)+len(fields)/2)
+	copy(with, l.with)
+
+	for i := 0; i+1 < len(fields); i += 2 {
+		key, ok := fields[i].(string)
+		if !ok {
+			key = fmt.Sprint(fields[i])
+		}
+		with = append(with, record.Field{Kind: record.KindAny, Key: key, Value: fields[i+1]})
+	}
+
+	return &logger{
+		logger: l.logger,
+		with:   with,
+		opts:   l.opts,
+	}
+}
+
+// Sync flushes the buffered records of the wrapped logger, if it implements onelog.Syncer.
+func (l *logger) Sync() error {
+	return onelog.Sync(l.logger)
+}
+
+// Debug returns a LoggerContext for a debug log. To send the log, use the Msg or Msgf methods.
+func (l *logger) Debug() onelog.LoggerContext {
+	return l.newContext(l.logger.Debug)
+}
+
+// Info returns a LoggerContext for an info log. To send the log, use the Msg or Msgf methods.
+func (l *logger) Info() onelog.LoggerContext {
+	return l.newContext(l.logger.Info)
+}
+
+// Warn returns a LoggerContext for a warn log. To send the log, use the Msg or Msgf methods.
+func (l *logger) Warn() onelog.LoggerContext {
+	return l.newContext(l.logger.Warn)
+}
+
+// Error returns a LoggerContext for an error log. To send the log, use the Msg or Msgf methods.
+func (l *logger) Error() onelog.LoggerContext {
+	return l.newContext(l.logger.Error)
+}
+
+// Fatal returns a LoggerContext for a fatal log. To send the log, use the Msg or Msgf methods.
+func (l *logger) Fatal() onelog.LoggerContext {
+	return l.newContext(l.logger.Fatal)
+}
+
+// FatalNoExit returns a LoggerContext for a fatal log that does not exit once sent.
+func (l *logger) FatalNoExit() onelog.LoggerContext {
+	return l.newContext(func() onelog.LoggerContext {
+		ctx, _ := onelog.FatalNoExit(l.logger)
+		return ctx
+	})
+}
@@ -0,0 +1,159 @@
+package dupkey
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikoksr/onelog"
+	zerologadapter "github.com/nikoksr/onelog/adapter/zerolog"
+	"github.com/nikoksr/onelog/internal/testutil"
+	"github.com/nikoksr/onelog/onelogtest"
+)
+
+// writeDuplicates writes a record with the key "user" added using With, Str and Fields.
+func writeDuplicates(l onelog.Logger) {
+	l.With("user", "with").Info().
+		Str("user", "str").
+		Fields(onelog.Fields{"user": "fields", "other": 1}).
+		Msg("duplicates")
+}
+
+// parseRecord returns the single record written to buff.
+func parseRecord(t *testing.T, buff *bytes.Buffer) onelogtest.Record {
+	t.Helper()
+
+	records, err := onelogtest.JSONParser.Parse(buff.Bytes())
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+
+	return records[0]
+}
+
+// TestPolicies tests if every policy resolves keys added using With, typed methods and Fields.
+func TestPolicies(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		policy   Policy
+		expected onelogtest.Record
+	}{
+		{"KeepFirst", KeepFirst, onelogtest.Record{"user": "with", "other": float64(1)}},
+		{"KeepLast", KeepLast, onelogtest.Record{"user": "fields", "other": float64(1)}},
+		{"Rename", Rename, onelogtest.Record{"user": "with", "user_1": "str", "user_2": "fields", "other": float64(1)}},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			buff := new(bytes.Buffer)
+			writeDuplicates(New(testutil.NewAdapter(buff), tc.policy))
+
+			// zerolog writes duplicate keys verbatim, so count them in the raw output
+			assert.Equal(t, 1, bytes.Count(buff.Bytes(), []byte(`"user":`)), "the key should be written once")
+
+			record := parseRecord(t, buff)
+			delete(record, zerolog.LevelFieldName)
+			delete(record, zerolog.MessageFieldName)
+			assert.Equal(t, tc.expected, record)
+		})
+	}
+}
+
+// TestRename tests if renamed keys skip taken keys and if the rename function can be replaced.
+func TestRename(t *testing.T) {
+	t.Parallel()
+
+	buff := new(bytes.Buffer)
+	New(testutil.NewAdapter(buff), Rename).Info().Str("a", "1").Str("a_1", "2").Str("a", "3").Msg("rename")
+
+	record := parseRecord(t, buff)
+	assert.Equal(t, "1", record["a"])
+	assert.Equal(t, "2", record["a_1"], "existing keys should not be overwritten")
+	assert.Equal(t, "3", record["a_2"], "renamed keys should skip taken keys")
+
+	buff.Reset()
+	logger := New(testutil.NewAdapter(buff), Rename, WithRename(func(key string, n int) string {
+		return key + "#" + string(rune('a'+n-1))
+	}))
+	logger.Info().Str("a", "1").Str("a", "2").Msg("rename")
+
+	record = parseRecord(t, buff)
+	assert.Equal(t, "2", record["a#a"])
+}
+
+// TestError tests if the Error policy reports duplicates and panics by default.
+func TestError(t *testing.T) {
+	t.Parallel()
+
+	buff := new(bytes.Buffer)
+	var errs []error
+	logger := New(testutil.NewAdapter(buff), Error, WithErrorHandler(func(err error) { errs = append(errs, err) }))
+	writeDuplicates(logger)
+
+	require.Len(t, errs, 2, "every duplicate should be reported")
+	var dupErr *DuplicateKeyError
+	require.ErrorAs(t, errs[0], &dupErr)
+	assert.Equal(t, "user", dupErr.Key)
+	assert.Equal(t, `duplicate key "user" in record "duplicates"`, dupErr.Error())
+
+	record := parseRecord(t, buff)
+	assert.Equal(t, "with", record["user"], "the first field should be kept")
+
+	assert.Panics(t, func() {
+		writeDuplicates(New(testutil.NewAdapter(io.Discard), Error))
+	}, "the default handler should panic")
+	assert.NotPanics(t, func() {
+		New(testutil.NewAdapter(io.Discard), Error).With("user", "with").Info().Str("other", "value").Msg("unique")
+	}, "records without duplicates should not be reported")
+}
+
+// TestNilErrors tests if nil errors, which are omitted from records, do not take up their key.
+func TestNilErrors(t *testing.T) {
+	t.Parallel()
+
+	buff := new(bytes.Buffer)
+	New(testutil.NewAdapter(buff), KeepFirst).Info().Err(nil).Err(errors.New("failed")).Msg("nil error")
+
+	record := parseRecord(t, buff)
+	assert.Equal(t, "failed", record[zerolog.ErrorFieldName])
+}
+
+// TestWith tests if fields added using With are inherited, resolved among each other and do not leak into parents.
+func TestWith(t *testing.T) {
+	t.Parallel()
+
+	buff := new(bytes.Buffer)
+	parent := New(testutil.NewAdapter(buff), KeepLast).With("user", "parent")
+	parent.With("user", "child").Info().Msg("child")
+	parent.Info().Msg("parent")
+
+	records, err := onelogtest.JSONParser.Parse(buff.Bytes())
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "child", records[0]["user"])
+	assert.Equal(t, "parent", records[1]["user"])
+	assert.Equal(t, 1, bytes.Count(buff.Bytes()[:bytes.IndexByte(buff.Bytes(), '\n')], []byte(`"user":`)))
+}
+
+// TestConformance tests if the wrapper passes the conformance suite. The suite adds fields to reused contexts more
+// than once, so it needs a policy that tolerates duplicates.
+func TestConformance(t *testing.T) {
+	t.Parallel()
+
+	onelogtest.Run(t, onelogtest.Config{
+		NewLogger: func(out io.Writer) onelog.Logger { return New(testutil.NewAdapter(out), KeepLast) },
+		NewFatalLogger: func(out io.Writer, exit onelog.ExitFunc) onelog.Logger {
+			return New(testutil.NewAdapter(out, zerologadapter.WithExitFunc(exit)), KeepLast)
+		},
+		MessageKey: zerolog.MessageFieldName,
+	})
+}
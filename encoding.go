@@ -0,0 +1,304 @@
+package onelog
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nikoksr/onelog/internal/nilsafe"
+)
+
+// Special time formats of Encoding.TimeFormat. The Unix formats render times as integers since the Unix epoch;
+// TimeFormatNative leaves rendering them to the backend.
+const (
+	TimeFormatUnix      = "UNIX"
+	TimeFormatUnixMs    = "UNIXMS"
+	TimeFormatUnixMicro = "UNIXMICRO"
+	TimeFormatUnixNano  = "UNIXNANO"
+	TimeFormatNative    = "NATIVE"
+)
+
+// DurationFormat defines how durations are rendered.
+type DurationFormat uint8
+
+const (
+	// DurationNanos renders durations as integer nanoseconds, like encoding/json does. This is the default.
+	DurationNanos DurationFormat = iota
+	// DurationMicros renders durations as floating-point microseconds.
+	DurationMicros
+	// DurationMillis renders durations as floating-point milliseconds.
+	DurationMillis
+	// DurationSeconds renders durations as floating-point seconds.
+	DurationSeconds
+	// DurationString renders durations as strings like "1m30s", see time.Duration.String.
+	DurationString
+	// DurationNative leaves rendering durations to the backend.
+	DurationNative
+)
+
+// BytesFormat defines how byte slices passed to Bytes are rendered.
+type BytesFormat uint8
+
+const (
+	// BytesString renders bytes as a string holding them as is. This is the default.
+	BytesString BytesFormat = iota
+	// BytesBase64 renders bytes as a standard base64 string.
+	BytesBase64
+	// BytesHex renders bytes as a lowercase hex string.
+	BytesHex
+)
+
+// ErrorFormat defines how errors are rendered.
+type ErrorFormat uint8
+
+const (
+	// ErrorString renders errors as their message. This is the default.
+	ErrorString ErrorFormat = iota
+	// ErrorObject renders errors as an object holding the message and the type of the error, e.g.
+	// {"message":"EOF","type":"*errors.errorString"}.
+	ErrorObject
+)
+
+// Keys of errors rendered using ErrorObject.
+const (
+	ErrorMessageKey = "message"
+	ErrorTypeKey    = "type"
+)
+
+// Encoding is the policy for rendering values whose representation differs between backends. All adapters of this
+// module honour it for fields added using Bytes, Time, Times, Dur, Durs, TimeDiff, Err, Errs and AnErr, and for times,
+// durations, errors and byte slices passed to Any, Fields and With, so that switching backends does not change the
+// schema of the logs. The zero value is the default policy.
+//
+// TimeFormatNative and DurationNative opt out of this for times and durations: adapters then pass them to the backend
+// as they are, which saves formatting them but renders them according to the configuration of the backend.
+type Encoding struct {
+	// TimeFormat is the layout times are formatted with, see time.Layout, or one of the TimeFormat constants. The
+	// default is time.RFC3339Nano.
+	TimeFormat string
+	// DurationFormat defines how durations are rendered. The default is DurationNanos.
+	DurationFormat DurationFormat
+	// BytesFormat defines how byte slices are rendered. The default is BytesString.
+	BytesFormat BytesFormat
+	// ErrorFormat defines how errors are rendered. The default is ErrorString.
+	ErrorFormat ErrorFormat
+}
+
+// EncodeTime returns t as a string formatted with the time format, as an int64 for the Unix time formats or as it is
+// for TimeFormatNative.
+func (e Encoding) EncodeTime(t time.Time) any {
+	switch e.TimeFormat {
+	case "":
+		return t.Format(time.RFC3339Nano)
+	case TimeFormatNative:
+		return t
+	case TimeFormatUnix:
+		return t.Unix()
+	case TimeFormatUnixMs:
+		return t.UnixMilli()
+	case TimeFormatUnixMicro:
+		return t.UnixMicro()
+	case TimeFormatUnixNano:
+		return t.UnixNano()
+	default:
+		return t.Format(e.TimeFormat)
+	}
+}
+
+// EncodeDuration returns d as an int64 for DurationNanos, a float64 for the other units, a string for DurationString
+// or as it is for DurationNative.
+func (e Encoding) EncodeDuration(d time.Duration) any {
+	switch e.DurationFormat {
+	case DurationNative:
+		return d
+	case DurationMicros:
+		return float64(d) / float64(time.Microsecond)
+	case DurationMillis:
+		return float64(d) / float64(time.Millisecond)
+	case DurationSeconds:
+		return d.Seconds()
+	case DurationString:
+		return d.String()
+	default:
+		return d.Nanoseconds()
+	}
+}
+
+// EncodeBytes returns b as a string in the bytes format.
+func (e Encoding) EncodeBytes(b []byte) string {
+	switch e.BytesFormat {
+	case BytesBase64:
+		return base64.StdEncoding.EncodeToString(b)
+	case BytesHex:
+		return hex.EncodeToString(b)
+	default:
+		return string(b)
+	}
+}
+
+// EncodeError returns err as a string for ErrorString, or as a map[string]any for ErrorObject. err must not be nil.
+func (e Encoding) EncodeError(err error) any {
+	if e.ErrorFormat == ErrorObject {
+		return map[string]any{
+			ErrorMessageKey: err.Error(),
+			ErrorTypeKey:    fmt.Sprintf("%T", err),
+		}
+	}
+
+	return err.Error()
+}
+
+// EncodeTimes returns the encoded times, see EncodeTime. Nil slices are returned as empty slices.
+func (e Encoding) EncodeTimes(times []time.Time) []any {
+	encoded := make([]any, len(times))
+	for i, t := range times {
+		encoded[i] = e.EncodeTime(t)
+	}
+
+	return encoded
+}
+
+// EncodeDurations returns the encoded durations, see EncodeDuration. Nil slices are returned as empty slices.
+func (e Encoding) EncodeDurations(durations []time.Duration) []any {
+	encoded := make([]any, len(durations))
+	for i, d := range durations {
+		encoded[i] = e.EncodeDuration(d)
+	}
+
+	return encoded
+}
+
+// EncodeErrors returns the encoded errors, see EncodeError. Nil errors, including typed nils, are returned as nil and
+// nil slices as empty slices.
+func (e Encoding) EncodeErrors(errs []error) []any {
+	encoded := make([]any, len(errs))
+	for i, err := range errs {
+		if !nilsafe.IsNil(err) {
+			encoded[i] = e.EncodeError(err)
+		}
+	}
+
+	return encoded
+}
+
+// EncodeValue encodes v if it is a time.Time, time.Duration, error or []byte, and reports whether it did. Other values
+// are returned as they are.
+func (e Encoding) EncodeValue(v any) (any, bool) {
+	switch value := v.(type) {
+	case time.Time:
+		return e.EncodeTime(value), true
+	case time.Duration:
+		return e.EncodeDuration(value), true
+	case []byte:
+		return e.EncodeBytes(value), true
+	case error:
+		if nilsafe.IsNil(value) {
+			return nil, true // Typed-nil errors are rendered as null, like other typed nils
+		}
+		return e.EncodeError(value), true
+	default:
+		return v, false
+	}
+}
+
+// EncodeFields returns fields with all values encoded using EncodeValue. Fields is only copied if a value changed.
+func (e Encoding) EncodeFields(fields Fields) Fields {
+	for _, value := range fields {
+		if _, ok := e.EncodeValue(value); ok {
+			return e.copyFields(fields)
+		}
+	}
+
+	return fields
+}
+
+func (e Encoding) copyFields(fields Fields) Fields {
+	encoded := make(Fields, len(fields))
+	for key, value := range fields {
+		encoded[key], _ = e.EncodeValue(value)
+	}
+
+	return encoded
+}
+
+// EncodeKeyValues returns the alternating keys and values of kv, as passed to Logger.With, with all values encoded
+// using EncodeValue. Kv is only copied if a value changed.
+func (e Encoding) EncodeKeyValues(kv []any) []any {
+	var encoded []any
+	for i := 1; i < len(kv); i += 2 {
+		value, ok := e.EncodeValue(kv[i])
+		if !ok {
+			continue
+		}
+		if encoded == nil {
+			encoded = append([]any(nil), kv...)
+		}
+		encoded[i] = value
+	}
+
+	if encoded == nil {
+		return kv
+	}
+
+	return encoded
+}
+
+var (
+	durationFormatNames = []string{"nanos", "micros", "millis", "seconds", "string", "native"}
+	bytesFormatNames    = []string{"string", "base64", "hex"}
+	errorFormatNames    = []string{"string", "object"}
+)
+
+// String returns the lowercase name of the format, e.g. "millis".
+func (f DurationFormat) String() string { return formatName(durationFormatNames, int(f)) }
+
+// MarshalText implements encoding.TextMarshaler.
+func (f DurationFormat) MarshalText() ([]byte, error) { return []byte(f.String()), nil }
+
+// UnmarshalText implements encoding.TextUnmarshaler. Names are case-insensitive.
+func (f *DurationFormat) UnmarshalText(text []byte) error {
+	return parseFormat(durationFormatNames, "duration", text, (*uint8)(f))
+}
+
+// String returns the lowercase name of the format, e.g. "base64".
+func (f BytesFormat) String() string { return formatName(bytesFormatNames, int(f)) }
+
+// MarshalText implements encoding.TextMarshaler.
+func (f BytesFormat) MarshalText() ([]byte, error) { return []byte(f.String()), nil }
+
+// UnmarshalText implements encoding.TextUnmarshaler. Names are case-insensitive.
+func (f *BytesFormat) UnmarshalText(text []byte) error {
+	return parseFormat(bytesFormatNames, "bytes", text, (*uint8)(f))
+}
+
+// String returns the lowercase name of the format, e.g. "object".
+func (f ErrorFormat) String() string { return formatName(errorFormatNames, int(f)) }
+
+// MarshalText implements encoding.TextMarshaler.
+func (f ErrorFormat) MarshalText() ([]byte, error) { return []byte(f.String()), nil }
+
+// UnmarshalText implements encoding.TextUnmarshaler. Names are case-insensitive.
+func (f *ErrorFormat) UnmarshalText(text []byte) error {
+	return parseFormat(errorFormatNames, "error", text, (*uint8)(f))
+}
+
+func formatName(names []string, i int) string {
+	if i < len(names) {
+		return names[i]
+	}
+
+	return "unknown"
+}
+
+func parseFormat(names []string, kind string, text []byte, f *uint8) error {
+	for i, name := range names {
+		if strings.EqualFold(name, string(text)) {
+			*f = uint8(i)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown %s format %q, use one of %s", kind, text, strings.Join(names, ", "))
+}
@@ -0,0 +1,136 @@
+package onelog_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikoksr/onelog"
+)
+
+// TestEncodingDefault tests if the zero encoding renders values in the default formats.
+func TestEncodingDefault(t *testing.T) {
+	t.Parallel()
+
+	var enc onelog.Encoding
+	ts := time.Date(2023, 4, 5, 6, 7, 8, 9, time.UTC)
+
+	assert.Equal(t, "2023-04-05T06:07:08.000000009Z", enc.EncodeTime(ts))
+	assert.Equal(t, int64(1500), enc.EncodeDuration(1500*time.Nanosecond))
+	assert.Equal(t, "onelog", enc.EncodeBytes([]byte("onelog")))
+	assert.Equal(t, "failed", enc.EncodeError(errors.New("failed")))
+	assert.Equal(t, []any{}, enc.EncodeTimes(nil))
+	assert.Equal(t, []any{}, enc.EncodeDurations(nil))
+}
+
+// TestEncodingFormats tests if every format renders values in the expected shape.
+func TestEncodingFormats(t *testing.T) {
+	t.Parallel()
+
+	ts := time.Date(2023, 4, 5, 6, 7, 8, 9_000_000, time.UTC)
+	tests := []struct {
+		name     string
+		enc      onelog.Encoding
+		fn       func(enc onelog.Encoding) any
+		expected any
+	}{
+		{"TimeUnix", onelog.Encoding{TimeFormat: onelog.TimeFormatUnix}, timeOf(ts), ts.Unix()},
+		{"TimeUnixMs", onelog.Encoding{TimeFormat: onelog.TimeFormatUnixMs}, timeOf(ts), ts.UnixMilli()},
+		{"TimeUnixMicro", onelog.Encoding{TimeFormat: onelog.TimeFormatUnixMicro}, timeOf(ts), ts.UnixMicro()},
+		{"TimeUnixNano", onelog.Encoding{TimeFormat: onelog.TimeFormatUnixNano}, timeOf(ts), ts.UnixNano()},
+		{"TimeLayout", onelog.Encoding{TimeFormat: time.DateOnly}, timeOf(ts), "2023-04-05"},
+		{"TimeNative", onelog.Encoding{TimeFormat: onelog.TimeFormatNative}, timeOf(ts), ts},
+		{"DurationMicros", onelog.Encoding{DurationFormat: onelog.DurationMicros}, durationOf(1500), 1.5},
+		{"DurationMillis", onelog.Encoding{DurationFormat: onelog.DurationMillis}, durationOf(1500 * time.Microsecond), 1.5},
+		{"DurationSeconds", onelog.Encoding{DurationFormat: onelog.DurationSeconds}, durationOf(1500 * time.Millisecond), 1.5},
+		{"DurationString", onelog.Encoding{DurationFormat: onelog.DurationString}, durationOf(1500 * time.Millisecond), "1.5s"},
+		{"DurationNative", onelog.Encoding{DurationFormat: onelog.DurationNative}, durationOf(1500), time.Duration(1500)},
+		{"BytesBase64", onelog.Encoding{BytesFormat: onelog.BytesBase64}, bytesOf("onelog"), "b25lbG9n"},
+		{"BytesHex", onelog.Encoding{BytesFormat: onelog.BytesHex}, bytesOf("onelog"), "6f6e656c6f67"},
+		{"ErrorObject", onelog.Encoding{ErrorFormat: onelog.ErrorObject}, func(enc onelog.Encoding) any {
+			return enc.EncodeError(errors.New("failed"))
+		}, map[string]any{onelog.ErrorMessageKey: "failed", onelog.ErrorTypeKey: "*errors.errorString"}},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, tc.fn(tc.enc))
+		})
+	}
+}
+
+func timeOf(ts time.Time) func(enc onelog.Encoding) any {
+	return func(enc onelog.Encoding) any { return enc.EncodeTime(ts) }
+}
+
+func durationOf(d time.Duration) func(enc onelog.Encoding) any {
+	return func(enc onelog.Encoding) any { return enc.EncodeDuration(d) }
+}
+
+func bytesOf(s string) func(enc onelog.Encoding) any {
+	return func(enc onelog.Encoding) any { return enc.EncodeBytes([]byte(s)) }
+}
+
+// TestEncodingValues tests if EncodeValue, EncodeFields and EncodeKeyValues only encode values with a policy and only
+// copy their input if needed.
+func TestEncodingValues(t *testing.T) {
+	t.Parallel()
+
+	enc := onelog.Encoding{DurationFormat: onelog.DurationString}
+
+	value, ok := enc.EncodeValue(time.Second)
+	assert.True(t, ok)
+	assert.Equal(t, "1s", value)
+
+	value, ok = enc.EncodeValue(42)
+	assert.False(t, ok)
+	assert.Equal(t, 42, value)
+
+	var typedNil *json.SyntaxError
+	value, ok = enc.EncodeValue(error(typedNil))
+	assert.True(t, ok)
+	assert.Nil(t, value, "typed-nil errors should be encoded as nil")
+
+	assert.Equal(t, []any{nil, "failed"}, enc.EncodeErrors([]error{typedNil, errors.New("failed")}))
+
+	fields := onelog.Fields{"int": 42}
+	assert.Equal(t, fields, enc.EncodeFields(fields))
+
+	fields["dur"] = time.Second
+	encoded := enc.EncodeFields(fields)
+	assert.Equal(t, onelog.Fields{"int": 42, "dur": "1s"}, encoded)
+	assert.Equal(t, time.Second, fields["dur"], "the input fields should not be modified")
+
+	kv := []any{"int", 42, "dur", time.Second}
+	assert.Equal(t, []any{"int", 42, "dur", "1s"}, enc.EncodeKeyValues(kv))
+	assert.Equal(t, time.Second, kv[3], "the input key-values should not be modified")
+}
+
+// TestEncodingFormatText tests if the formats can be marshalled to and parsed from their names.
+func TestEncodingFormatText(t *testing.T) {
+	t.Parallel()
+
+	var cfg struct {
+		Duration onelog.DurationFormat `json:"duration"`
+		Bytes    onelog.BytesFormat    `json:"bytes"`
+		Error    onelog.ErrorFormat    `json:"error"`
+	}
+
+	require.NoError(t, json.Unmarshal([]byte(`{"duration":"Millis","bytes":"hex","error":"object"}`), &cfg))
+	assert.Equal(t, onelog.DurationMillis, cfg.Duration)
+	assert.Equal(t, onelog.BytesHex, cfg.Bytes)
+	assert.Equal(t, onelog.ErrorObject, cfg.Error)
+
+	data, err := json.Marshal(cfg)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"duration":"millis","bytes":"hex","error":"object"}`, string(data))
+
+	assert.ErrorContains(t, json.Unmarshal([]byte(`{"duration":"hours"}`), &cfg), "unknown duration format")
+	assert.Equal(t, "unknown", onelog.DurationFormat(42).String())
+}
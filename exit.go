@@ -0,0 +1,110 @@
+package onelog
+
+import (
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// ExitFunc terminates the process with the given status code.
+type ExitFunc func(code int)
+
+// Syncer is implemented by loggers that buffer records. Sync flushes all buffered records to the backend.
+type Syncer interface {
+	Sync() error
+}
+
+// exitHook is a registered exit hook. Hooks are stored as pointers, so that they can be unregistered by identity.
+type exitHook struct {
+	fn func()
+}
+
+var (
+	// exitFunc is the process-wide ExitFunc; nil means os.Exit.
+	exitFunc atomic.Pointer[ExitFunc]
+
+	exitHooksMu sync.Mutex
+	exitHooks   []*exitHook
+)
+
+// SetExitFunc replaces the process-wide function used to terminate the process after fatal records and returns a
+// function that restores the previous one. The default is os.Exit. Passing nil restores the default.
+//
+// Adapters that were given their own exit function, e.g. using an option of their constructor, use that one instead.
+func SetExitFunc(fn ExitFunc) (restore func()) {
+	var next *ExitFunc
+	if fn != nil {
+		next = &fn
+	}
+	previous := exitFunc.Swap(next)
+
+	return func() { exitFunc.Store(previous) }
+}
+
+// RegisterExitHook registers fn to run after a fatal record has been written and flushed, right before the process
+// exits. Hooks run in the order they were registered; a hook that panics does not prevent the others from running or
+// the process from exiting. The returned function unregisters the hook.
+func RegisterExitHook(fn func()) (unregister func()) {
+	hook := &exitHook{fn: fn}
+
+	exitHooksMu.Lock()
+	exitHooks = append(exitHooks, hook)
+	exitHooksMu.Unlock()
+
+	return func() {
+		exitHooksMu.Lock()
+		defer exitHooksMu.Unlock()
+
+		for i, h := range exitHooks {
+			if h == hook {
+				exitHooks = append(exitHooks[:i:i], exitHooks[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Exit runs the registered exit hooks and then terminates the process with status code 1, using fn or, if fn is nil,
+// the process-wide exit function.
+//
+// This is the last step of every fatal record: implementations of Logger write the record, flush their backend and
+// then call Exit, instead of terminating the process themselves.
+func Exit(fn ExitFunc) {
+	runExitHooks()
+
+	if fn == nil {
+		fn = os.Exit
+		if global := exitFunc.Load(); global != nil {
+			fn = *global
+		}
+	}
+
+	fn(1)
+}
+
+func runExitHooks() {
+	exitHooksMu.Lock()
+	hooks := append([]*exitHook(nil), exitHooks...)
+	exitHooksMu.Unlock()
+
+	for _, hook := range hooks {
+		runExitHook(hook.fn)
+	}
+}
+
+func runExitHook(fn func()) {
+	defer func() {
+		_ = recover() // The process is about to exit; a failing hook must not prevent that
+	}()
+
+	fn()
+}
+
+// Sync flushes the buffered records of l, if it implements Syncer.
+func Sync(l Logger) error {
+	if s, ok := l.(Syncer); ok {
+		return s.Sync()
+	}
+
+	return nil
+}
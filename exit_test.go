@@ -0,0 +1,167 @@
+package onelog_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nikoksr/onelog"
+	nopadapter "github.com/nikoksr/onelog/adapter/nop"
+	"github.com/nikoksr/onelog/async"
+	"github.com/nikoksr/onelog/dedup"
+	"github.com/nikoksr/onelog/dupkey"
+	"github.com/nikoksr/onelog/hook"
+	"github.com/nikoksr/onelog/internal/testutil"
+	"github.com/nikoksr/onelog/ratelimit"
+	"github.com/nikoksr/onelog/redact"
+	"github.com/nikoksr/onelog/sampling"
+)
+
+// syncLogger is a logger that records calls of Sync.
+type syncLogger struct {
+	onelog.Logger
+	synced int
+	err    error
+}
+
+func (l *syncLogger) FatalNoExit() onelog.LoggerContext {
+	ctx, _ := onelog.FatalNoExit(l.Logger)
+	return ctx
+}
+
+func (l *syncLogger) Sync() error {
+	l.synced++
+	return l.err
+}
+
+// TestExit tests if Exit runs the exit hooks in registration order, unaffected by panics, before calling the exit
+// function with code 1.
+//
+//nolint:paralleltest // Registers process-wide exit hooks.
+func TestExit(t *testing.T) {
+	var calls []string
+
+	unregisterFirst := onelog.RegisterExitHook(func() { calls = append(calls, "first") })
+	defer unregisterFirst()
+	unregisterPanic := onelog.RegisterExitHook(func() { panic("hook failed") })
+	defer unregisterPanic()
+	unregisterLast := onelog.RegisterExitHook(func() { calls = append(calls, "last") })
+
+	onelog.Exit(func(code int) { calls = append(calls, "exit") })
+	assert.Equal(t, []string{"first", "last", "exit"}, calls, "the hooks should run in order before the exit function")
+
+	calls = nil
+	unregisterLast()
+	unregisterLast() // Unregistering twice is a no-op
+
+	var exitCode int
+	onelog.Exit(func(code int) { exitCode = code })
+	assert.Equal(t, []string{"first"}, calls, "unregistered hooks should not run anymore")
+	assert.Equal(t, 1, exitCode, "the process should exit with code 1")
+}
+
+// TestSetExitFunc tests if Exit falls back to the process-wide exit function and SetExitFunc restores the previous one.
+//
+//nolint:paralleltest // Replaces the process-wide exit function.
+func TestSetExitFunc(t *testing.T) {
+	var outer, inner int
+
+	restoreOuter := onelog.SetExitFunc(func(code int) { outer = code })
+	defer restoreOuter()
+
+	restoreInner := onelog.SetExitFunc(func(code int) { inner = code })
+	onelog.Exit(nil)
+	restoreInner()
+	onelog.Exit(nil)
+
+	assert.Equal(t, 1, inner, "the process-wide exit function should be used")
+	assert.Equal(t, 1, outer, "restore should reinstate the previous exit function")
+}
+
+// TestMultiFatalSync tests if a fatal record of a multi logger syncs all children before the process exits.
+//
+//nolint:paralleltest // Replaces the process-wide exit function.
+func TestMultiFatalSync(t *testing.T) {
+	syncer := &syncLogger{Logger: testutil.NewAdapter(new(bytes.Buffer)), err: errors.New("sync failed")}
+
+	var exits, syncedBeforeExit int
+	restore := onelog.SetExitFunc(func(int) {
+		exits++
+		syncedBeforeExit = syncer.synced
+	})
+	defer restore()
+
+	logger := onelog.Multi(syncer, testutil.NewAdapter(new(bytes.Buffer)))
+	logger.Fatal().Msg("fatal")
+
+	assert.Equal(t, 1, exits, "the process should exit exactly once")
+	assert.Equal(t, 1, syncedBeforeExit, "the children should be synced before the process exits")
+	assert.ErrorContains(t, onelog.Sync(logger), "sync failed", "errors of children should be reported by Sync")
+}
+
+// TestWrapperSync tests if the level filter and the wrappers of this module forward Sync to the wrapped logger, so that
+// fatal records of a multi logger flush it before the process exits.
+//
+//nolint:paralleltest // Replaces the process-wide exit function.
+func TestWrapperSync(t *testing.T) {
+	wrappers := map[string]func(l onelog.Logger) onelog.Logger{
+		"LevelFilter": func(l onelog.Logger) onelog.Logger { return onelog.NewLevelFilter(l, onelog.DebugLevel) },
+		"Async": func(l onelog.Logger) onelog.Logger {
+			logger := async.New(l)
+			t.Cleanup(logger.Close)
+			return logger
+		},
+		"Redact":   func(l onelog.Logger) onelog.Logger { return redact.New(l) },
+		"Sampling": func(l onelog.Logger) onelog.Logger { return sampling.New(l, sampling.NewRandomSampler(1)) },
+		"RateLimit": func(l onelog.Logger) onelog.Logger {
+			logger := ratelimit.New(l, 10, time.Second)
+			t.Cleanup(logger.Close)
+			return logger
+		},
+		"Dedup":  func(l onelog.Logger) onelog.Logger { return dedup.New(l, time.Second) },
+		"Dupkey": func(l onelog.Logger) onelog.Logger { return dupkey.New(l, dupkey.KeepLast) },
+		"Hook":   func(l onelog.Logger) onelog.Logger { return hook.New(l) },
+	}
+
+	for name, wrap := range wrappers {
+		syncer := &syncLogger{Logger: testutil.NewAdapter(new(bytes.Buffer)), err: errors.New("sync failed")}
+
+		var syncedBeforeExit int
+		restore := onelog.SetExitFunc(func(int) { syncedBeforeExit = syncer.synced })
+
+		logger := wrap(syncer)
+		onelog.Multi(logger).Fatal().Msg("fatal")
+
+		restore()
+		assert.Equal(t, 1, syncedBeforeExit, "%s: the wrapped logger should be synced before the process exits", name)
+		assert.ErrorContains(t, onelog.Sync(logger), "sync failed", "%s: errors of Sync should be returned", name)
+	}
+}
+
+// TestNopFatal tests if loggers that discard records still exit the process on fatal records.
+//
+//nolint:paralleltest // Replaces the process-wide exit function.
+func TestNopFatal(t *testing.T) {
+	loggers := map[string]onelog.Logger{
+		"Global":   onelog.L(),
+		"Adapter":  nopadapter.NewAdapter(),
+		"Disabled": onelog.NewLevelFilter(testutil.NewAdapter(new(bytes.Buffer)), onelog.Level(42)),
+	}
+
+	for name, logger := range loggers {
+		var exits int
+		restore := onelog.SetExitFunc(func(int) { exits++ })
+
+		logger.Fatal().Str("Test", "Value").Msg("fatal")
+		logger.Fatal().Template().Context().Send()
+		ctx, _ := onelog.FatalNoExit(logger)
+		ctx.Msg("no exit")
+		logger.Error().Msg("no exit")
+
+		restore()
+		assert.Equal(t, 2, exits, "%s: fatal records should exit, other records should not", name)
+	}
+}
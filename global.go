@@ -0,0 +1,72 @@
+package onelog
+
+import "sync/atomic"
+
+// globalLogger wraps the global Logger, since atomic.Pointer needs a concrete type.
+type globalLogger struct {
+	logger Logger
+}
+
+// global holds the process-wide Logger. A nil pointer stands for the default no-op logger.
+var global atomic.Pointer[globalLogger]
+
+// L returns the global Logger. It discards everything until SetGlobal or ReplaceGlobal is called. It is safe for
+// concurrent use.
+func L() Logger {
+	if g := global.Load(); g != nil {
+		return g.logger
+	}
+
+	return nopLogger{}
+}
+
+// SetGlobal sets the global Logger returned by L. Passing nil restores the default no-op logger.
+func SetGlobal(l Logger) {
+	if l == nil {
+		global.Store(nil)
+		return
+	}
+
+	global.Store(&globalLogger{logger: l})
+}
+
+// ReplaceGlobal sets the global Logger and returns a function that restores the previous one, which comes in handy in
+// tests:
+//
+//	defer onelog.ReplaceGlobal(logger)()
+func ReplaceGlobal(l Logger) (restore func()) {
+	previous := global.Load()
+	SetGlobal(l)
+
+	return func() { global.Store(previous) }
+}
+
+// With returns the global logger with the given fields.
+func With(fields ...any) Logger {
+	return L().With(fields...)
+}
+
+// Debug returns a LoggerContext for a debug log of the global logger. To send the log, use the Msg or Msgf methods.
+func Debug() LoggerContext {
+	return L().Debug()
+}
+
+// Info returns a LoggerContext for an info log of the global logger. To send the log, use the Msg or Msgf methods.
+func Info() LoggerContext {
+	return L().Info()
+}
+
+// Warn returns a LoggerContext for a warn log of the global logger. To send the log, use the Msg or Msgf methods.
+func Warn() LoggerContext {
+	return L().Warn()
+}
+
+// Error returns a LoggerContext for an error log of the global logger. To send the log, use the Msg or Msgf methods.
+func Error() LoggerContext {
+	return L().Error()
+}
+
+// Fatal returns a LoggerContext for a fatal log of the global logger. To send the log, use the Msg or Msgf methods.
+func Fatal() LoggerContext {
+	return L().Fatal()
+}
@@ -0,0 +1,80 @@
+package onelog_test
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikoksr/onelog"
+	"github.com/nikoksr/onelog/internal/testutil"
+)
+
+// TestGlobal tests if the package-level shortcuts write to the global logger and if it can be restored.
+//
+//nolint:paralleltest // Replaces the global logger
+func TestGlobal(t *testing.T) {
+	assert.NotPanics(t, func() { onelog.Info().Str("Test", "Value").Msg("discarded") }, "the default should be no-op")
+
+	buff := new(bytes.Buffer)
+	restore := onelog.ReplaceGlobal(testutil.NewAdapter(buff))
+
+	onelog.Debug().Msg("debug")
+	onelog.Info().Msg("info")
+	onelog.Warn().Msg("warn")
+	onelog.Error().Msg("error")
+	onelog.With("test-with", "test").Info().Msg("with")
+
+	records := testutil.ParseRecords(t, buff.String())
+	require.Len(t, records, 5)
+	for i, level := range []string{"debug", "info", "warn", "error", "info"} {
+		assert.Equal(t, level, records[i]["level"])
+	}
+	assert.Equal(t, "test", records[4]["test-with"])
+
+	nested := new(bytes.Buffer)
+	restoreNested := onelog.ReplaceGlobal(testutil.NewAdapter(nested))
+	onelog.Info().Msg("nested")
+	restoreNested()
+	onelog.Info().Msg("restored")
+
+	assert.Len(t, testutil.ParseRecords(t, nested.String()), 1)
+	assert.Len(t, testutil.ParseRecords(t, buff.String()), 6, "the previous global logger should be restored")
+
+	restore()
+	onelog.Info().Msg("discarded")
+	assert.Len(t, testutil.ParseRecords(t, buff.String()), 6, "the default logger should be restored")
+
+	onelog.SetGlobal(testutil.NewAdapter(buff))
+	onelog.SetGlobal(nil)
+	onelog.Info().Msg("discarded")
+	assert.Len(t, testutil.ParseRecords(t, buff.String()), 6, "setting nil should restore the default logger")
+}
+
+// TestGlobalConcurrent tests if the global logger can be replaced while being used.
+//
+//nolint:paralleltest // Replaces the global logger
+func TestGlobalConcurrent(t *testing.T) {
+	defer onelog.ReplaceGlobal(nil)()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				onelog.SetGlobal(testutil.NewAdapter(io.Discard))
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				onelog.Info().Msg("test")
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -4,7 +4,6 @@ go 1.20
 
 require (
 	github.com/rs/zerolog v1.29.1
-	github.com/shopspring/decimal v1.3.1
 	github.com/stretchr/testify v1.8.0
 	go.uber.org/zap v1.24.0
 	golang.org/x/exp v0.0.0-20230713183714-613f0c0eb8a1
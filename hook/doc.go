@@ -0,0 +1,3 @@
+// Package hook provides hooks for onelog loggers. Hooks run for every record right before it is written, independent of
+// the backend adapter, and can add fields to the record, observe it or veto it.
+package hook
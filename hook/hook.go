@@ -0,0 +1,199 @@
+package hook
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/nikoksr/onelog"
+	"github.com/nikoksr/onelog/internal/record"
+)
+
+// Compile-time check that logger implements onelog.Logger, onelog.FatalWriter and onelog.Syncer
+var (
+	_ onelog.Logger      = (*logger)(nil)
+	_ onelog.FatalWriter = (*logger)(nil)
+	_ onelog.Syncer      = (*logger)(nil)
+	_ Hook               = (Func)(nil)
+	_ Vetoer             = (*filter)(nil)
+	_ Hook               = (*Counter)(nil)
+)
+
+type (
+	// Hook is run for every record right before it is written. Fields added to ctx become part of the record.
+	Hook interface {
+		// Run is called with the level and message of the record and the backend context holding its fields.
+		Run(level onelog.Level, msg string, ctx onelog.LoggerContext)
+	}
+
+	// Vetoer is an optional interface for hooks that can prevent records from being written. All vetoers are asked
+	// before any hook is run or any field is passed on to the backend. Fatal records cannot be vetoed.
+	Vetoer interface {
+		// Veto reports whether the record with the given level and message should be dropped.
+		Veto(level onelog.Level, msg string) bool
+	}
+
+	// Func is an adapter to allow the use of ordinary functions as hooks.
+	Func func(level onelog.Level, msg string, ctx onelog.LoggerContext)
+
+	// logger is a onelog.Logger that runs hooks for every record.
+	logger struct {
+		logger onelog.Logger
+		hooks  []Hook
+	}
+)
+
+// Run calls f(level, msg, ctx).
+func (f Func) Run(level onelog.Level, msg string, ctx onelog.LoggerContext) {
+	f(level, msg, ctx)
+}
+
+// New returns a Logger that runs the given hooks, in order, for every record before passing it on to l. If l was
+// returned by New itself, the hooks are appended to its hooks.
+func New(l onelog.Logger, hooks ...Hook) onelog.Logger {
+	if hooked, ok := l.(*logger); ok {
+		combined := make([]Hook, 0, len(hooked.hooks)+len(hooks))
+		combined = append(combined, hooked.hooks...)
+
+		return &logger{logger: hooked.logger, hooks: append(combined, hooks...)}
+	}
+
+	return &logger{logger: l, hooks: hooks}
+}
+
+func (l *logger) newContext(level onelog.Level, newBackendContext func() onelog.LoggerContext) onelog.LoggerContext {
+	return record.NewContext(func(msg string, fields []record.Field) {
+		if level != onelog.FatalLevel && l.vetoed(level, msg) {
+			return
+		}
+
+		ctx := record.Apply(newBackendContext(), fields)
+		for _, h := range l.hooks {
+			h.Run(level, msg, ctx)
+		}
+
+		ctx.Msg(msg)
+	})
+}
+
+// vetoed reports whether any hook vetoes the record.
+func (l *logger) vetoed(level onelog.Level, msg string) bool {
+	for _, h := range l.hooks {
+		if v, ok := h.(Vetoer); ok && v.Veto(level, msg) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// With returns the logger with the given fields.
+func (l *logger) With(fields ...any) onelog.Logger {
+	return &logger{logger: l.logger.With(fields...), hooks: l.hooks}
+}
+
+// Sync flushes the buffered records of the wrapped logger, if it implements onelog.Syncer.
+func (l *logger) Sync() error {
+	return onelog.Sync(l.logger)
+}
+
+// Debug returns a LoggerContext for a debug log. To send the log, use the Msg or Msgf methods.
+func (l *logger) Debug() onelog.LoggerContext {
+	return l.newContext(onelog.DebugLevel, l.logger.Debug)
+}
+
+// Info returns a LoggerContext for an info log. To send the log, use the Msg or Msgf methods.
+func (l *logger) Info() onelog.LoggerContext {
+	return l.newContext(onelog.InfoLevel, l.logger.Info)
+}
+
+// Warn returns a LoggerContext for a warn log. To send the log, use the Msg or Msgf methods.
+func (l *logger) Warn() onelog.LoggerContext {
+	return l.newContext(onelog.WarnLevel, l.logger.Warn)
+}
+
+// Error returns a LoggerContext for an error log. To send the log, use the Msg or Msgf methods.
+func (l *logger) Error() onelog.LoggerContext {
+	return l.newContext(onelog.ErrorLevel, l.logger.Error)
+}
+
+// Fatal returns a LoggerContext for a fatal log. To send the log, use the Msg or Msgf methods.
+func (l *logger) Fatal() onelog.LoggerContext {
+	return l.newContext(onelog.FatalLevel, l.logger.Fatal)
+}
+
+// FatalNoExit returns a LoggerContext for a fatal log that does not exit once sent.
+func (l *logger) FatalNoExit() onelog.LoggerContext {
+	return l.newContext(onelog.FatalLevel, func() onelog.LoggerContext {
+		ctx, _ := onelog.FatalNoExit(l.logger)
+		return ctx
+	})
+}
+
+// Fields returns a hook that adds the given static fields, e.g. the hostname or build version, to every record.
+func Fields(fields onelog.Fields) Hook {
+	return Func(func(_ onelog.Level, _ string, ctx onelog.LoggerContext) {
+		ctx.Fields(fields)
+	})
+}
+
+// GoroutineID returns a hook that adds the ID of the goroutine writing the record to every record, using key.
+func GoroutineID(key string) Hook {
+	return Func(func(_ onelog.Level, _ string, ctx onelog.LoggerContext) {
+		ctx.Uint64(key, goroutineID())
+	})
+}
+
+// goroutineID parses the ID of the current goroutine from its stack trace, which starts with "goroutine <id> [".
+func goroutineID() uint64 {
+	var buf [64]byte
+	stack := buf[:runtime.Stack(buf[:], false)]
+	stack = bytes.TrimPrefix(stack, []byte("goroutine "))
+	if idx := bytes.IndexByte(stack, ' '); idx >= 0 {
+		stack = stack[:idx]
+	}
+
+	id, _ := strconv.ParseUint(string(stack), 10, 64)
+
+	return id
+}
+
+// filter is a hook that vetoes records rejected by a function.
+type filter struct {
+	keep func(level onelog.Level, msg string) bool
+}
+
+// Filter returns a hook that vetoes all records for which keep returns false.
+func Filter(keep func(level onelog.Level, msg string) bool) Hook {
+	return &filter{keep: keep}
+}
+
+// Run does nothing; the filter only vetoes records.
+func (f *filter) Run(_ onelog.Level, _ string, _ onelog.LoggerContext) {}
+
+// Veto reports whether the record should be dropped.
+func (f *filter) Veto(level onelog.Level, msg string) bool {
+	return !f.keep(level, msg)
+}
+
+// Counter is a hook that counts the written records per level. It is safe for concurrent use.
+type Counter struct {
+	counts [onelog.FatalLevel + 1]atomic.Uint64
+}
+
+// Run counts the record.
+func (c *Counter) Run(level onelog.Level, _ string, _ onelog.LoggerContext) {
+	if level >= onelog.DebugLevel && level <= onelog.FatalLevel {
+		c.counts[level].Add(1)
+	}
+}
+
+// Count returns the number of records written at the given level.
+func (c *Counter) Count(level onelog.Level) uint64 {
+	if level < onelog.DebugLevel || level > onelog.FatalLevel {
+		return 0
+	}
+
+	return c.counts[level].Load()
+}
@@ -0,0 +1,76 @@
+package hook
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikoksr/onelog"
+	"github.com/nikoksr/onelog/internal/testutil"
+)
+
+// TestHooks tests if hooks run in order and can add fields to the record.
+func TestHooks(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+	buff := new(bytes.Buffer)
+	logger := New(testutil.NewAdapter(buff),
+		Fields(onelog.Fields{"version": "1.0.0"}),
+		Func(func(level onelog.Level, msg string, ctx onelog.LoggerContext) {
+			calls = append(calls, level.String()+":"+msg)
+			ctx.Str("hooked", "yes")
+		}),
+	).With("test-with", "test")
+
+	logger.Warn().Str("Test", "Value").Msg("Test message")
+
+	assert.Equal(t, []string{"warn:Test message"}, calls, "the hook should run once with the record's level and message")
+
+	records := testutil.ParseRecords(t, buff.String())
+	require.Len(t, records, 1)
+	assert.Equal(t, "1.0.0", records[0]["version"], "static fields should be added")
+	assert.Equal(t, "yes", records[0]["hooked"], "fields added by hooks should be written")
+	assert.Equal(t, "Value", records[0]["Test"], "the record's own fields should be written")
+	assert.Equal(t, "test", records[0]["test-with"], "inherited fields should be written")
+}
+
+// TestVeto tests if records vetoed by a hook are dropped before other hooks run.
+func TestVeto(t *testing.T) {
+	t.Parallel()
+
+	counter := new(Counter)
+	buff := new(bytes.Buffer)
+	logger := New(testutil.NewAdapter(buff), counter)
+	logger = New(logger, Filter(func(level onelog.Level, msg string) bool {
+		return msg != "noisy"
+	}))
+
+	logger.Info().Msg("noisy")
+	logger.Info().Msg("useful")
+	logger.Error().Msg("useful")
+	logger.(onelog.FatalWriter).FatalNoExit().Msg("noisy")
+
+	records := testutil.ParseRecords(t, buff.String())
+	require.Len(t, records, 3, "only the vetoed record should be dropped, fatal records cannot be vetoed")
+	assert.Equal(t, "useful", records[0]["message"])
+
+	assert.EqualValues(t, 1, counter.Count(onelog.InfoLevel), "vetoed records should not be counted")
+	assert.EqualValues(t, 1, counter.Count(onelog.ErrorLevel))
+	assert.EqualValues(t, 1, counter.Count(onelog.FatalLevel))
+	assert.Zero(t, counter.Count(onelog.DebugLevel))
+}
+
+// TestGoroutineID tests if the goroutine ID hook adds a plausible ID.
+func TestGoroutineID(t *testing.T) {
+	t.Parallel()
+
+	buff := new(bytes.Buffer)
+	New(testutil.NewAdapter(buff), GoroutineID("goroutine")).Info().Msg("test")
+
+	records := testutil.ParseRecords(t, buff.String())
+	require.Len(t, records, 1)
+	assert.Greater(t, records[0]["goroutine"], float64(0), "the goroutine ID should be set")
+}
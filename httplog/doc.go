@@ -0,0 +1,2 @@
+// Package httplog provides an http.RoundTripper that logs outbound HTTP requests through an onelog.Logger.
+package httplog
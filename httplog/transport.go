@@ -0,0 +1,320 @@
+package httplog
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nikoksr/onelog"
+)
+
+// Compile-time check that Transport implements http.RoundTripper
+var _ http.RoundTripper = (*Transport)(nil)
+
+// redactedValue replaces the values of redacted query parameters.
+const redactedValue = "REDACTED"
+
+// defaultRedactedParams are the query parameters that get redacted unless configured otherwise.
+var defaultRedactedParams = []string{
+	"access_token",
+	"api_key",
+	"apikey",
+	"client_secret",
+	"code",
+	"id_token",
+	"key",
+	"password",
+	"refresh_token",
+	"secret",
+	"sig",
+	"signature",
+	"token",
+}
+
+type (
+	// Transport is an http.RoundTripper that logs every outbound request through an onelog.Logger. If the request's
+	// context carries a logger (see onelog.NewContext), that logger is used instead of the transport's own logger.
+	Transport struct {
+		logger onelog.Logger
+		next   http.RoundTripper
+
+		redactAll      bool
+		redactedParams map[string]struct{}
+
+		bodyLimit int
+
+		maxRetries int
+		retryWait  time.Duration
+	}
+
+	// Option configures a Transport.
+	Option func(*Transport)
+)
+
+// WithRedactedQueryParams adds the given query parameters to the set of parameters whose values are redacted in the
+// logged URL. Parameter names are matched case-insensitively.
+func WithRedactedQueryParams(params ...string) Option {
+	return func(t *Transport) {
+		for _, param := range params {
+			t.redactedParams[strings.ToLower(param)] = struct{}{}
+		}
+	}
+}
+
+// WithRedactAllQueryParams redacts the values of all query parameters in the logged URL.
+func WithRedactAllQueryParams() Option {
+	return func(t *Transport) {
+		t.redactAll = true
+	}
+}
+
+// WithBodyDump enables logging of request and response bodies at debug level. At most limit bytes of each body are
+// logged. A limit of zero or less disables body dumps, which is the default.
+func WithBodyDump(limit int) Option {
+	return func(t *Transport) {
+		t.bodyLimit = limit
+	}
+}
+
+// WithRetries makes the transport retry idempotent requests that failed with a transport error, a 5xx or a 429 status
+// up to maxRetries times, waiting wait between attempts. Requests with a body are only retried if their GetBody
+// function is set. By default, requests are not retried.
+func WithRetries(maxRetries int, wait time.Duration) Option {
+	return func(t *Transport) {
+		t.maxRetries = maxRetries
+		t.retryWait = wait
+	}
+}
+
+// NewTransport creates a new logging transport that logs through l and delegates the actual round trip to next. If
+// next is nil, http.DefaultTransport is used.
+func NewTransport(l onelog.Logger, next http.RoundTripper, opts ...Option) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	t := &Transport{
+		logger:         l,
+		next:           next,
+		redactedParams: make(map[string]struct{}, len(defaultRedactedParams)),
+	}
+
+	WithRedactedQueryParams(defaultRedactedParams...)(t)
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// RoundTrip executes a single HTTP transaction, including retries, and logs its outcome.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	logger := t.logger
+	if l, ok := onelog.FromContext(req.Context()); ok {
+		logger = l
+	}
+
+	rawURL := t.redactURL(req.URL)
+
+	// The http.RoundTripper contract forbids modifying the request, so we work on a shallow copy.
+	req = req.Clone(req.Context())
+
+	if t.bodyLimit > 0 && req.Body != nil && req.Body != http.NoBody {
+		var body []byte
+		var truncated bool
+		req.Body, body, truncated = peekBody(req.Body, t.bodyLimit)
+
+		logger.Debug().
+			Str("method", req.Method).
+			Str("url", rawURL).
+			Bytes("body", body).
+			Bool("body_truncated", truncated).
+			Msg("http request body")
+	}
+
+	start := time.Now()
+	retries := 0
+
+	resp, err := t.next.RoundTrip(req)
+	for retries < t.maxRetries && t.shouldRetry(req, resp, err) {
+		retry, waitErr := t.prepareRetry(req, resp)
+		if waitErr != nil {
+			resp, err = nil, waitErr
+			break
+		}
+		if !retry {
+			break
+		}
+
+		retries++
+		resp, err = t.next.RoundTrip(req)
+	}
+
+	duration := time.Since(start)
+
+	var logContext onelog.LoggerContext
+	switch {
+	case err != nil, resp.StatusCode >= http.StatusInternalServerError:
+		logContext = logger.Error()
+	case resp.StatusCode >= http.StatusBadRequest:
+		logContext = logger.Warn()
+	default:
+		logContext = logger.Info()
+	}
+
+	logContext = logContext.
+		Str("method", req.Method).
+		Str("url", rawURL).
+		Dur("duration", duration).
+		Int("retries", retries)
+
+	if err != nil {
+		logContext.Err(err).Msg("http request failed")
+
+		return nil, err
+	}
+
+	logContext.Int("status", resp.StatusCode).Msg("http request")
+
+	if t.bodyLimit > 0 && resp.Body != nil && resp.Body != http.NoBody {
+		var body []byte
+		var truncated bool
+		resp.Body, body, truncated = peekBody(resp.Body, t.bodyLimit)
+
+		logger.Debug().
+			Str("method", req.Method).
+			Str("url", rawURL).
+			Int("status", resp.StatusCode).
+			Bytes("body", body).
+			Bool("body_truncated", truncated).
+			Msg("http response body")
+	}
+
+	return resp, nil
+}
+
+// shouldRetry reports whether the outcome of a round trip warrants another attempt.
+func (t *Transport) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil || !isIdempotent(req.Method) {
+		return false
+	}
+
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	if err != nil {
+		return true
+	}
+
+	return resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests
+}
+
+// prepareRetry rewinds the request body, waits before the next attempt and then releases the previous response. It
+// returns false, leaving the response untouched, if the body cannot be rewound. If the request context is done while
+// waiting, the response is released and the error of the context is returned.
+func (t *Transport) prepareRetry(req *http.Request, resp *http.Response) (bool, error) {
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return false, nil
+		}
+		req.Body = body
+	}
+
+	if t.retryWait > 0 {
+		timer := time.NewTimer(t.retryWait)
+		defer timer.Stop()
+
+		select {
+		case <-req.Context().Done():
+			releaseResponse(resp)
+			return false, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+
+	releaseResponse(resp)
+
+	return true, nil
+}
+
+// releaseResponse drains and closes the body of resp, so that its connection can be reused.
+func releaseResponse(resp *http.Response) {
+	if resp != nil && resp.Body != nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}
+}
+
+// redactURL returns the string form of u with its password and sensitive query parameters redacted.
+func (t *Transport) redactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+
+	redacted := *u
+	if redacted.RawQuery != "" {
+		query := redacted.Query()
+		for param, values := range query {
+			if _, ok := t.redactedParams[strings.ToLower(param)]; !ok && !t.redactAll {
+				continue
+			}
+			for i := range values {
+				values[i] = redactedValue
+			}
+		}
+		redacted.RawQuery = query.Encode()
+	}
+
+	return redacted.Redacted()
+}
+
+// isIdempotent reports whether requests with the given method can safely be retried.
+func isIdempotent(method string) bool {
+	switch method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// readCloser combines a reader with the closer of the body it replaces.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// peekBody reads up to limit bytes from body and returns a replacement body that yields the full, unconsumed content.
+// It also returns the bytes read and whether the body was longer than limit.
+func peekBody(body io.ReadCloser, limit int) (io.ReadCloser, []byte, bool) {
+	buf, err := io.ReadAll(io.LimitReader(body, int64(limit)+1))
+
+	// Replay everything we read, followed by the rest of the body. A read error is surfaced to the reader of the
+	// replacement body.
+	var rest io.Reader = body
+	if err != nil {
+		rest = &errReader{err: err}
+	}
+	replacement := &readCloser{Reader: io.MultiReader(bytes.NewReader(buf), rest), Closer: body}
+
+	if len(buf) > limit {
+		return replacement, buf[:limit], true
+	}
+
+	return replacement, buf, false
+}
+
+// errReader is an io.Reader that always returns err.
+type errReader struct {
+	err error
+}
+
+func (r *errReader) Read(_ []byte) (int, error) {
+	return 0, r.err
+}
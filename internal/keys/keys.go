@@ -0,0 +1,19 @@
+// Package keys provides the deterministic order in which adapters add the entries of onelog.Fields.
+package keys
+
+import (
+	"sort"
+
+	"github.com/nikoksr/onelog"
+)
+
+// Sorted returns the keys of fields in sorted order.
+func Sorted(fields onelog.Fields) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
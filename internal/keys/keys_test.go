@@ -0,0 +1,17 @@
+package keys
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nikoksr/onelog"
+)
+
+// TestSorted tests if Sorted returns all keys in sorted order.
+func TestSorted(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"a", "b", "c"}, Sorted(onelog.Fields{"c": 3, "a": 1, "b": 2}))
+	assert.Empty(t, Sorted(nil))
+}
@@ -0,0 +1,56 @@
+// Package nilsafe implements the handling of nil values shared by the adapters, so that all of them render nil
+// values the same way. See the documentation of onelog.LoggerContext for the rendering.
+package nilsafe
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// IsNil reports whether v is nil, or a nil pointer, map, slice, func, chan or interface wrapped in a non-nil interface.
+// Calling methods like String or Error on such values panics for most types, so adapters check them first.
+func IsNil(v any) bool {
+	if v == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface,
+		reflect.UnsafePointer:
+		return rv.IsNil()
+	default:
+		return false
+	}
+}
+
+// Value returns v, or nil if v is a typed nil. It is used for arbitrary values, which are rendered as null if nil.
+func Value(v any) any {
+	if IsNil(v) {
+		return nil
+	}
+
+	return v
+}
+
+// Slice returns s, or an empty slice if s is nil. Nil slices are rendered as empty arrays.
+func Slice[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+
+	return s
+}
+
+// Strings returns the string representations of vals. Nil elements are represented by nil, so that they are rendered
+// as null.
+func Strings(vals []fmt.Stringer) []any {
+	strs := make([]any, len(vals))
+	for i, val := range vals {
+		if !IsNil(val) {
+			strs[i] = val.String()
+		}
+	}
+
+	return strs
+}
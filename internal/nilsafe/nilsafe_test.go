@@ -0,0 +1,43 @@
+package nilsafe
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestIsNil tests if IsNil detects nil values and typed nils of all nilable kinds.
+func TestIsNil(t *testing.T) {
+	t.Parallel()
+
+	var (
+		buff   *bytes.Buffer
+		err    error
+		nilMap map[string]int
+		nilFn  func()
+	)
+
+	assert.True(t, IsNil(nil))
+	assert.True(t, IsNil(buff), "typed-nil pointers should be nil")
+	assert.True(t, IsNil(fmt.Stringer(buff)), "typed-nil pointers wrapped in interfaces should be nil")
+	assert.True(t, IsNil(err))
+	assert.True(t, IsNil(nilMap))
+	assert.True(t, IsNil(nilFn))
+	assert.True(t, IsNil([]int(nil)))
+
+	assert.False(t, IsNil(0))
+	assert.False(t, IsNil(""))
+	assert.False(t, IsNil([]int{}))
+	assert.False(t, IsNil(new(bytes.Buffer)))
+}
+
+// TestStrings tests if Strings keeps nil elements in place.
+func TestStrings(t *testing.T) {
+	t.Parallel()
+
+	var buff *bytes.Buffer
+	assert.Equal(t, []any{nil, nil, "value"}, Strings([]fmt.Stringer{nil, buff, bytes.NewBufferString("value")}))
+	assert.Equal(t, []any{}, Strings(nil))
+}
@@ -0,0 +1,60 @@
+// Package numeric renders arbitrary-precision numbers as JSON number literals, so that adapters can embed them
+// verbatim instead of losing precision by converting them to float64.
+package numeric
+
+import "math/big"
+
+// BigFloat returns v formatted with the smallest number of digits necessary to represent it exactly.
+func BigFloat(v *big.Float) string {
+	return v.Text('g', -1)
+}
+
+// IsJSON reports whether s is a valid JSON number literal.
+func IsJSON(s string) bool {
+	i := 0
+	if i < len(s) && s[i] == '-' {
+		i++
+	}
+
+	// Integer part; leading zeros are not allowed
+	switch {
+	case i < len(s) && s[i] == '0':
+		i++
+	case i < len(s) && s[i] >= '1' && s[i] <= '9':
+		i = skipDigits(s, i)
+	default:
+		return false
+	}
+
+	// Fraction
+	if i < len(s) && s[i] == '.' {
+		j := skipDigits(s, i+1)
+		if j == i+1 {
+			return false
+		}
+		i = j
+	}
+
+	// Exponent
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		i++
+		if i < len(s) && (s[i] == '+' || s[i] == '-') {
+			i++
+		}
+		j := skipDigits(s, i)
+		if j == i {
+			return false
+		}
+		i = j
+	}
+
+	return i == len(s)
+}
+
+func skipDigits(s string, i int) int {
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+
+	return i
+}
@@ -0,0 +1,35 @@
+package numeric
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestIsJSON tests if IsJSON accepts exactly the JSON number literals.
+func TestIsJSON(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []string{"0", "-0", "42", "-42", "3.14", "1e10", "1E-10", "1.5e+06", "123456789012345678901234567890"} {
+		assert.True(t, IsJSON(s), "%q should be a JSON number", s)
+	}
+
+	for _, s := range []string{"", "-", "+1", "01", "1.", ".5", "1e", "1e+", "0x10", "NaN", "+Inf", "1,5", " 1", "true"} {
+		assert.False(t, IsJSON(s), "%q should not be a JSON number", s)
+	}
+}
+
+// TestBigFloat tests if BigFloat formats floats exactly and as JSON numbers where possible.
+func TestBigFloat(t *testing.T) {
+	t.Parallel()
+
+	precise, _, err := big.ParseFloat("12345678901234567890.123456789", 10, 200, big.ToNearestEven)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "1.5", BigFloat(big.NewFloat(1.5)))
+	assert.Equal(t, "1.2345678901234567890123456789e+19", BigFloat(precise))
+	assert.True(t, IsJSON(BigFloat(precise)))
+	assert.False(t, IsJSON(BigFloat(big.NewFloat(math.Inf(1)))), "infinite floats should not be JSON numbers")
+}
@@ -0,0 +1,8 @@
+//go:build !race
+
+// Package race reports whether the race detector is enabled. Tests use it to skip allocation assertions, since the
+// race detector allocates on its own and makes sync.Pool drop items at random.
+package race
+
+// Enabled reports whether the race detector is enabled.
+const Enabled = false
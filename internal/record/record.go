@@ -0,0 +1,788 @@
+// Package record provides a onelog.LoggerContext that records typed fields so they can be inspected and replayed
+// onto other contexts later on. It is the foundation of the wrappers in this module.
+package record
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+	"net/netip"
+	"net/url"
+	"time"
+
+	"github.com/nikoksr/onelog"
+	"github.com/nikoksr/onelog/internal/nilsafe"
+)
+
+// Compile-time checks that Context implements onelog.LoggerContext and Template implements onelog.Template
+var (
+	_ onelog.LoggerContext = (*Context)(nil)
+	_ onelog.Template      = (*Template)(nil)
+)
+
+// Kind identifies the onelog.LoggerContext method a Field was added with.
+type Kind uint8
+
+// The kinds of fields, one per typed onelog.LoggerContext method.
+const (
+	KindBytes Kind = iota
+	KindHex
+	KindRawJSON
+	KindStr
+	KindStrs
+	KindStringer
+	KindStringers
+	KindInt
+	KindInts
+	KindInt8
+	KindInts8
+	KindInt16
+	KindInts16
+	KindInt32
+	KindInts32
+	KindInt64
+	KindInts64
+	KindUint
+	KindUints
+	KindUint8
+	KindUints8
+	KindUint16
+	KindUints16
+	KindUint32
+	KindUints32
+	KindUint64
+	KindUints64
+	KindFloat32
+	KindFloats32
+	KindFloat64
+	KindFloats64
+	KindBool
+	KindBools
+	KindTime
+	KindTimes
+	KindDur
+	KindDurs
+	KindTimeDiff
+	KindIPAddr
+	KindIPPrefix
+	KindMACAddr
+	KindAddr
+	KindPrefix
+	KindAddrPort
+	KindURL
+	KindBigInt
+	KindBigFloat
+	KindDecimal
+	KindErr
+	KindErrs
+	KindAnErr
+	KindAny
+	KindFields
+)
+
+// Field is a single recorded call of a typed onelog.LoggerContext method.
+type Field struct {
+	// Kind is the method the field was added with.
+	Kind Kind
+	// Key is the key of the field. It is empty for KindFields and "error" for KindErr.
+	Key string
+	// Value is the value as passed to the method. For KindTimeDiff, it holds the first of both times; for KindFields,
+	// it holds the onelog.Fields map.
+	Value any
+	// Start holds the second time of KindTimeDiff fields.
+	Start time.Time
+}
+
+// Replay adds f to ctx by calling the method f was recorded with and returns the resulting context.
+func Replay(ctx onelog.LoggerContext, f Field) onelog.LoggerContext {
+	switch f.Kind {
+	case KindBytes:
+		v, _ := f.Value.([]byte)
+
+		return ctx.Bytes(f.Key, v)
+	case KindHex:
+		v, _ := f.Value.([]byte)
+
+		return ctx.Hex(f.Key, v)
+	case KindRawJSON:
+		v, _ := f.Value.([]byte)
+
+		return ctx.RawJSON(f.Key, v)
+	case KindStr:
+		v, _ := f.Value.(string)
+
+		return ctx.Str(f.Key, v)
+	case KindStrs:
+		v, _ := f.Value.([]string)
+
+		return ctx.Strs(f.Key, v)
+	case KindStringer:
+		v, _ := f.Value.(fmt.Stringer)
+
+		return ctx.Stringer(f.Key, v)
+	case KindStringers:
+		v, _ := f.Value.([]fmt.Stringer)
+
+		return ctx.Stringers(f.Key, v)
+	case KindInt:
+		v, _ := f.Value.(int)
+
+		return ctx.Int(f.Key, v)
+	case KindInts:
+		v, _ := f.Value.([]int)
+
+		return ctx.Ints(f.Key, v)
+	case KindInt8:
+		v, _ := f.Value.(int8)
+
+		return ctx.Int8(f.Key, v)
+	case KindInts8:
+		v, _ := f.Value.([]int8)
+
+		return ctx.Ints8(f.Key, v)
+	case KindInt16:
+		v, _ := f.Value.(int16)
+
+		return ctx.Int16(f.Key, v)
+	case KindInts16:
+		v, _ := f.Value.([]int16)
+
+		return ctx.Ints16(f.Key, v)
+	case KindInt32:
+		v, _ := f.Value.(int32)
+
+		return ctx.Int32(f.Key, v)
+	case KindInts32:
+		v, _ := f.Value.([]int32)
+
+		return ctx.Ints32(f.Key, v)
+	case KindInt64:
+		v, _ := f.Value.(int64)
+
+		return ctx.Int64(f.Key, v)
+	case KindInts64:
+		v, _ := f.Value.([]int64)
+
+		return ctx.Ints64(f.Key, v)
+	case KindUint:
+		v, _ := f.Value.(uint)
+
+		return ctx.Uint(f.Key, v)
+	case KindUints:
+		v, _ := f.Value.([]uint)
+
+		return ctx.Uints(f.Key, v)
+	case KindUint8:
+		v, _ := f.Value.(uint8)
+
+		return ctx.Uint8(f.Key, v)
+	case KindUints8:
+		v, _ := f.Value.([]uint8)
+
+		return ctx.Uints8(f.Key, v)
+	case KindUint16:
+		v, _ := f.Value.(uint16)
+
+		return ctx.Uint16(f.Key, v)
+	case KindUints16:
+		v, _ := f.Value.([]uint16)
+
+		return ctx.Uints16(f.Key, v)
+	case KindUint32:
+		v, _ := f.Value.(uint32)
+
+		return ctx.Uint32(f.Key, v)
+	case KindUints32:
+		v, _ := f.Value.([]uint32)
+
+		return ctx.Uints32(f.Key, v)
+	case KindUint64:
+		v, _ := f.Value.(uint64)
+
+		return ctx.Uint64(f.Key, v)
+	case KindUints64:
+		v, _ := f.Value.([]uint64)
+
+		return ctx.Uints64(f.Key, v)
+	case KindFloat32:
+		v, _ := f.Value.(float32)
+
+		return ctx.Float32(f.Key, v)
+	case KindFloats32:
+		v, _ := f.Value.([]float32)
+
+		return ctx.Floats32(f.Key, v)
+	case KindFloat64:
+		v, _ := f.Value.(float64)
+
+		return ctx.Float64(f.Key, v)
+	case KindFloats64:
+		v, _ := f.Value.([]float64)
+
+		return ctx.Floats64(f.Key, v)
+	case KindBool:
+		v, _ := f.Value.(bool)
+
+		return ctx.Bool(f.Key, v)
+	case KindBools:
+		v, _ := f.Value.([]bool)
+
+		return ctx.Bools(f.Key, v)
+	case KindTime:
+		v, _ := f.Value.(time.Time)
+
+		return ctx.Time(f.Key, v)
+	case KindTimes:
+		v, _ := f.Value.([]time.Time)
+
+		return ctx.Times(f.Key, v)
+	case KindDur:
+		v, _ := f.Value.(time.Duration)
+
+		return ctx.Dur(f.Key, v)
+	case KindDurs:
+		v, _ := f.Value.([]time.Duration)
+
+		return ctx.Durs(f.Key, v)
+	case KindTimeDiff:
+		t, _ := f.Value.(time.Time)
+
+		return ctx.TimeDiff(f.Key, t, f.Start)
+	case KindIPAddr:
+		v, _ := f.Value.(net.IP)
+
+		return ctx.IPAddr(f.Key, v)
+	case KindIPPrefix:
+		v, _ := f.Value.(net.IPNet)
+
+		return ctx.IPPrefix(f.Key, v)
+	case KindMACAddr:
+		v, _ := f.Value.(net.HardwareAddr)
+
+		return ctx.MACAddr(f.Key, v)
+	case KindAddr:
+		v, _ := f.Value.(netip.Addr)
+
+		return ctx.Addr(f.Key, v)
+	case KindPrefix:
+		v, _ := f.Value.(netip.Prefix)
+
+		return ctx.Prefix(f.Key, v)
+	case KindAddrPort:
+		v, _ := f.Value.(netip.AddrPort)
+
+		return ctx.AddrPort(f.Key, v)
+	case KindURL:
+		v, _ := f.Value.(*url.URL)
+
+		return ctx.URL(f.Key, v)
+	case KindBigInt:
+		v, _ := f.Value.(*big.Int)
+
+		return ctx.BigInt(f.Key, v)
+	case KindBigFloat:
+		v, _ := f.Value.(*big.Float)
+
+		return ctx.BigFloat(f.Key, v)
+	case KindDecimal:
+		v, _ := f.Value.(fmt.Stringer)
+
+		return ctx.Decimal(f.Key, v)
+	case KindErr:
+		err, _ := f.Value.(error)
+
+		return ctx.Err(err)
+	case KindErrs:
+		v, _ := f.Value.([]error)
+
+		return ctx.Errs(f.Key, v)
+	case KindAnErr:
+		v, _ := f.Value.(error)
+
+		return ctx.AnErr(f.Key, v)
+	case KindAny:
+		return ctx.Any(f.Key, f.Value)
+	case KindFields:
+		fields, _ := f.Value.(onelog.Fields)
+
+		return ctx.Fields(fields)
+	default:
+		return ctx
+	}
+}
+
+// Apply replays all fields onto ctx, in order, and returns the resulting context.
+func Apply(ctx onelog.LoggerContext, fields []Field) onelog.LoggerContext {
+	for _, f := range fields {
+		ctx = Replay(ctx, f)
+	}
+
+	return ctx
+}
+
+// SendFunc receives the message and the recorded fields of a Context once Msg or Msgf is called. The fields slice is
+// owned by the receiver; the Context does not touch it afterwards.
+type SendFunc func(msg string, fields []Field)
+
+// Context is an onelog.LoggerContext that records every field instead of encoding it. Once the message is sent, the
+// recorded fields are handed to a SendFunc, which can inspect, modify, drop or replay them onto another context. A
+// Context is not safe for concurrent use; its Template is, provided the SendFunc is.
+type Context struct {
+	base   []Field // Fields of the template the context was created from, kept across records
+	fields []Field
+	send   SendFunc
+}
+
+// NewContext returns a new recording context that hands its fields to send once the message is sent.
+func NewContext(send SendFunc) *Context {
+	return &Context{send: send}
+}
+
+func (c *Context) add(kind Kind, key string, value any) onelog.LoggerContext {
+	c.fields = append(c.fields, Field{Kind: kind, Key: key, Value: value})
+
+	return c
+}
+
+// Bytes records the field key with val as a []byte.
+func (c *Context) Bytes(key string, value []byte) onelog.LoggerContext {
+	return c.add(KindBytes, key, value)
+}
+
+// Hex records the field key with val as a hex string.
+func (c *Context) Hex(key string, value []byte) onelog.LoggerContext {
+	return c.add(KindHex, key, value)
+}
+
+// RawJSON records the field key with val as a json.RawMessage.
+func (c *Context) RawJSON(key string, value []byte) onelog.LoggerContext {
+	return c.add(KindRawJSON, key, value)
+}
+
+// Str records the field key with val as a string.
+func (c *Context) Str(key, value string) onelog.LoggerContext {
+	return c.add(KindStr, key, value)
+}
+
+// Strs records the field key with val as a []string.
+func (c *Context) Strs(key string, value []string) onelog.LoggerContext {
+	return c.add(KindStrs, key, value)
+}
+
+// Stringer records the field key with val as a fmt.Stringer.
+func (c *Context) Stringer(key string, value fmt.Stringer) onelog.LoggerContext {
+	return c.add(KindStringer, key, value)
+}
+
+// Stringers records the field key with val as a []fmt.Stringer.
+func (c *Context) Stringers(key string, value []fmt.Stringer) onelog.LoggerContext {
+	return c.add(KindStringers, key, value)
+}
+
+// Int records the field key with val as an int.
+func (c *Context) Int(key string, value int) onelog.LoggerContext {
+	return c.add(KindInt, key, value)
+}
+
+// Ints records the field key with val as a []int.
+func (c *Context) Ints(key string, value []int) onelog.LoggerContext {
+	return c.add(KindInts, key, value)
+}
+
+// Int8 records the field key with val as an int8.
+func (c *Context) Int8(key string, value int8) onelog.LoggerContext {
+	return c.add(KindInt8, key, value)
+}
+
+// Ints8 records the field key with val as a []int8.
+func (c *Context) Ints8(key string, value []int8) onelog.LoggerContext {
+	return c.add(KindInts8, key, value)
+}
+
+// Int16 records the field key with val as an int16.
+func (c *Context) Int16(key string, value int16) onelog.LoggerContext {
+	return c.add(KindInt16, key, value)
+}
+
+// Ints16 records the field key with val as a []int16.
+func (c *Context) Ints16(key string, value []int16) onelog.LoggerContext {
+	return c.add(KindInts16, key, value)
+}
+
+// Int32 records the field key with val as an int32.
+func (c *Context) Int32(key string, value int32) onelog.LoggerContext {
+	return c.add(KindInt32, key, value)
+}
+
+// Ints32 records the field key with val as a []int32.
+func (c *Context) Ints32(key string, value []int32) onelog.LoggerContext {
+	return c.add(KindInts32, key, value)
+}
+
+// Int64 records the field key with val as an int64.
+func (c *Context) Int64(key string, value int64) onelog.LoggerContext {
+	return c.add(KindInt64, key, value)
+}
+
+// Ints64 records the field key with val as a []int64.
+func (c *Context) Ints64(key string, value []int64) onelog.LoggerContext {
+	return c.add(KindInts64, key, value)
+}
+
+// Uint records the field key with val as a uint.
+func (c *Context) Uint(key string, value uint) onelog.LoggerContext {
+	return c.add(KindUint, key, value)
+}
+
+// Uints records the field key with val as a []uint.
+func (c *Context) Uints(key string, value []uint) onelog.LoggerContext {
+	return c.add(KindUints, key, value)
+}
+
+// Uint8 records the field key with val as a uint8.
+func (c *Context) Uint8(key string, value uint8) onelog.LoggerContext {
+	return c.add(KindUint8, key, value)
+}
+
+// Uints8 records the field key with val as a []uint8.
+func (c *Context) Uints8(key string, value []uint8) onelog.LoggerContext {
+	return c.add(KindUints8, key, value)
+}
+
+// Uint16 records the field key with val as a uint16.
+func (c *Context) Uint16(key string, value uint16) onelog.LoggerContext {
+	return c.add(KindUint16, key, value)
+}
+
+// Uints16 records the field key with val as a []uint16.
+func (c *Context) Uints16(key string, value []uint16) onelog.LoggerContext {
+	return c.add(KindUints16, key, value)
+}
+
+// Uint32 records the field key with val as a uint32.
+func (c *Context) Uint32(key string, value uint32) onelog.LoggerContext {
+	return c.add(KindUint32, key, value)
+}
+
+// Uints32 records the field key with val as a []uint32.
+func (c *Context) Uints32(key string, value []uint32) onelog.LoggerContext {
+	return c.add(KindUints32, key, value)
+}
+
+// Uint64 records the field key with val as a uint64.
+func (c *Context) Uint64(key string, value uint64) onelog.LoggerContext {
+	return c.add(KindUint64, key, value)
+}
+
+// Uints64 records the field key with val as a []uint64.
+func (c *Context) Uints64(key string, value []uint64) onelog.LoggerContext {
+	return c.add(KindUints64, key, value)
+}
+
+// Float32 records the field key with val as a float32.
+func (c *Context) Float32(key string, value float32) onelog.LoggerContext {
+	return c.add(KindFloat32, key, value)
+}
+
+// Floats32 records the field key with val as a []float32.
+func (c *Context) Floats32(key string, value []float32) onelog.LoggerContext {
+	return c.add(KindFloats32, key, value)
+}
+
+// Float64 records the field key with val as a float64.
+func (c *Context) Float64(key string, value float64) onelog.LoggerContext {
+	return c.add(KindFloat64, key, value)
+}
+
+// Floats64 records the field key with val as a []float64.
+func (c *Context) Floats64(key string, value []float64) onelog.LoggerContext {
+	return c.add(KindFloats64, key, value)
+}
+
+// Bool records the field key with val as a bool.
+func (c *Context) Bool(key string, value bool) onelog.LoggerContext {
+	return c.add(KindBool, key, value)
+}
+
+// Bools records the field key with val as a []bool.
+func (c *Context) Bools(key string, value []bool) onelog.LoggerContext {
+	return c.add(KindBools, key, value)
+}
+
+// Time records the field key with val as a time.Time.
+func (c *Context) Time(key string, value time.Time) onelog.LoggerContext {
+	return c.add(KindTime, key, value)
+}
+
+// Times records the field key with val as a []time.Time.
+func (c *Context) Times(key string, value []time.Time) onelog.LoggerContext {
+	return c.add(KindTimes, key, value)
+}
+
+// Dur records the field key with val as a time.Duration.
+func (c *Context) Dur(key string, value time.Duration) onelog.LoggerContext {
+	return c.add(KindDur, key, value)
+}
+
+// Durs records the field key with val as a []time.Duration.
+func (c *Context) Durs(key string, value []time.Duration) onelog.LoggerContext {
+	return c.add(KindDurs, key, value)
+}
+
+// TimeDiff records the field key with the duration between t and start.
+func (c *Context) TimeDiff(key string, t, start time.Time) onelog.LoggerContext {
+	c.fields = append(c.fields, Field{Kind: KindTimeDiff, Key: key, Value: t, Start: start})
+
+	return c
+}
+
+// IPAddr records the field key with val as a net.IP.
+func (c *Context) IPAddr(key string, value net.IP) onelog.LoggerContext {
+	return c.add(KindIPAddr, key, value)
+}
+
+// IPPrefix records the field key with val as a net.IPNet.
+func (c *Context) IPPrefix(key string, value net.IPNet) onelog.LoggerContext {
+	return c.add(KindIPPrefix, key, value)
+}
+
+// MACAddr records the field key with val as a net.HardwareAddr.
+func (c *Context) MACAddr(key string, value net.HardwareAddr) onelog.LoggerContext {
+	return c.add(KindMACAddr, key, value)
+}
+
+// Addr records the field key with val as a netip.Addr.
+func (c *Context) Addr(key string, value netip.Addr) onelog.LoggerContext {
+	return c.add(KindAddr, key, value)
+}
+
+// Prefix records the field key with val as a netip.Prefix.
+func (c *Context) Prefix(key string, value netip.Prefix) onelog.LoggerContext {
+	return c.add(KindPrefix, key, value)
+}
+
+// AddrPort records the field key with val as a netip.AddrPort.
+func (c *Context) AddrPort(key string, value netip.AddrPort) onelog.LoggerContext {
+	return c.add(KindAddrPort, key, value)
+}
+
+// URL records the field key with val as a *url.URL.
+func (c *Context) URL(key string, value *url.URL) onelog.LoggerContext {
+	return c.add(KindURL, key, value)
+}
+
+// BigInt records the field key with val as a *big.Int.
+func (c *Context) BigInt(key string, value *big.Int) onelog.LoggerContext {
+	return c.add(KindBigInt, key, value)
+}
+
+// BigFloat records the field key with val as a *big.Float.
+func (c *Context) BigFloat(key string, value *big.Float) onelog.LoggerContext {
+	return c.add(KindBigFloat, key, value)
+}
+
+// Decimal records the field key with val as a decimal number.
+func (c *Context) Decimal(key string, value fmt.Stringer) onelog.LoggerContext {
+	return c.add(KindDecimal, key, value)
+}
+
+// Err records the field "error" with err as an error.
+func (c *Context) Err(err error) onelog.LoggerContext {
+	return c.add(KindErr, "error", err)
+}
+
+// Errs records the field key with val as a []error.
+func (c *Context) Errs(key string, value []error) onelog.LoggerContext {
+	return c.add(KindErrs, key, value)
+}
+
+// AnErr records the field key with val as an error.
+func (c *Context) AnErr(key string, value error) onelog.LoggerContext {
+	return c.add(KindAnErr, key, value)
+}
+
+// Any records the field key with val as an arbitrary value.
+func (c *Context) Any(key string, value any) onelog.LoggerContext {
+	return c.add(KindAny, key, value)
+}
+
+// Fields records the given fields.
+func (c *Context) Fields(fields onelog.Fields) onelog.LoggerContext {
+	return c.add(KindFields, "", fields)
+}
+
+// Msg hands the recorded fields and msg to the SendFunc and resets the context.
+func (c *Context) Msg(msg string) {
+	fields := c.fields
+	c.fields = copyFields(c.base)
+
+	c.send(msg, fields)
+}
+
+// Msgf hands the recorded fields and the formatted msg to the SendFunc and resets the context.
+func (c *Context) Msgf(format string, v ...any) {
+	c.Msg(fmt.Sprintf(format, v...))
+}
+
+// Send hands the recorded fields and an empty message to the SendFunc and resets the context.
+func (c *Context) Send() {
+	c.Msg("")
+}
+
+// Discard drops the recorded fields without handing them to the SendFunc and resets the context.
+func (c *Context) Discard() {
+	c.fields = copyFields(c.base)
+}
+
+// Template returns a template holding a Snapshot of the recorded fields and the SendFunc of the context.
+func (c *Context) Template() onelog.Template {
+	return &Template{fields: Snapshot(c.fields), send: c.send}
+}
+
+// Template is an immutable snapshot of the fields of a Context. It is safe for concurrent use if its SendFunc is.
+type Template struct {
+	fields []Field
+	send   SendFunc
+}
+
+// Context returns a new recording context holding the fields of the template. Every record of the context starts
+// with a copy of them, so that the SendFunc may take ownership of them.
+func (t *Template) Context() onelog.LoggerContext {
+	return &Context{base: t.fields, fields: copyFields(t.fields), send: t.send}
+}
+
+// copyFields returns a shallow copy of fields, or nil if there are none.
+func copyFields(fields []Field) []Field {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return append(make([]Field, 0, len(fields)), fields...)
+}
+
+// decimal is a decimal resolved to its string representation by Snapshot.
+type decimal string
+
+func (d decimal) String() string { return string(d) }
+
+// Snapshot returns a deep copy of fields that is safe to keep after the caller returns. Slices, URLs and big numbers are
+// copied, Stringers and decimals are resolved to strings, or to nil if they are nil, and Fields maps are copied
+// shallowly. Values passed via Any, and values nested in Fields, are kept as they are.
+func Snapshot(fields []Field) []Field {
+	snapshot := make([]Field, len(fields))
+	for i, f := range fields {
+		snapshot[i] = snapshotField(f)
+	}
+
+	return snapshot
+}
+
+func snapshotField(f Field) Field {
+	switch f.Kind {
+	case KindStringer:
+		v, _ := f.Value.(fmt.Stringer)
+		if nilsafe.IsNil(v) {
+			return Field{Kind: KindAny, Key: f.Key} // Rendered as null, like nil Stringers
+		}
+
+		return Field{Kind: KindStr, Key: f.Key, Value: v.String()}
+	case KindStringers:
+		v, _ := f.Value.([]fmt.Stringer)
+		strs := make([]string, len(v))
+		for i, s := range v {
+			if nilsafe.IsNil(s) {
+				// Strs cannot hold null elements, so fall back to a slice that can
+				return Field{Kind: KindAny, Key: f.Key, Value: nilsafe.Strings(v)}
+			}
+			strs[i] = s.String()
+		}
+
+		return Field{Kind: KindStrs, Key: f.Key, Value: strs}
+	case KindDecimal:
+		v, _ := f.Value.(fmt.Stringer)
+		if nilsafe.IsNil(v) {
+			return Field{Kind: KindDecimal, Key: f.Key}
+		}
+
+		return Field{Kind: KindDecimal, Key: f.Key, Value: decimal(v.String())}
+	case KindURL:
+		if v, _ := f.Value.(*url.URL); v != nil {
+			u := *v
+			f.Value = &u
+		}
+
+		return f
+	case KindBigInt:
+		if v, _ := f.Value.(*big.Int); v != nil {
+			f.Value = new(big.Int).Set(v)
+		}
+
+		return f
+	case KindBigFloat:
+		if v, _ := f.Value.(*big.Float); v != nil {
+			f.Value = new(big.Float).Copy(v)
+		}
+
+		return f
+	}
+
+	switch v := f.Value.(type) {
+	case []byte:
+		f.Value = cloneSlice(v)
+	case []string:
+		f.Value = cloneSlice(v)
+	case []int:
+		f.Value = cloneSlice(v)
+	case []int8:
+		f.Value = cloneSlice(v)
+	case []int16:
+		f.Value = cloneSlice(v)
+	case []int32:
+		f.Value = cloneSlice(v)
+	case []int64:
+		f.Value = cloneSlice(v)
+	case []uint:
+		f.Value = cloneSlice(v)
+	case []uint16:
+		f.Value = cloneSlice(v)
+	case []uint32:
+		f.Value = cloneSlice(v)
+	case []uint64:
+		f.Value = cloneSlice(v)
+	case []float32:
+		f.Value = cloneSlice(v)
+	case []float64:
+		f.Value = cloneSlice(v)
+	case []bool:
+		f.Value = cloneSlice(v)
+	case []time.Time:
+		f.Value = cloneSlice(v)
+	case []time.Duration:
+		f.Value = cloneSlice(v)
+	case []error:
+		f.Value = cloneSlice(v)
+	case net.IP:
+		f.Value = net.IP(cloneSlice(v))
+	case net.IPNet:
+		f.Value = net.IPNet{IP: cloneSlice(v.IP), Mask: cloneSlice(v.Mask)}
+	case net.HardwareAddr:
+		f.Value = net.HardwareAddr(cloneSlice(v))
+	case onelog.Fields:
+		fields := make(onelog.Fields, len(v))
+		for key, value := range v {
+			fields[key] = value
+		}
+		f.Value = fields
+	}
+
+	return f
+}
+
+// cloneSlice returns a copy of s. A nil slice stays nil.
+func cloneSlice[S ~[]E, E any](s S) S {
+	if s == nil {
+		return nil
+	}
+
+	return append(S(nil), s...)
+}
@@ -0,0 +1,204 @@
+package record
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/netip"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikoksr/onelog"
+)
+
+func recordAll(ctx onelog.LoggerContext) onelog.LoggerContext {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	return ctx.
+		Bytes("bytes", []byte("bytes")).
+		Hex("hex", []byte{0x01}).
+		RawJSON("raw", []byte(`{}`)).
+		Str("str", "str").
+		Strs("strs", []string{"a"}).
+		Stringer("stringer", bytes.NewBufferString("stringer")).
+		Stringers("stringers", []fmt.Stringer{bytes.NewBufferString("stringer")}).
+		Int("int", 1).Ints("ints", []int{1}).
+		Int8("int8", 1).Ints8("ints8", []int8{1}).
+		Int16("int16", 1).Ints16("ints16", []int16{1}).
+		Int32("int32", 1).Ints32("ints32", []int32{1}).
+		Int64("int64", 1).Ints64("ints64", []int64{1}).
+		Uint("uint", 1).Uints("uints", []uint{1}).
+		Uint8("uint8", 1).Uints8("uints8", []uint8{1}).
+		Uint16("uint16", 1).Uints16("uints16", []uint16{1}).
+		Uint32("uint32", 1).Uints32("uints32", []uint32{1}).
+		Uint64("uint64", 1).Uints64("uints64", []uint64{1}).
+		Float32("float32", 1).Floats32("floats32", []float32{1}).
+		Float64("float64", 1).Floats64("floats64", []float64{1}).
+		Bool("bool", true).Bools("bools", []bool{true}).
+		Time("time", now).Times("times", []time.Time{now}).
+		Dur("dur", time.Second).Durs("durs", []time.Duration{time.Second}).
+		TimeDiff("diff", now.Add(time.Second), now).
+		IPAddr("ip", net.IP{127, 0, 0, 1}).
+		IPPrefix("prefix", net.IPNet{IP: net.IP{127, 0, 0, 0}, Mask: net.IPMask{255, 0, 0, 0}}).
+		MACAddr("mac", net.HardwareAddr{0, 0, 0, 0, 0, 0}).
+		Addr("addr", netip.MustParseAddr("127.0.0.1")).
+		Prefix("netprefix", netip.MustParsePrefix("127.0.0.0/8")).
+		AddrPort("addrport", netip.MustParseAddrPort("127.0.0.1:80")).
+		URL("url", &url.URL{Scheme: "https", Host: "example.com"}).
+		BigInt("bigint", big.NewInt(1)).
+		BigFloat("bigfloat", big.NewFloat(1)).
+		Decimal("decimal", bytes.NewBufferString("1.5")).
+		Err(errors.New("err")).
+		Errs("errs", []error{errors.New("err")}).
+		AnErr("anerr", errors.New("err")).
+		Any("any", 1).
+		Fields(onelog.Fields{"field": 1})
+}
+
+// TestReplay tests if replaying recorded fields onto another context reproduces every call.
+func TestReplay(t *testing.T) {
+	t.Parallel()
+
+	var recorded, replayed []Field
+	var recordedMsg, replayedMsg string
+
+	target := NewContext(func(msg string, fields []Field) {
+		replayedMsg, replayed = msg, fields
+	})
+	source := NewContext(func(msg string, fields []Field) {
+		recordedMsg, recorded = msg, fields
+		Apply(target, fields).Msg(msg)
+	})
+
+	recordAll(source).Msgf("Test %s", "message")
+
+	require.Len(t, recorded, int(KindFields)+1, "every method should have been recorded exactly once")
+	for i, f := range recorded {
+		assert.Equal(t, Kind(i), f.Kind, "the fields should be recorded in order")
+	}
+
+	assert.Equal(t, "Test message", recordedMsg)
+	assert.Equal(t, recordedMsg, replayedMsg, "the message should be replayed")
+	assert.Equal(t, recorded, replayed, "the fields should be replayed unchanged")
+}
+
+// TestContextReset tests if a context starts over after the message was sent.
+func TestContextReset(t *testing.T) {
+	t.Parallel()
+
+	var got [][]Field
+	ctx := NewContext(func(_ string, fields []Field) { got = append(got, fields) })
+
+	ctx.Str("first", "value").Msg("first")
+	ctx.Str("second", "value").Msg("second")
+
+	require.Len(t, got, 2)
+	assert.Equal(t, []Field{{Kind: KindStr, Key: "first", Value: "value"}}, got[0])
+	assert.Equal(t, []Field{{Kind: KindStr, Key: "second", Value: "value"}}, got[1])
+}
+
+// TestSendDiscard tests if Send hands the fields to the SendFunc with an empty message, and if Discard drops them
+// without calling it.
+func TestSendDiscard(t *testing.T) {
+	t.Parallel()
+
+	var msgs []string
+	var got [][]Field
+	ctx := NewContext(func(msg string, fields []Field) {
+		msgs = append(msgs, msg)
+		got = append(got, fields)
+	})
+
+	ctx.Str("discarded", "value").Discard()
+	ctx.Str("sent", "value").Send()
+
+	assert.Equal(t, []string{""}, msgs)
+	assert.Equal(t, [][]Field{{{Kind: KindStr, Key: "sent", Value: "value"}}}, got)
+
+	tmpl := ctx.Str("template", "value").Template()
+	reused := tmpl.Context()
+	reused.Str("discarded", "value").Discard()
+	reused.Send()
+
+	require.Len(t, got, 2)
+	assert.Equal(t, []Field{{Kind: KindStr, Key: "template", Value: "value"}}, got[1],
+		"discarded contexts should start over with the fields of the template")
+}
+
+// TestTemplate tests if templates are independent of the context they were taken from and of the contexts they create.
+func TestTemplate(t *testing.T) {
+	t.Parallel()
+
+	var got [][]Field
+	ctx := NewContext(func(msg string, fields []Field) {
+		if msg == "first" {
+			fields[0].Value = "modified" // The SendFunc owns the fields
+		}
+		got = append(got, fields)
+	})
+
+	data := []byte("value")
+	tmpl := ctx.Bytes("bytes", data).Template()
+	data[0] = 'X'
+
+	ctx.Str("context", "value").Msg("context")
+	reused := tmpl.Context()
+	reused.Str("first", "value").Msg("first")
+	reused.Msg("second")
+
+	require.Len(t, got, 3)
+	assert.Len(t, got[0], 2, "the context should keep its fields")
+	assert.Equal(t, []Field{
+		{Kind: KindBytes, Key: "bytes", Value: "modified"},
+		{Kind: KindStr, Key: "first", Value: "value"},
+	}, got[1])
+	assert.Equal(t, []Field{{Kind: KindBytes, Key: "bytes", Value: []byte("value")}}, got[2],
+		"reused contexts should start over with the fields of the template")
+}
+
+// TestSnapshot tests if snapshots are independent of later changes to the recorded values.
+func TestSnapshot(t *testing.T) {
+	t.Parallel()
+
+	data := []byte("value")
+	ints := []int{1, 2}
+	stringer := bytes.NewBufferString("stringer")
+	fields := onelog.Fields{"key": "value"}
+	bigInt := big.NewInt(1)
+	link := &url.URL{Scheme: "https", Host: "example.com"}
+
+	snapshot := Snapshot([]Field{
+		{Kind: KindBytes, Key: "bytes", Value: data},
+		{Kind: KindInts, Key: "ints", Value: ints},
+		{Kind: KindStringer, Key: "stringer", Value: stringer},
+		{Kind: KindStringers, Key: "stringers", Value: []fmt.Stringer{stringer}},
+		{Kind: KindIPAddr, Key: "ip", Value: net.IP{127, 0, 0, 1}},
+		{Kind: KindFields, Value: fields},
+		{Kind: KindBigInt, Key: "bigint", Value: bigInt},
+		{Kind: KindURL, Key: "url", Value: link},
+		{Kind: KindDecimal, Key: "decimal", Value: stringer},
+	})
+
+	data[0] = 'X'
+	ints[0] = 42
+	stringer.WriteString(" changed")
+	fields["key"] = "changed"
+	bigInt.SetInt64(42)
+	link.Host = "changed.com"
+
+	assert.Equal(t, []byte("value"), snapshot[0].Value)
+	assert.Equal(t, []int{1, 2}, snapshot[1].Value)
+	assert.Equal(t, Field{Kind: KindStr, Key: "stringer", Value: "stringer"}, snapshot[2])
+	assert.Equal(t, Field{Kind: KindStrs, Key: "stringers", Value: []string{"stringer"}}, snapshot[3])
+	assert.Equal(t, net.IP{127, 0, 0, 1}, snapshot[4].Value)
+	assert.Equal(t, onelog.Fields{"key": "value"}, snapshot[5].Value)
+	assert.Equal(t, big.NewInt(1), snapshot[6].Value)
+	assert.Equal(t, &url.URL{Scheme: "https", Host: "example.com"}, snapshot[7].Value)
+	assert.Equal(t, Field{Kind: KindDecimal, Key: "decimal", Value: decimal("stringer")}, snapshot[8])
+}
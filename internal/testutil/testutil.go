@@ -0,0 +1,74 @@
+// Package testutil provides the helpers shared by the tests of this module: a zerolog adapter writing JSON records, a
+// parser for those records and a buffer that is safe for concurrent use.
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikoksr/onelog"
+	zerologadapter "github.com/nikoksr/onelog/adapter/zerolog"
+)
+
+// NewAdapter returns a zerolog adapter that writes JSON records to out.
+func NewAdapter(out io.Writer, opts ...zerologadapter.Option) onelog.Logger {
+	logger := zerolog.New(out)
+	return zerologadapter.NewAdapter(&logger, opts...)
+}
+
+// ParseRecords parses the newline-delimited JSON records in s, failing the test if one of them is invalid.
+func ParseRecords(t *testing.T, s string) []map[string]any {
+	t.Helper()
+
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(s), "\n") {
+		if line == "" {
+			continue
+		}
+
+		record := make(map[string]any)
+		require.NoError(t, json.Unmarshal([]byte(line), &record), "the log should be valid json")
+		records = append(records, record)
+	}
+
+	return records
+}
+
+// ParseRecord parses the single JSON record in s, failing the test if there is not exactly one.
+func ParseRecord(t *testing.T, s string) map[string]any {
+	t.Helper()
+
+	records := ParseRecords(t, s)
+	require.Len(t, records, 1, "exactly one record should be written")
+
+	return records[0]
+}
+
+// SyncBuffer is a bytes.Buffer that is safe for concurrent use.
+type SyncBuffer struct {
+	mu   sync.Mutex
+	buff bytes.Buffer
+}
+
+// Write appends p to the buffer.
+func (b *SyncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buff.Write(p)
+}
+
+// String returns the contents of the buffer.
+func (b *SyncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buff.String()
+}
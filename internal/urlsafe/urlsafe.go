@@ -0,0 +1,21 @@
+// Package urlsafe implements the rendering of URLs shared by the adapters, so that all of them mask credentials the
+// same way. See the documentation of onelog.LoggerContext.URL for the rendering.
+package urlsafe
+
+import "net/url"
+
+// redactedUserinfo replaces the user information of redacted URLs.
+const redactedUserinfo = "xxxxx"
+
+// Redacted returns u as a string with its user information, if any, replaced by "xxxxx". Unlike url.URL.Redacted, it
+// masks the username too, since URLs often carry tokens or API keys as the username alone.
+func Redacted(u *url.URL) string {
+	if u.User == nil {
+		return u.String()
+	}
+
+	redacted := *u
+	redacted.User = url.User(redactedUserinfo)
+
+	return redacted.String()
+}
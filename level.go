@@ -0,0 +1,195 @@
+package onelog
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Compile-time check that levelFilter implements Logger, FatalWriter and Syncer
+var (
+	_ Logger      = (*levelFilter)(nil)
+	_ FatalWriter = (*levelFilter)(nil)
+	_ Syncer      = (*levelFilter)(nil)
+)
+
+// Level defines the severity of a log record.
+type Level int8
+
+const (
+	// DebugLevel is used for verbose output that is usually disabled in production.
+	DebugLevel Level = iota
+	// InfoLevel is the default level for informational records.
+	InfoLevel
+	// WarnLevel is used for records that are noteworthy but not errors.
+	WarnLevel
+	// ErrorLevel is used for records about failed operations.
+	ErrorLevel
+	// FatalLevel is used for records after which the process terminates.
+	FatalLevel
+)
+
+// String returns the lowercase name of the level.
+func (l Level) String() string {
+	switch l {
+	case DebugLevel:
+		return "debug"
+	case InfoLevel:
+		return "info"
+	case WarnLevel:
+		return "warn"
+	case ErrorLevel:
+		return "error"
+	case FatalLevel:
+		return "fatal"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseLevel parses a level from its name, as returned by Level.String. Parsing is case-insensitive and accepts
+// "warning" as an alias for "warn".
+func ParseLevel(name string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return DebugLevel, nil
+	case "info":
+		return InfoLevel, nil
+	case "warn", "warning":
+		return WarnLevel, nil
+	case "error":
+		return ErrorLevel, nil
+	case "fatal":
+		return FatalLevel, nil
+	default:
+		return 0, fmt.Errorf("unknown level %q", name)
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler, so levels are encoded by their names, e.g. in JSON.
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using ParseLevel.
+func (l *Level) UnmarshalText(text []byte) error {
+	level, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = level
+
+	return nil
+}
+
+// Enabled reports whether level is at least as severe as l. This makes a Level usable as a static LevelEnabler.
+func (l Level) Enabled(level Level) bool {
+	return level >= l
+}
+
+// LevelEnabler decides whether records at a given level should be written.
+type LevelEnabler interface {
+	// Enabled reports whether records at the given level should be written.
+	Enabled(level Level) bool
+}
+
+// FatalWriter is an optional interface for loggers that can write a fatal record without terminating the process.
+// Wrappers such as Multi use it to make sure every backend has written a fatal record before the process exits.
+type FatalWriter interface {
+	// FatalNoExit returns a LoggerContext for a fatal log that, unlike Fatal, does not exit once sent.
+	FatalNoExit() LoggerContext
+}
+
+// AtLevel returns a LoggerContext of l for the given level.
+func AtLevel(l Logger, level Level) LoggerContext {
+	switch level {
+	case DebugLevel:
+		return l.Debug()
+	case InfoLevel:
+		return l.Info()
+	case WarnLevel:
+		return l.Warn()
+	case ErrorLevel:
+		return l.Error()
+	default:
+		return l.Fatal()
+	}
+}
+
+// FatalNoExit returns a LoggerContext of l for a fatal log that does not exit once sent. The returned bool reports
+// whether l implements FatalWriter; if it does not, l.Fatal() is returned instead.
+func FatalNoExit(l Logger) (LoggerContext, bool) {
+	if fw, ok := l.(FatalWriter); ok {
+		return fw.FatalNoExit(), true
+	}
+
+	return l.Fatal(), false
+}
+
+// levelFilter is a Logger that drops records below a minimum level before any field is added.
+type levelFilter struct {
+	logger  Logger
+	enabler LevelEnabler
+}
+
+// NewLevelFilter returns a Logger that only writes records to l whose level is enabled by enabler. Contexts of
+// disabled levels discard all fields and messages. A Level can be passed as a static minimum level.
+func NewLevelFilter(l Logger, enabler LevelEnabler) Logger {
+	return &levelFilter{
+		logger:  l,
+		enabler: enabler,
+	}
+}
+
+func (f *levelFilter) newContext(level Level) LoggerContext {
+	if !f.enabler.Enabled(level) {
+		return nopContext{fatal: level == FatalLevel} // Fatal records exit even if their level is disabled
+	}
+
+	return AtLevel(f.logger, level)
+}
+
+// With returns the logger with the given fields.
+func (f *levelFilter) With(fields ...any) Logger {
+	return &levelFilter{logger: f.logger.With(fields...), enabler: f.enabler}
+}
+
+// Sync flushes the buffered records of the wrapped logger, if it implements Syncer.
+func (f *levelFilter) Sync() error {
+	return Sync(f.logger)
+}
+
+// Debug returns a LoggerContext for a debug log. To send the log, use the Msg or Msgf methods.
+func (f *levelFilter) Debug() LoggerContext {
+	return f.newContext(DebugLevel)
+}
+
+// Info returns a LoggerContext for an info log. To send the log, use the Msg or Msgf methods.
+func (f *levelFilter) Info() LoggerContext {
+	return f.newContext(InfoLevel)
+}
+
+// Warn returns a LoggerContext for a warn log. To send the log, use the Msg or Msgf methods.
+func (f *levelFilter) Warn() LoggerContext {
+	return f.newContext(WarnLevel)
+}
+
+// Error returns a LoggerContext for an error log. To send the log, use the Msg or Msgf methods.
+func (f *levelFilter) Error() LoggerContext {
+	return f.newContext(ErrorLevel)
+}
+
+// Fatal returns a LoggerContext for a fatal log. To send the log, use the Msg or Msgf methods.
+func (f *levelFilter) Fatal() LoggerContext {
+	return f.newContext(FatalLevel)
+}
+
+// FatalNoExit returns a LoggerContext for a fatal log that does not exit once sent.
+func (f *levelFilter) FatalNoExit() LoggerContext {
+	if !f.enabler.Enabled(FatalLevel) {
+		return nopContext{}
+	}
+
+	ctx, _ := FatalNoExit(f.logger)
+
+	return ctx
+}
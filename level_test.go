@@ -0,0 +1,42 @@
+package onelog_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikoksr/onelog"
+	"github.com/nikoksr/onelog/internal/testutil"
+)
+
+// TestLevelFilter tests if a level filter drops records below the minimum level.
+func TestLevelFilter(t *testing.T) {
+	t.Parallel()
+
+	buff := new(bytes.Buffer)
+	logger := onelog.NewLevelFilter(testutil.NewAdapter(buff), onelog.ErrorLevel).With("test-with", "test")
+
+	logger.Info().Str("Test", "Value").Msg("dropped")
+	assert.Zero(t, buff.Len(), "records below the minimum level should be dropped")
+
+	logger.Error().Str("Test", "Value").Msg("written")
+
+	records := testutil.ParseRecords(t, buff.String())
+	require.Len(t, records, 1)
+	assert.Equal(t, "written", records[0]["message"])
+	assert.Equal(t, "test", records[0]["test-with"], "the record should contain the inherited field")
+}
+
+// TestLevelString tests the string representation of levels.
+func TestLevelString(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "debug", onelog.DebugLevel.String())
+	assert.Equal(t, "info", onelog.InfoLevel.String())
+	assert.Equal(t, "warn", onelog.WarnLevel.String())
+	assert.Equal(t, "error", onelog.ErrorLevel.String())
+	assert.Equal(t, "fatal", onelog.FatalLevel.String())
+	assert.Equal(t, "unknown", onelog.Level(42).String())
+}
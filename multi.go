@@ -0,0 +1,691 @@
+package onelog
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/netip"
+	"net/url"
+	"time"
+)
+
+// Compile-time check that multiLogger and multiContext implement Logger and LoggerContext respectively
+var (
+	_ Logger        = (*multiLogger)(nil)
+	_ FatalWriter   = (*multiLogger)(nil)
+	_ Syncer        = (*multiLogger)(nil)
+	_ LoggerContext = (*multiContext)(nil)
+)
+
+type (
+	// multiLogger is a Logger that fans out every record to a list of child loggers.
+	multiLogger struct {
+		loggers []Logger
+	}
+
+	// multiContext is the LoggerContext of a multiLogger. It replays every call onto the contexts of all children.
+	multiContext struct {
+		contexts []LoggerContext
+		exit     *multiLogger // Set for fatal records; synced before the process exits
+		exiting  int          // Number of trailing contexts that exit the process themselves once sent
+	}
+
+	// multiTemplate is the Template of a multiContext, holding the templates of the child contexts.
+	multiTemplate struct {
+		templates []Template
+		exit      *multiLogger
+		exiting   int
+	}
+)
+
+// Multi returns a Logger that writes every record to all given loggers, in order. To configure a minimum level per
+// child, wrap the child using NewLevelFilter.
+//
+// Records at fatal level are written to all children, which are then synced before the process exits. The process exits
+// through the first child, so that its exit function is used. For this to work, children have to implement
+// FatalWriter, which all adapters of this module do. Children that do not are written to last, which means the first of
+// them terminates the process.
+func Multi(loggers ...Logger) Logger {
+	return &multiLogger{loggers: loggers}
+}
+
+func (m *multiLogger) newContext(level Level) LoggerContext {
+	contexts := make([]LoggerContext, 0, len(m.loggers))
+	for _, l := range m.loggers {
+		ctx := AtLevel(l, level)
+		if _, ok := ctx.(nopContext); ok {
+			continue // Disabled level; skip the child entirely
+		}
+		contexts = append(contexts, ctx)
+	}
+
+	return &multiContext{contexts: contexts}
+}
+
+func (m *multiLogger) newFatalContext(exit bool) LoggerContext {
+	contexts := make([]LoggerContext, 0, len(m.loggers))
+	var exiting []LoggerContext
+	var first Logger // First enabled child that implements FatalWriter
+
+	for _, l := range m.loggers {
+		ctx, ok := FatalNoExit(l)
+		if _, disabled := ctx.(nopContext); disabled {
+			continue
+		}
+		if !ok {
+			exiting = append(exiting, ctx)
+			continue
+		}
+		if exit && first == nil {
+			first = l
+			continue
+		}
+		contexts = append(contexts, ctx)
+	}
+
+	if first != nil {
+		if len(exiting) == 0 {
+			// Written last, so that the process exits through the exit function of the child
+			exiting = append(exiting, first.Fatal())
+		} else {
+			ctx, _ := FatalNoExit(first)
+			contexts = append([]LoggerContext{ctx}, contexts...)
+		}
+	}
+
+	ctx := &multiContext{contexts: append(contexts, exiting...), exiting: len(exiting)}
+	if exit {
+		ctx.exit = m
+	}
+
+	return ctx
+}
+
+// Sync flushes the buffered records of all children that implement Syncer.
+func (m *multiLogger) Sync() error {
+	var errs []error
+	for _, l := range m.loggers {
+		if err := Sync(l); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// With returns the logger with the given fields.
+func (m *multiLogger) With(fields ...any) Logger {
+	loggers := make([]Logger, len(m.loggers))
+	for i, l := range m.loggers {
+		loggers[i] = l.With(fields...)
+	}
+
+	return &multiLogger{loggers: loggers}
+}
+
+// Debug returns a LoggerContext for a debug log. To send the log, use the Msg or Msgf methods.
+func (m *multiLogger) Debug() LoggerContext {
+	return m.newContext(DebugLevel)
+}
+
+// Info returns a LoggerContext for an info log. To send the log, use the Msg or Msgf methods.
+func (m *multiLogger) Info() LoggerContext {
+	return m.newContext(InfoLevel)
+}
+
+// Warn returns a LoggerContext for a warn log. To send the log, use the Msg or Msgf methods.
+func (m *multiLogger) Warn() LoggerContext {
+	return m.newContext(WarnLevel)
+}
+
+// Error returns a LoggerContext for an error log. To send the log, use the Msg or Msgf methods.
+func (m *multiLogger) Error() LoggerContext {
+	return m.newContext(ErrorLevel)
+}
+
+// Fatal returns a LoggerContext for a fatal log. Once sent, the record is written to all children and the process
+// exits.
+func (m *multiLogger) Fatal() LoggerContext {
+	return m.newFatalContext(true)
+}
+
+// FatalNoExit returns a LoggerContext for a fatal log that does not exit once sent.
+func (m *multiLogger) FatalNoExit() LoggerContext {
+	return m.newFatalContext(false)
+}
+
+// Bytes adds the field key with val as a []byte to the logger context.
+func (c *multiContext) Bytes(key string, value []byte) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Bytes(key, value)
+	}
+
+	return c
+}
+
+// Hex adds the field key with val as a hex string to the logger context.
+func (c *multiContext) Hex(key string, value []byte) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Hex(key, value)
+	}
+
+	return c
+}
+
+// RawJSON adds the field key with val as a json.RawMessage to the logger context.
+func (c *multiContext) RawJSON(key string, value []byte) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.RawJSON(key, value)
+	}
+
+	return c
+}
+
+// Str adds the field key with val as a string to the logger context.
+func (c *multiContext) Str(key, value string) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Str(key, value)
+	}
+
+	return c
+}
+
+// Strs adds the field key with val as a []string to the logger context.
+func (c *multiContext) Strs(key string, value []string) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Strs(key, value)
+	}
+
+	return c
+}
+
+// Stringer adds the field key with val as a fmt.Stringer to the logger context.
+func (c *multiContext) Stringer(key string, val fmt.Stringer) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Stringer(key, val)
+	}
+
+	return c
+}
+
+// Stringers adds the field key with val as a []fmt.Stringer to the logger context.
+func (c *multiContext) Stringers(key string, vals []fmt.Stringer) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Stringers(key, vals)
+	}
+
+	return c
+}
+
+// Int adds the field key with val as an int to the logger context.
+func (c *multiContext) Int(key string, value int) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Int(key, value)
+	}
+
+	return c
+}
+
+// Ints adds the field key with val as a []int to the logger context.
+func (c *multiContext) Ints(key string, value []int) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Ints(key, value)
+	}
+
+	return c
+}
+
+// Int8 adds the field key with val as an int8 to the logger context.
+func (c *multiContext) Int8(key string, value int8) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Int8(key, value)
+	}
+
+	return c
+}
+
+// Ints8 adds the field key with val as a []int8 to the logger context.
+func (c *multiContext) Ints8(key string, value []int8) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Ints8(key, value)
+	}
+
+	return c
+}
+
+// Int16 adds the field key with val as an int16 to the logger context.
+func (c *multiContext) Int16(key string, value int16) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Int16(key, value)
+	}
+
+	return c
+}
+
+// Ints16 adds the field key with val as a []int16 to the logger context.
+func (c *multiContext) Ints16(key string, value []int16) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Ints16(key, value)
+	}
+
+	return c
+}
+
+// Int32 adds the field key with val as an int32 to the logger context.
+func (c *multiContext) Int32(key string, value int32) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Int32(key, value)
+	}
+
+	return c
+}
+
+// Ints32 adds the field key with val as a []int32 to the logger context.
+func (c *multiContext) Ints32(key string, value []int32) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Ints32(key, value)
+	}
+
+	return c
+}
+
+// Int64 adds the field key with val as an int64 to the logger context.
+func (c *multiContext) Int64(key string, value int64) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Int64(key, value)
+	}
+
+	return c
+}
+
+// Ints64 adds the field key with val as a []int64 to the logger context.
+func (c *multiContext) Ints64(key string, value []int64) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Ints64(key, value)
+	}
+
+	return c
+}
+
+// Uint adds the field key with val as a uint to the logger context.
+func (c *multiContext) Uint(key string, value uint) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Uint(key, value)
+	}
+
+	return c
+}
+
+// Uints adds the field key with val as a []uint to the logger context.
+func (c *multiContext) Uints(key string, value []uint) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Uints(key, value)
+	}
+
+	return c
+}
+
+// Uint8 adds the field key with val as a uint8 to the logger context.
+func (c *multiContext) Uint8(key string, value uint8) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Uint8(key, value)
+	}
+
+	return c
+}
+
+// Uints8 adds the field key with val as a []uint8 to the logger context.
+func (c *multiContext) Uints8(key string, value []uint8) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Uints8(key, value)
+	}
+
+	return c
+}
+
+// Uint16 adds the field key with val as a uint16 to the logger context.
+func (c *multiContext) Uint16(key string, value uint16) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Uint16(key, value)
+	}
+
+	return c
+}
+
+// Uints16 adds the field key with val as a []uint16 to the logger context.
+func (c *multiContext) Uints16(key string, value []uint16) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Uints16(key, value)
+	}
+
+	return c
+}
+
+// Uint32 adds the field key with val as a uint32 to the logger context.
+func (c *multiContext) Uint32(key string, value uint32) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Uint32(key, value)
+	}
+
+	return c
+}
+
+// Uints32 adds the field key with val as a []uint32 to the logger context.
+func (c *multiContext) Uints32(key string, value []uint32) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Uints32(key, value)
+	}
+
+	return c
+}
+
+// Uint64 adds the field key with val as a uint64 to the logger context.
+func (c *multiContext) Uint64(key string, value uint64) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Uint64(key, value)
+	}
+
+	return c
+}
+
+// Uints64 adds the field key with val as a []uint64 to the logger context.
+func (c *multiContext) Uints64(key string, value []uint64) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Uints64(key, value)
+	}
+
+	return c
+}
+
+// Float32 adds the field key with val as a float32 to the logger context.
+func (c *multiContext) Float32(key string, value float32) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Float32(key, value)
+	}
+
+	return c
+}
+
+// Floats32 adds the field key with val as a []float32 to the logger context.
+func (c *multiContext) Floats32(key string, value []float32) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Floats32(key, value)
+	}
+
+	return c
+}
+
+// Float64 adds the field key with val as a float64 to the logger context.
+func (c *multiContext) Float64(key string, value float64) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Float64(key, value)
+	}
+
+	return c
+}
+
+// Floats64 adds the field key with val as a []float64 to the logger context.
+func (c *multiContext) Floats64(key string, value []float64) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Floats64(key, value)
+	}
+
+	return c
+}
+
+// Bool adds the field key with val as a bool to the logger context.
+func (c *multiContext) Bool(key string, value bool) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Bool(key, value)
+	}
+
+	return c
+}
+
+// Bools adds the field key with val as a []bool to the logger context.
+func (c *multiContext) Bools(key string, value []bool) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Bools(key, value)
+	}
+
+	return c
+}
+
+// Time adds the field key with val as a time.Time to the logger context.
+func (c *multiContext) Time(key string, value time.Time) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Time(key, value)
+	}
+
+	return c
+}
+
+// Times adds the field key with val as a []time.Time to the logger context.
+func (c *multiContext) Times(key string, value []time.Time) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Times(key, value)
+	}
+
+	return c
+}
+
+// Dur adds the field key with val as a time.Duration to the logger context.
+func (c *multiContext) Dur(key string, value time.Duration) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Dur(key, value)
+	}
+
+	return c
+}
+
+// Durs adds the field key with val as a []time.Duration to the logger context.
+func (c *multiContext) Durs(key string, value []time.Duration) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Durs(key, value)
+	}
+
+	return c
+}
+
+// TimeDiff adds the field key with begin and end as a time.Time to the logger context.
+func (c *multiContext) TimeDiff(key string, t, start time.Time) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.TimeDiff(key, t, start)
+	}
+
+	return c
+}
+
+// IPAddr adds the field key with val as a net.IP to the logger context.
+func (c *multiContext) IPAddr(key string, value net.IP) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.IPAddr(key, value)
+	}
+
+	return c
+}
+
+// IPPrefix adds the field key with val as a net.IPNet to the logger context.
+func (c *multiContext) IPPrefix(key string, value net.IPNet) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.IPPrefix(key, value)
+	}
+
+	return c
+}
+
+// MACAddr adds the field key with val as a net.HardwareAddr to the logger context.
+func (c *multiContext) MACAddr(key string, value net.HardwareAddr) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.MACAddr(key, value)
+	}
+
+	return c
+}
+
+// Addr adds the field key with val as a netip.Addr to the logger context.
+func (c *multiContext) Addr(key string, value netip.Addr) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Addr(key, value)
+	}
+
+	return c
+}
+
+// Prefix adds the field key with val as a netip.Prefix to the logger context.
+func (c *multiContext) Prefix(key string, value netip.Prefix) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Prefix(key, value)
+	}
+
+	return c
+}
+
+// AddrPort adds the field key with val as a netip.AddrPort to the logger context.
+func (c *multiContext) AddrPort(key string, value netip.AddrPort) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.AddrPort(key, value)
+	}
+
+	return c
+}
+
+// URL adds the field key with val as a *url.URL to the logger context.
+func (c *multiContext) URL(key string, value *url.URL) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.URL(key, value)
+	}
+
+	return c
+}
+
+// BigInt adds the field key with val as a *big.Int to the logger context.
+func (c *multiContext) BigInt(key string, value *big.Int) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.BigInt(key, value)
+	}
+
+	return c
+}
+
+// BigFloat adds the field key with val as a *big.Float to the logger context.
+func (c *multiContext) BigFloat(key string, value *big.Float) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.BigFloat(key, value)
+	}
+
+	return c
+}
+
+// Decimal adds the field key with val as a decimal number to the logger context.
+func (c *multiContext) Decimal(key string, value fmt.Stringer) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Decimal(key, value)
+	}
+
+	return c
+}
+
+// Err adds the field "error" with val as an error to the logger context.
+func (c *multiContext) Err(err error) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Err(err)
+	}
+
+	return c
+}
+
+// Errs adds the field key with val as a []error to the logger context.
+func (c *multiContext) Errs(key string, errs []error) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Errs(key, errs)
+	}
+
+	return c
+}
+
+// AnErr adds the field key with val as an error to the logger context.
+func (c *multiContext) AnErr(key string, err error) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.AnErr(key, err)
+	}
+
+	return c
+}
+
+// Any adds the field key with val as an arbitrary value to the logger context.
+func (c *multiContext) Any(key string, value any) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Any(key, value)
+	}
+
+	return c
+}
+
+// Fields adds the fields to the logger context.
+func (c *multiContext) Fields(fields Fields) LoggerContext {
+	for _, ctx := range c.contexts {
+		ctx.Fields(fields)
+	}
+
+	return c
+}
+
+// Msg sends the LoggerContext with msg to all child loggers. For fatal records, the children are synced and the
+// process exits once all of them have written the record.
+func (c *multiContext) Msg(msg string) {
+	n := len(c.contexts) - c.exiting
+	for _, ctx := range c.contexts[:n] {
+		ctx.Msg(msg)
+	}
+
+	if c.exit != nil {
+		_ = c.exit.Sync() // The process exits either way; there is nobody left to report the error to
+	}
+
+	for _, ctx := range c.contexts[n:] {
+		ctx.Msg(msg) // The first of them exits the process
+	}
+
+	if c.exit != nil && c.exiting == 0 {
+		Exit(nil) // No child is enabled at fatal level
+	}
+}
+
+// Template returns a snapshot of the contexts of all child loggers.
+func (c *multiContext) Template() Template {
+	templates := make([]Template, len(c.contexts))
+	for i, ctx := range c.contexts {
+		templates[i] = ctx.Template()
+	}
+
+	return &multiTemplate{templates: templates, exit: c.exit, exiting: c.exiting}
+}
+
+// Context returns a new context writing to all child loggers, holding the fields of their templates.
+func (t *multiTemplate) Context() LoggerContext {
+	contexts := make([]LoggerContext, len(t.templates))
+	for i, tmpl := range t.templates {
+		contexts[i] = tmpl.Context()
+	}
+
+	return &multiContext{contexts: contexts, exit: t.exit, exiting: t.exiting}
+}
+
+// Msgf sends the LoggerContext with formatted msg to all child loggers.
+func (c *multiContext) Msgf(format string, v ...any) {
+	c.Msg(fmt.Sprintf(format, v...))
+}
+
+// Send sends the LoggerContext without a message to all child loggers.
+func (c *multiContext) Send() {
+	c.Msg("")
+}
+
+// Discard abandons the contexts of all child loggers without writing the record.
+func (c *multiContext) Discard() {
+	for _, ctx := range c.contexts {
+		ctx.Discard()
+	}
+}
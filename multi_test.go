@@ -0,0 +1,114 @@
+package onelog_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikoksr/onelog"
+	zerologadapter "github.com/nikoksr/onelog/adapter/zerolog"
+	"github.com/nikoksr/onelog/internal/testutil"
+)
+
+// TestMulti tests if a record and its fields are written to every child logger.
+func TestMulti(t *testing.T) {
+	t.Parallel()
+
+	buff1 := new(bytes.Buffer)
+	buff2 := new(bytes.Buffer)
+	logger := onelog.Multi(testutil.NewAdapter(buff1), testutil.NewAdapter(buff2)).With("test-with", "test")
+
+	logger.Info().Str("Test", "Value").Int("Number", 42).Msgf("Test %s", "message")
+
+	for _, buff := range []*bytes.Buffer{buff1, buff2} {
+		records := testutil.ParseRecords(t, buff.String())
+		require.Len(t, records, 1, "exactly one record should be written to each child")
+		assert.Equal(t, "info", records[0]["level"], "the record should have the correct level")
+		assert.Equal(t, "Test message", records[0]["message"], "the record should have the correct message")
+		assert.Equal(t, "Value", records[0]["Test"], "the record should contain the string field")
+		assert.EqualValues(t, 42, records[0]["Number"], "the record should contain the int field")
+		assert.Equal(t, "test", records[0]["test-with"], "the record should contain the inherited field")
+	}
+}
+
+// TestMultiMinLevel tests if children wrapped in a level filter only receive records of enabled levels.
+func TestMultiMinLevel(t *testing.T) {
+	t.Parallel()
+
+	allBuff := new(bytes.Buffer)
+	warnBuff := new(bytes.Buffer)
+	logger := onelog.Multi(
+		testutil.NewAdapter(allBuff),
+		onelog.NewLevelFilter(testutil.NewAdapter(warnBuff), onelog.WarnLevel),
+	)
+
+	logger.Debug().Msg("debug")
+	logger.Info().Msg("info")
+	logger.Warn().Msg("warn")
+	logger.Error().Msg("error")
+
+	assert.Len(t, testutil.ParseRecords(t, allBuff.String()), 4, "the unfiltered child should receive all records")
+
+	records := testutil.ParseRecords(t, warnBuff.String())
+	require.Len(t, records, 2, "the filtered child should only receive warn and error records")
+	assert.Equal(t, "warn", records[0]["message"])
+	assert.Equal(t, "error", records[1]["message"])
+}
+
+// TestMultiFatal tests if a fatal record is written to all children before the process exits.
+//
+//nolint:paralleltest // Replaces the package-level exit function.
+func TestMultiFatal(t *testing.T) {
+	var exitCode int
+	var writtenBeforeExit int
+
+	buff1 := new(bytes.Buffer)
+	buff2 := new(bytes.Buffer)
+
+	restore := onelog.SetExitFunc(func(code int) {
+		exitCode = code
+		writtenBeforeExit = len(testutil.ParseRecords(t, buff1.String())) + len(testutil.ParseRecords(t, buff2.String()))
+	})
+	defer restore()
+
+	logger := onelog.Multi(testutil.NewAdapter(buff1), testutil.NewAdapter(buff2))
+	logger.Fatal().Str("Test", "Value").Msg("fatal")
+
+	assert.Equal(t, 1, exitCode, "the process should exit with code 1")
+	assert.Equal(t, 2, writtenBeforeExit, "both children should have written the record before exiting")
+
+	records := testutil.ParseRecords(t, buff1.String())
+	require.Len(t, records, 1)
+	assert.Equal(t, "fatal", records[0]["level"], "the record should be written at fatal level")
+}
+
+// TestMultiFatalExitFunc tests if a fatal record exits through the exit function of the first child, once all children
+// have written it.
+func TestMultiFatalExitFunc(t *testing.T) {
+	t.Parallel()
+
+	buff1 := new(bytes.Buffer)
+	buff2 := new(bytes.Buffer)
+
+	var exits1, exits2, writtenBeforeExit int
+	exit1 := func(int) {
+		exits1++
+		writtenBeforeExit = len(testutil.ParseRecords(t, buff1.String())) + len(testutil.ParseRecords(t, buff2.String()))
+	}
+	exit2 := func(int) { exits2++ }
+
+	logger := onelog.Multi(
+		testutil.NewAdapter(buff1, zerologadapter.WithExitFunc(exit1)),
+		testutil.NewAdapter(buff2, zerologadapter.WithExitFunc(exit2)),
+	)
+	logger.Fatal().Str("Test", "Value").Msg("fatal")
+
+	assert.Equal(t, 1, exits1, "the exit function of the first child should be called once")
+	assert.Zero(t, exits2, "the exit functions of other children should not be called")
+	assert.Equal(t, 2, writtenBeforeExit, "both children should have written the record before exiting")
+
+	logger.Fatal().Msg("fatal")
+	assert.Equal(t, 2, exits1, "reused loggers should exit through the first child again")
+}
@@ -0,0 +1,109 @@
+package onelog
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+	"net/netip"
+	"net/url"
+	"time"
+)
+
+// Compile-time check that nopLogger and nopContext implement Logger and LoggerContext respectively
+var (
+	_ Logger        = nopLogger{}
+	_ FatalWriter   = nopLogger{}
+	_ LoggerContext = nopContext{}
+	_ Template      = nopTemplate{}
+)
+
+// nopLogger is a Logger that discards everything. It is the default global logger. See the nop adapter for a public
+// no-op Logger.
+type nopLogger struct{}
+
+func (l nopLogger) With(_ ...any) Logger       { return l }
+func (l nopLogger) Debug() LoggerContext       { return nopContext{} }
+func (l nopLogger) Info() LoggerContext        { return nopContext{} }
+func (l nopLogger) Warn() LoggerContext        { return nopContext{} }
+func (l nopLogger) Error() LoggerContext       { return nopContext{} }
+func (l nopLogger) Fatal() LoggerContext       { return nopContext{fatal: true} }
+func (l nopLogger) FatalNoExit() LoggerContext { return nopContext{} }
+
+// nopContext is a LoggerContext that discards everything. It is used for records that were dropped before any field
+// got added, e.g. because their level is disabled. Fatal records still run Exit once sent. See the nop adapter for a
+// public no-op Logger.
+type nopContext struct {
+	fatal bool
+}
+
+func (c nopContext) Bytes(_ string, _ []byte) LoggerContext                    { return c }
+func (c nopContext) Hex(_ string, _ []byte) LoggerContext                      { return c }
+func (c nopContext) RawJSON(_ string, _ []byte) LoggerContext                  { return c }
+func (c nopContext) Str(_, _ string) LoggerContext                             { return c }
+func (c nopContext) Strs(_ string, _ []string) LoggerContext                   { return c }
+func (c nopContext) Stringer(_ string, _ fmt.Stringer) LoggerContext           { return c }
+func (c nopContext) Stringers(_ string, _ []fmt.Stringer) LoggerContext        { return c }
+func (c nopContext) Int(_ string, _ int) LoggerContext                         { return c }
+func (c nopContext) Ints(_ string, _ []int) LoggerContext                      { return c }
+func (c nopContext) Int8(_ string, _ int8) LoggerContext                       { return c }
+func (c nopContext) Ints8(_ string, _ []int8) LoggerContext                    { return c }
+func (c nopContext) Int16(_ string, _ int16) LoggerContext                     { return c }
+func (c nopContext) Ints16(_ string, _ []int16) LoggerContext                  { return c }
+func (c nopContext) Int32(_ string, _ int32) LoggerContext                     { return c }
+func (c nopContext) Ints32(_ string, _ []int32) LoggerContext                  { return c }
+func (c nopContext) Int64(_ string, _ int64) LoggerContext                     { return c }
+func (c nopContext) Ints64(_ string, _ []int64) LoggerContext                  { return c }
+func (c nopContext) Uint(_ string, _ uint) LoggerContext                       { return c }
+func (c nopContext) Uints(_ string, _ []uint) LoggerContext                    { return c }
+func (c nopContext) Uint8(_ string, _ uint8) LoggerContext                     { return c }
+func (c nopContext) Uints8(_ string, _ []uint8) LoggerContext                  { return c }
+func (c nopContext) Uint16(_ string, _ uint16) LoggerContext                   { return c }
+func (c nopContext) Uints16(_ string, _ []uint16) LoggerContext                { return c }
+func (c nopContext) Uint32(_ string, _ uint32) LoggerContext                   { return c }
+func (c nopContext) Uints32(_ string, _ []uint32) LoggerContext                { return c }
+func (c nopContext) Uint64(_ string, _ uint64) LoggerContext                   { return c }
+func (c nopContext) Uints64(_ string, _ []uint64) LoggerContext                { return c }
+func (c nopContext) Float32(_ string, _ float32) LoggerContext                 { return c }
+func (c nopContext) Floats32(_ string, _ []float32) LoggerContext              { return c }
+func (c nopContext) Float64(_ string, _ float64) LoggerContext                 { return c }
+func (c nopContext) Floats64(_ string, _ []float64) LoggerContext              { return c }
+func (c nopContext) Bool(_ string, _ bool) LoggerContext                       { return c }
+func (c nopContext) Bools(_ string, _ []bool) LoggerContext                    { return c }
+func (c nopContext) Time(_ string, _ time.Time) LoggerContext                  { return c }
+func (c nopContext) Times(_ string, _ []time.Time) LoggerContext               { return c }
+func (c nopContext) Dur(_ string, _ time.Duration) LoggerContext               { return c }
+func (c nopContext) Durs(_ string, _ []time.Duration) LoggerContext            { return c }
+func (c nopContext) TimeDiff(_ string, _ time.Time, _ time.Time) LoggerContext { return c }
+func (c nopContext) IPAddr(_ string, _ net.IP) LoggerContext                   { return c }
+func (c nopContext) IPPrefix(_ string, _ net.IPNet) LoggerContext              { return c }
+func (c nopContext) MACAddr(_ string, _ net.HardwareAddr) LoggerContext        { return c }
+func (c nopContext) Addr(_ string, _ netip.Addr) LoggerContext                 { return c }
+func (c nopContext) Prefix(_ string, _ netip.Prefix) LoggerContext             { return c }
+func (c nopContext) AddrPort(_ string, _ netip.AddrPort) LoggerContext         { return c }
+func (c nopContext) URL(_ string, _ *url.URL) LoggerContext                    { return c }
+func (c nopContext) BigInt(_ string, _ *big.Int) LoggerContext                 { return c }
+func (c nopContext) BigFloat(_ string, _ *big.Float) LoggerContext             { return c }
+func (c nopContext) Decimal(_ string, _ fmt.Stringer) LoggerContext            { return c }
+func (c nopContext) Err(_ error) LoggerContext                                 { return c }
+func (c nopContext) Errs(_ string, _ []error) LoggerContext                    { return c }
+func (c nopContext) AnErr(_ string, _ error) LoggerContext                     { return c }
+func (c nopContext) Any(_ string, _ any) LoggerContext                         { return c }
+func (c nopContext) Fields(_ Fields) LoggerContext                             { return c }
+func (c nopContext) Template() Template                                        { return nopTemplate(c) }
+
+func (c nopContext) Msgf(_ string, _ ...any) { c.Msg("") }
+func (c nopContext) Send()                   { c.Msg("") }
+func (c nopContext) Discard()                {}
+
+func (c nopContext) Msg(_ string) {
+	if c.fatal {
+		Exit(nil)
+	}
+}
+
+// nopTemplate is the Template of a nopContext.
+type nopTemplate struct {
+	fatal bool
+}
+
+func (t nopTemplate) Context() LoggerContext { return nopContext(t) }
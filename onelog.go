@@ -2,7 +2,10 @@ package onelog
 
 import (
 	"fmt"
+	"math/big"
 	"net"
+	"net/netip"
+	"net/url"
 	"time"
 )
 
@@ -26,11 +29,24 @@ type Logger interface {
 	// Error returns a LoggerContext for an error log.
 	Error() LoggerContext
 
-	// Fatal returns a LoggerContext for a fatal log.
+	// Fatal returns a LoggerContext for a fatal log. Once sent, the record is written and the backend is flushed, then
+	// Exit runs the registered exit hooks and terminates the process using the configured exit function.
 	Fatal() LoggerContext
 }
 
 // LoggerContext interface provides methods for adding context to logs.
+//
+// A LoggerContext is not safe for concurrent use. It belongs to the goroutine that created it and ends once the record
+// is sent using Msg, Msgf or Send, or abandoned using Discard. Adapters that pool their contexts, as the zap and slog
+// adapters do by default, then take it back, so it must not be used afterwards; other contexts may be reused for
+// another record with the same level. To share a prepared set of fields between goroutines, or to emit many records
+// with them, use Template.
+//
+// All methods accept nil values and render them the same way in every adapter: nil errors, including typed-nil
+// pointers, are omitted; nil Stringers, typed-nil pointers passed to Any or Fields, and nil or empty IP addresses, IP
+// prefixes, MAC addresses and raw JSON, invalid netip values, and nil URLs, big numbers and decimals are rendered as
+// null; nil slices are rendered as empty arrays, and nil elements of Stringers and Errs as null; nil byte slices passed
+// to Bytes or Hex are rendered as empty strings.
 type LoggerContext interface {
 	// Bytes adds the field key with val as a []byte to the logger context.
 	Bytes(key string, value []byte) LoggerContext
@@ -38,7 +54,8 @@ type LoggerContext interface {
 	// Hex adds the field key with val as a hex string to the logger context.
 	Hex(key string, value []byte) LoggerContext
 
-	// RawJSON adds the field key with val as a json.RawMessage to the logger context.
+	// RawJSON adds the field key with val as a json.RawMessage to the logger context. The value is embedded verbatim as
+	// nested JSON; invalid JSON is added as a string instead, so that the record stays valid.
 	RawJSON(key string, value []byte) LoggerContext
 
 	// Str adds the field key with val as a string to the logger context.
@@ -155,6 +172,32 @@ type LoggerContext interface {
 	// MACAddr adds the field key with val as a net.HardwareAddr to the logger context.
 	MACAddr(key string, value net.HardwareAddr) LoggerContext
 
+	// Addr adds the field key with val as a netip.Addr to the logger context.
+	Addr(key string, value netip.Addr) LoggerContext
+
+	// Prefix adds the field key with val as a netip.Prefix to the logger context.
+	Prefix(key string, value netip.Prefix) LoggerContext
+
+	// AddrPort adds the field key with val as a netip.AddrPort to the logger context.
+	AddrPort(key string, value netip.AddrPort) LoggerContext
+
+	// URL adds the field key with val as a *url.URL to the logger context. The user information of the URL, if any,
+	// is replaced by "xxxxx", so that neither passwords nor tokens passed as usernames are logged.
+	URL(key string, value *url.URL) LoggerContext
+
+	// BigInt adds the field key with val as a *big.Int to the logger context. The value is rendered as a number with
+	// all its digits.
+	BigInt(key string, value *big.Int) LoggerContext
+
+	// BigFloat adds the field key with val as a *big.Float to the logger context. The value is rendered as a number
+	// with all digits of its precision; infinite values are rendered as strings.
+	BigFloat(key string, value *big.Float) LoggerContext
+
+	// Decimal adds the field key with val as a decimal number to the logger context. Val is typically a decimal type
+	// such as shopspring/decimal.Decimal; its String method must return the number, which is then rendered verbatim as
+	// a number. Strings that are not valid numbers are rendered as strings.
+	Decimal(key string, value fmt.Stringer) LoggerContext
+
 	// Err adds the key "error" with val as an error to the logger context.
 	Err(err error) LoggerContext
 
@@ -167,12 +210,36 @@ type LoggerContext interface {
 	// Any adds the field key with val as an interface{} to the logger context.
 	Any(key string, value any) LoggerContext
 
-	// Fields adds the field key with val as a Fields to the logger context.
+	// Fields adds all fields to the logger context, in the order of their sorted keys, so that the output does not
+	// depend on the iteration order of the map.
 	Fields(fields Fields) LoggerContext
 
+	// Template returns an immutable snapshot of the level and the fields added to the context so far. The context is
+	// left unchanged and may be used further.
+	Template() Template
+
 	// Msg sends the LoggerContext with msg to the logger.
 	Msg(msg string)
 
 	// Msgf sends the LoggerContext with formatted msg to the logger.
 	Msgf(format string, v ...any)
+
+	// Send sends the LoggerContext to the logger without a message. It is equivalent to Msg(""), so the message is
+	// rendered the way the backend renders empty messages: zerolog omits the message key, while zap and slog write
+	// it with an empty value.
+	Send()
+
+	// Discard abandons the LoggerContext without writing the record, like Msg would reset or release it. Discarding a
+	// fatal record does not terminate the process.
+	Discard()
+}
+
+// Template is an immutable snapshot of the level and fields of a LoggerContext, see LoggerContext.Template. It is safe
+// for concurrent use: every record is emitted from a context of its own, so a template can be shared by any number of
+// goroutines and used for any number of records. Values are snapshotted as far as the backend allows; values passed
+// to Any or Fields, such as maps and pointers, are shared and must not be modified afterwards.
+type Template interface {
+	// Context returns a new LoggerContext holding the level and fields of the template. Fields added to it do not
+	// affect the template.
+	Context() LoggerContext
 }
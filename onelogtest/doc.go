@@ -0,0 +1,16 @@
+// Package onelogtest provides a conformance test suite for onelog.Logger implementations. Adapter authors can run it
+// from their own tests to verify that every LoggerContext method encodes its value correctly, that With, level mapping,
+// Send, Discard and context reuse behave like the adapters of this module, that fatal records write, run the exit hooks
+// and exit in that order, and that the logger and the templates of its contexts are safe for concurrent use:
+//
+//	func TestConformance(t *testing.T) {
+//		onelogtest.Run(t, onelogtest.Config{
+//			NewLogger: func(out io.Writer) onelog.Logger { return myadapter.New(out) },
+//		})
+//	}
+//
+// To verify fatal records without replacing the process-wide exit function, set Config.NewFatalLogger to build the
+// logger with the injected exit function, and set Config.NewEncodingLogger to verify that non-default onelog.Encoding
+// policies are honoured. Records are parsed as JSON by default; set Config.Parser to verify backends writing other
+// formats, and set Config.Pooled for loggers that return their contexts to a pool once sent.
+package onelogtest
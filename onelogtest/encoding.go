@@ -0,0 +1,143 @@
+package onelogtest
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikoksr/onelog"
+)
+
+// encodingValues are the values written by RunEncoding.
+type encodingValues struct {
+	time  time.Time
+	dur   time.Duration
+	bytes []byte
+	err   error
+}
+
+func newEncodingValues() encodingValues {
+	return encodingValues{
+		time:  time.Date(2023, 4, 5, 6, 7, 8, 9_000_000, time.UTC),
+		dur:   1500 * time.Millisecond,
+		bytes: []byte("onelog"),
+		err:   errors.New("failed"),
+	}
+}
+
+// write writes a record with every value through every method that may carry it: the typed methods, Any, Fields and
+// With.
+func (v encodingValues) write(logger onelog.Logger) {
+	logger.
+		With("with-time", v.time, "with-dur", v.dur, "with-bytes", v.bytes, "with-err", v.err).
+		Info().
+		Time("typed-time", v.time).
+		Times("typed-times", []time.Time{v.time}).
+		Dur("typed-dur", v.dur).
+		Durs("typed-durs", []time.Duration{v.dur}).
+		TimeDiff("typed-diff", v.time.Add(v.dur), v.time).
+		Bytes("typed-bytes", v.bytes).
+		AnErr("typed-err", v.err).
+		Errs("typed-errs", []error{v.err}).
+		Any("any-time", v.time).
+		Any("any-dur", v.dur).
+		Any("any-bytes", v.bytes).
+		Any("any-err", v.err).
+		Fields(onelog.Fields{"fields-time": v.time, "fields-dur": v.dur, "fields-bytes": v.bytes, "fields-err": v.err}).
+		Msg("encoding")
+}
+
+// RunEncoding verifies that times, durations, bytes and errors are rendered according to onelog.Encoding, regardless
+// of whether they are added using their typed method, Any, Fields or With. The logger returned by Config.NewLogger
+// must use the default encoding; if Config.NewEncodingLogger is set, a set of non-default encodings is verified too.
+func RunEncoding(t *testing.T, cfg Config) {
+	t.Helper()
+
+	values := newEncodingValues()
+
+	t.Run("Default", func(t *testing.T) {
+		buff := new(syncBuffer)
+		values.write(cfg.NewLogger(buff))
+		record := cfg.parseOne(t, buff)
+
+		// Spell out the default shapes instead of deriving them from onelog.Encoding, so that changes to the defaults
+		// don't go unnoticed.
+		for _, prefix := range []string{"typed-", "any-", "fields-", "with-"} {
+			assert.Equal(t, "2023-04-05T06:07:08.009Z", record[prefix+"time"], "times should be RFC 3339 strings")
+			assert.Equal(t, 1.5e9, record[prefix+"dur"], "durations should be nanoseconds")
+			assert.Equal(t, "onelog", record[prefix+"bytes"], "bytes should be strings")
+			assert.Equal(t, "failed", record[prefix+"err"], "errors should be their message")
+		}
+
+		assert.Equal(t, []any{"2023-04-05T06:07:08.009Z"}, record["typed-times"])
+		assert.Equal(t, []any{1.5e9}, record["typed-durs"])
+		assert.Equal(t, 1.5e9, record["typed-diff"], "time differences should be t minus start")
+		assert.Equal(t, []any{"failed"}, record["typed-errs"])
+	})
+
+	if cfg.NewEncodingLogger == nil {
+		return
+	}
+
+	encodings := map[string]onelog.Encoding{
+		"Zero": {},
+		"Unix": {
+			TimeFormat:     onelog.TimeFormatUnix,
+			DurationFormat: onelog.DurationSeconds,
+			BytesFormat:    onelog.BytesBase64,
+			ErrorFormat:    onelog.ErrorObject,
+		},
+		"UnixMs": {
+			TimeFormat:     onelog.TimeFormatUnixMs,
+			DurationFormat: onelog.DurationMillis,
+			BytesFormat:    onelog.BytesHex,
+		},
+		"UnixNano": {
+			TimeFormat:     onelog.TimeFormatUnixNano,
+			DurationFormat: onelog.DurationMicros,
+		},
+		"Layout": {
+			TimeFormat:     time.DateOnly,
+			DurationFormat: onelog.DurationString,
+			ErrorFormat:    onelog.ErrorObject,
+		},
+	}
+
+	for name, enc := range encodings {
+		enc := enc
+		t.Run(name, func(t *testing.T) {
+			buff := new(syncBuffer)
+			values.write(cfg.NewEncodingLogger(buff, enc))
+			record := cfg.parseOne(t, buff)
+
+			for _, prefix := range []string{"typed-", "any-", "fields-", "with-"} {
+				assert.Equal(t, jsonValue(t, enc.EncodeTime(values.time)), record[prefix+"time"])
+				assert.Equal(t, jsonValue(t, enc.EncodeDuration(values.dur)), record[prefix+"dur"])
+				assert.Equal(t, jsonValue(t, enc.EncodeBytes(values.bytes)), record[prefix+"bytes"])
+				assert.Equal(t, jsonValue(t, enc.EncodeError(values.err)), record[prefix+"err"])
+			}
+
+			assert.Equal(t, jsonValue(t, enc.EncodeTimes([]time.Time{values.time})), record["typed-times"])
+			assert.Equal(t, jsonValue(t, enc.EncodeDurations([]time.Duration{values.dur})), record["typed-durs"])
+			assert.Equal(t, jsonValue(t, enc.EncodeDuration(values.dur)), record["typed-diff"])
+			assert.Equal(t, jsonValue(t, enc.EncodeErrors([]error{values.err})), record["typed-errs"])
+		})
+	}
+}
+
+// jsonValue returns value as decoded by encoding/json, so that it compares equal to the values of a Record.
+func jsonValue(t *testing.T, value any) any {
+	t.Helper()
+
+	data, err := json.Marshal(value)
+	require.NoError(t, err)
+
+	var decoded any
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	return decoded
+}
@@ -0,0 +1,287 @@
+package onelogtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikoksr/onelog"
+)
+
+// UpdateGoldenEnv is the environment variable that makes AssertGolden rewrite the golden files instead of comparing
+// against them if set to a true value as understood by strconv.ParseBool, e.g. "ONELOGTEST_UPDATE=1 go test".
+const UpdateGoldenEnv = "ONELOGTEST_UPDATE"
+
+// Placeholders of normalized values.
+const (
+	TimePlaceholder     = "<time>"
+	DurationPlaceholder = "<duration>"
+	CallerPlaceholder   = "<caller>"
+	PIDPlaceholder      = "<pid>"
+)
+
+var (
+	// volatileKeys maps the keys of fields that differ between runs to their placeholders.
+	volatileKeys = map[string]string{
+		"time":      TimePlaceholder,
+		"timestamp": TimePlaceholder,
+		"ts":        TimePlaceholder,
+		"caller":    CallerPlaceholder,
+		"source":    CallerPlaceholder,
+		"pid":       PIDPlaceholder,
+		"duration":  DurationPlaceholder,
+		"elapsed":   DurationPlaceholder,
+		"latency":   DurationPlaceholder,
+	}
+
+	durationPattern = regexp.MustCompile(`^-?\d+(\.\d+)?(ns|us|µs|ms|s|m|h)([\d.]+(ns|us|µs|ms|s|m|h))*$`)
+	callerPattern   = regexp.MustCompile(`^\S+\.go:\d+$`)
+)
+
+type (
+	// Normalizer replaces volatile values with stable ones. It is called for every value, including nested ones, with
+	// the key of the innermost enclosing field, and returns the value to compare.
+	Normalizer func(key string, value any) any
+
+	// GoldenOption configures AssertGolden.
+	GoldenOption func(*golden)
+
+	golden struct {
+		dir         string
+		keys        map[string]string
+		normalizers []Normalizer
+	}
+)
+
+// WithGoldenDir sets the directory golden files are stored in. The default is "testdata".
+func WithGoldenDir(dir string) GoldenOption {
+	return func(g *golden) {
+		g.dir = dir
+	}
+}
+
+// WithVolatileKeys replaces the values of fields with the given keys by placeholder, in addition to the default
+// volatile keys.
+func WithVolatileKeys(placeholder string, keys ...string) GoldenOption {
+	return func(g *golden) {
+		for _, key := range keys {
+			g.keys[key] = placeholder
+		}
+	}
+}
+
+// WithNormalizer adds a normalizer that runs after the default normalization.
+func WithNormalizer(n Normalizer) GoldenOption {
+	return func(g *golden) {
+		g.normalizers = append(g.normalizers, n)
+	}
+}
+
+// Capture runs fn with a logger built by cfg.NewLogger and returns the records it wrote, parsed by cfg.Parser.
+func Capture(t *testing.T, cfg Config, fn func(l onelog.Logger)) []Record {
+	t.Helper()
+
+	buff := new(syncBuffer)
+	fn(cfg.NewLogger(buff))
+
+	return cfg.parse(t, buff)
+}
+
+// AssertGolden normalizes the records and compares them against the golden file <dir>/<name>.golden.json. Values that
+// differ between runs are replaced by placeholders: fields with well-known keys such as "time", "caller" or "pid", and
+// strings that look like timestamps, durations or callers. Differences are reported per record and field.
+//
+// Set UpdateGoldenEnv to write the golden files instead.
+func AssertGolden(t *testing.T, name string, records []Record, opts ...GoldenOption) {
+	t.Helper()
+
+	update, _ := strconv.ParseBool(os.Getenv(UpdateGoldenEnv))
+	assertGolden(t, name, records, update, opts...)
+}
+
+func assertGolden(t *testing.T, name string, records []Record, updateFile bool, opts ...GoldenOption) {
+	t.Helper()
+
+	g := &golden{
+		dir:  "testdata",
+		keys: make(map[string]string, len(volatileKeys)),
+	}
+	for key, placeholder := range volatileKeys {
+		g.keys[key] = placeholder
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	got := make([]Record, len(records))
+	for i, record := range records {
+		got[i] = g.normalizeRecord(record)
+	}
+
+	path := filepath.Join(g.dir, name+".golden.json")
+	if updateFile {
+		var data bytes.Buffer
+		encoder := json.NewEncoder(&data)
+		encoder.SetEscapeHTML(false) // Keep the placeholders readable
+		encoder.SetIndent("", "  ")
+		require.NoError(t, encoder.Encode(got), "the records should be encodable as JSON")
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, data.Bytes(), 0o644)) //nolint:gosec // Golden files are not secret
+
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err, "the golden file should exist; run the tests with "+UpdateGoldenEnv+"=1 to create it")
+
+	var want []Record
+	require.NoError(t, json.Unmarshal(data, &want), "the golden file should be valid JSON")
+
+	// Round-trip the records through JSON, so that both sides use the same types
+	encoded, err := json.Marshal(got)
+	require.NoError(t, err, "the records should be encodable as JSON")
+	got = nil
+	require.NoError(t, json.Unmarshal(encoded, &got))
+
+	if diffs := diffRecords(want, got); len(diffs) > 0 {
+		t.Errorf("log output differs from %s (run the tests with %s=1 to accept it):\n\t%s", path, UpdateGoldenEnv,
+			strings.Join(diffs, "\n\t"))
+	}
+}
+
+func (g *golden) normalizeRecord(record Record) Record {
+	normalized := make(Record, len(record))
+	for key, value := range record {
+		normalized[key] = g.normalize(key, value)
+	}
+
+	return normalized
+}
+
+func (g *golden) normalize(key string, value any) any {
+	if placeholder, ok := g.keys[key]; ok && value != nil {
+		value = placeholder
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		normalized := make(map[string]any, len(v))
+		for k, elem := range v {
+			normalized[k] = g.normalize(k, elem)
+		}
+		value = normalized
+	case Record:
+		value = g.normalizeRecord(v)
+	case []any:
+		normalized := make([]any, len(v))
+		for i, elem := range v {
+			normalized[i] = g.normalize(key, elem)
+		}
+		value = normalized
+	case string:
+		value = normalizeString(v)
+	}
+
+	for _, n := range g.normalizers {
+		value = n(key, value)
+	}
+
+	return value
+}
+
+// normalizeString replaces strings that look like timestamps, durations or callers.
+func normalizeString(s string) string {
+	switch {
+	case len(s) >= len("2006-01-02T15:04:05Z") && isTime(s):
+		return TimePlaceholder
+	case durationPattern.MatchString(s):
+		return DurationPlaceholder
+	case callerPattern.MatchString(s):
+		return CallerPlaceholder
+	default:
+		return s
+	}
+}
+
+func isTime(s string) bool {
+	_, err := time.Parse(time.RFC3339Nano, s)
+	return err == nil
+}
+
+// diffRecords describes the differences between two lists of records, one line per differing field.
+func diffRecords(want, got []Record) []string {
+	var diffs []string
+	if len(want) != len(got) {
+		diffs = append(diffs, fmt.Sprintf("record count: want %d, got %d", len(want), len(got)))
+	}
+
+	for i := 0; i < len(want) || i < len(got); i++ {
+		switch {
+		case i >= len(got):
+			diffs = append(diffs, fmt.Sprintf("record %d: missing %s", i, encode(want[i])))
+		case i >= len(want):
+			diffs = append(diffs, fmt.Sprintf("record %d: unexpected %s", i, encode(got[i])))
+		default:
+			diffs = append(diffs, diffRecord(i, want[i], got[i])...)
+		}
+	}
+
+	return diffs
+}
+
+func diffRecord(index int, want, got Record) []string {
+	keys := make(map[string]bool, len(want)+len(got))
+	for key := range want {
+		keys[key] = true
+	}
+	for key := range got {
+		keys[key] = true
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	var diffs []string
+	for _, key := range sorted {
+		wantValue, inWant := want[key]
+		gotValue, inGot := got[key]
+
+		switch {
+		case !inGot:
+			diffs = append(diffs, fmt.Sprintf("record %d: field %q missing, want %s", index, key, encode(wantValue)))
+		case !inWant:
+			diffs = append(diffs, fmt.Sprintf("record %d: field %q unexpected, got %s", index, key, encode(gotValue)))
+		case !reflect.DeepEqual(wantValue, gotValue):
+			diffs = append(diffs, fmt.Sprintf("record %d: field %q: want %s, got %s", index, key, encode(wantValue),
+				encode(gotValue)))
+		}
+	}
+
+	return diffs
+}
+
+// encode returns the compact JSON encoding of a value for diff output.
+func encode(value any) string {
+	var buff bytes.Buffer
+	encoder := json.NewEncoder(&buff)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+
+	return strings.TrimSpace(buff.String())
+}
@@ -0,0 +1,130 @@
+package onelogtest
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikoksr/onelog"
+	zerologadapter "github.com/nikoksr/onelog/adapter/zerolog"
+)
+
+var goldenConfig = Config{
+	NewLogger: func(out io.Writer) onelog.Logger {
+		logger := zerolog.New(out).With().Timestamp().Caller().Int("pid", os.Getpid()).Logger()
+		return zerologadapter.NewAdapter(&logger)
+	},
+}
+
+func logRequests(l onelog.Logger) {
+	l = l.With("service", "api")
+
+	start := time.Now()
+	l.Info().
+		Str("method", "GET").
+		Int("status", 200).
+		Str("took", time.Since(start).String()).
+		Time("started_at", start).
+		Msg("request handled")
+	l.Error().Err(errors.New("connection refused")).Strs("hosts", []string{"db-1", "db-2"}).Msg("db unreachable")
+}
+
+// TestAssertGolden tests if captured records are normalized and match the checked-in golden file.
+func TestAssertGolden(t *testing.T) {
+	t.Parallel()
+
+	AssertGolden(t, "requests", Capture(t, goldenConfig, logRequests))
+}
+
+// TestAssertGoldenUpdate tests if golden files are written on update and compared against afterwards.
+func TestAssertGoldenUpdate(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "golden")
+	records := Capture(t, goldenConfig, logRequests)
+
+	assertGolden(t, "requests", records, true, WithGoldenDir(dir), WithVolatileKeys("<id>", "request_id"))
+	require.FileExists(t, filepath.Join(dir, "requests.golden.json"))
+
+	// Records of a later run differ in timestamps, callers and durations only
+	time.Sleep(time.Millisecond)
+	assertGolden(t, "requests", Capture(t, goldenConfig, logRequests), false, WithGoldenDir(dir))
+}
+
+// TestAssertGoldenUpdateEnv tests if AssertGolden writes the golden files when UpdateGoldenEnv is set.
+//
+//nolint:paralleltest // t.Setenv cannot be used in parallel tests
+func TestAssertGoldenUpdateEnv(t *testing.T) {
+	t.Setenv(UpdateGoldenEnv, "true")
+
+	dir := filepath.Join(t.TempDir(), "golden")
+	AssertGolden(t, "requests", Capture(t, goldenConfig, logRequests), WithGoldenDir(dir))
+	require.FileExists(t, filepath.Join(dir, "requests.golden.json"))
+}
+
+// TestNormalize tests if volatile values are replaced by placeholders.
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+
+	g := &golden{keys: map[string]string{"time": TimePlaceholder, "request_id": "<id>"}}
+	g.normalizers = append(g.normalizers, func(key string, value any) any {
+		if key == "host" {
+			return "<host>"
+		}
+		return value
+	})
+
+	normalized := g.normalizeRecord(Record{
+		"time":       1700000000.0,
+		"request_id": "abc",
+		"started":    "2023-01-02T03:04:05.123456789Z",
+		"took":       "1.5ms",
+		"at":         "server/handler.go:42",
+		"host":       "db-1",
+		"nested":     map[string]any{"elapsed": "2h3m", "list": []any{"1s", "text"}},
+		"message":    "5 retries",
+	})
+
+	assert.Equal(t, Record{
+		"time":       TimePlaceholder,
+		"request_id": "<id>",
+		"started":    TimePlaceholder,
+		"took":       DurationPlaceholder,
+		"at":         CallerPlaceholder,
+		"host":       "<host>",
+		"nested":     map[string]any{"elapsed": DurationPlaceholder, "list": []any{DurationPlaceholder, "text"}},
+		"message":    "5 retries",
+	}, normalized)
+}
+
+// TestDiffRecords tests if differences are reported per record and field.
+func TestDiffRecords(t *testing.T) {
+	t.Parallel()
+
+	want := []Record{
+		{"msg": "request handled", "status": 200.0, "method": "GET"},
+		{"msg": "db unreachable"},
+	}
+	got := []Record{
+		{"msg": "request handled", "status": 500.0, "path": "/"},
+		{"msg": "db unreachable"},
+		{"msg": "extra"},
+	}
+
+	assert.Equal(t, []string{
+		"record count: want 2, got 3",
+		`record 0: field "method" missing, want "GET"`,
+		`record 0: field "path" unexpected, got "/"`,
+		`record 0: field "status": want 200, got 500`,
+		`record 2: unexpected {"msg":"extra"}`,
+	}, diffRecords(want, got))
+
+	assert.Empty(t, diffRecords(want, want))
+}
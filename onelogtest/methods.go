@@ -1,11 +1,13 @@
-package testutils
+package onelogtest
 
 import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"net"
-	"strings"
+	"net/netip"
+	"net/url"
 	"testing"
 	"time"
 
@@ -15,24 +17,10 @@ import (
 	"github.com/nikoksr/onelog"
 )
 
-func parseLogRecord(t *testing.T, buff *bytes.Buffer) map[string]any {
-	t.Helper()
-
-	require.NotNil(t, buff, "the log buffer should not be nil")
-	require.Greater(t, buff.Len(), 0, "the log buffer should not be empty")
-
-	// Some logger prefix the log with a timestamp, so we need to remove it before unmarshalling the log
-	msg := buff.String()
-	idx := strings.Index(msg, "{")
-	msg = msg[idx:]
+// decimal is a decimal type as used by Decimal, e.g. shopspring/decimal.Decimal.
+type decimal string
 
-	// Parse the log
-	result := make(map[string]any)
-	err := json.Unmarshal([]byte(msg), &result)
-	assert.NoError(t, err, "the log should be valid json")
-
-	return result
-}
+func (d decimal) String() string { return string(d) }
 
 func assertEqualSlices(t *testing.T, expected any, actual any) {
 	t.Helper()
@@ -50,20 +38,16 @@ func assertEqualSlices(t *testing.T, expected any, actual any) {
 	}
 }
 
-func validateRawJSON(t *testing.T, value interface{}) {
+func validateRawJSON(t *testing.T, expected string, value interface{}) {
 	t.Helper()
 
-	switch v := value.(type) {
-	case string:
-		var js map[string]interface{}
-		err := json.Unmarshal([]byte(v), &js)
-		require.NoError(t, err, "the log should contain valid json string, but got: %s", v)
-	case map[string]interface{}:
-		_, err := json.Marshal(v)
-		require.NoError(t, err, "the log should contain value that can be marshaled to json, but got: %v", v)
-	default:
-		t.Errorf("Unexpected type for raw JSON value: %T", v)
-	}
+	// Raw JSON has to be embedded as a nested value; a string holding the JSON is not enough
+	v, ok := value.(map[string]interface{})
+	require.True(t, ok, "the log should contain the raw JSON as an object, but got %T: %v", value, value)
+
+	var want map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(expected), &want))
+	assert.Equal(t, want, v, "the log should contain the raw JSON verbatim")
 }
 
 func validateTimestamp(t *testing.T, expected time.Time, got any) {
@@ -147,7 +131,7 @@ func validateErrors(t *testing.T, expected []error, got any) {
 type testCase struct {
 	Name            string
 	Fn              func() onelog.LoggerContext
-	ValidateMethods func(t *testing.T, result map[string]any)
+	ValidateMethods func(t *testing.T, result Record)
 }
 
 func getMethodsTests(logContext onelog.LoggerContext) []testCase {
@@ -164,7 +148,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 		{
 			Name: "Str",
 			Fn:   func() onelog.LoggerContext { return logContext.Str("Test", "Value") },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -174,7 +158,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 		{
 			Name: "Strs",
 			Fn:   func() onelog.LoggerContext { return logContext.Strs("Test", []string{"Value1", "Value2"}) },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -184,7 +168,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 		{
 			Name: "Bytes",
 			Fn:   func() onelog.LoggerContext { return logContext.Bytes("Test", []byte("Test")) },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -194,7 +178,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 		{
 			Name: "Hex",
 			Fn:   func() onelog.LoggerContext { return logContext.Hex("Test", []byte{0x01, 0x02, 0x03}) },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -203,18 +187,29 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 		},
 		{
 			Name: "RawJSON",
-			Fn:   func() onelog.LoggerContext { return logContext.RawJSON("Test", []byte(`{"test": "test"}`)) },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			Fn: func() onelog.LoggerContext {
+				return logContext.RawJSON("Test", []byte(`{"test": "test", "nested": {"list": [1, true, null]}}`))
+			},
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
-				validateRawJSON(t, value)
+				validateRawJSON(t, `{"test": "test", "nested": {"list": [1, true, null]}}`, value)
+			},
+		},
+		{
+			Name: "RawJSONInvalid",
+			Fn:   func() onelog.LoggerContext { return logContext.RawJSON("Test", []byte(`{"test": `)) },
+			ValidateMethods: func(t *testing.T, result Record) {
+				t.Helper()
+				// Invalid JSON must not break the record; it is written as a string instead
+				assert.Equal(t, `{"test": `, result["Test"], "the log should contain the invalid JSON as a string")
 			},
 		},
 		{
 			Name: "Stringer",
 			Fn:   func() onelog.LoggerContext { return logContext.Stringer("Test", stringer1) },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -226,7 +221,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 			Fn: func() onelog.LoggerContext {
 				return logContext.Stringers("Test", []fmt.Stringer{stringer1, stringer2})
 			},
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -236,7 +231,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 		{
 			Name: "Int",
 			Fn:   func() onelog.LoggerContext { return logContext.Int("Test", 42) },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -246,7 +241,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 		{
 			Name: "Ints",
 			Fn:   func() onelog.LoggerContext { return logContext.Ints("Test", []int{1, 2, 3}) },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -256,7 +251,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 		{
 			Name: "Int8",
 			Fn:   func() onelog.LoggerContext { return logContext.Int8("Test", 42) },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -266,7 +261,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 		{
 			Name: "Ints8",
 			Fn:   func() onelog.LoggerContext { return logContext.Ints8("Test", []int8{1, 2, 3}) },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -276,7 +271,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 		{
 			Name: "Int16",
 			Fn:   func() onelog.LoggerContext { return logContext.Int16("Test", 42) },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -286,7 +281,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 		{
 			Name: "Ints16",
 			Fn:   func() onelog.LoggerContext { return logContext.Ints16("Test", []int16{1, 2, 3}) },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -296,7 +291,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 		{
 			Name: "Int32",
 			Fn:   func() onelog.LoggerContext { return logContext.Int32("Test", 42) },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -306,7 +301,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 		{
 			Name: "Ints32",
 			Fn:   func() onelog.LoggerContext { return logContext.Ints32("Test", []int32{1, 2, 3}) },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -316,7 +311,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 		{
 			Name: "Int64",
 			Fn:   func() onelog.LoggerContext { return logContext.Int64("Test", 42) },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -326,7 +321,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 		{
 			Name: "Ints64",
 			Fn:   func() onelog.LoggerContext { return logContext.Ints64("Test", []int64{1, 2, 3}) },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -336,7 +331,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 		{
 			Name: "Uint",
 			Fn:   func() onelog.LoggerContext { return logContext.Uint("Test", 42) },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -346,7 +341,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 		{
 			Name: "Uints",
 			Fn:   func() onelog.LoggerContext { return logContext.Uints("Test", []uint{1, 2, 3}) },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -356,7 +351,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 		{
 			Name: "Uint8",
 			Fn:   func() onelog.LoggerContext { return logContext.Uint8("Test", 42) },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -366,9 +361,8 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 		{
 			Name: "Uints8",
 			Fn:   func() onelog.LoggerContext { return logContext.Uints8("Test", []uint8{1, 2, 3}) },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
-				t.Log(result)
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
 				assertEqualSlices(t, []any{uint8(1), uint8(2), uint8(3)}, value)
@@ -377,7 +371,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 		{
 			Name: "Uint16",
 			Fn:   func() onelog.LoggerContext { return logContext.Uint16("Test", 42) },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -387,7 +381,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 		{
 			Name: "Uints16",
 			Fn:   func() onelog.LoggerContext { return logContext.Uints16("Test", []uint16{1, 2, 3}) },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -397,7 +391,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 		{
 			Name: "Uint32",
 			Fn:   func() onelog.LoggerContext { return logContext.Uint32("Test", 42) },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -407,7 +401,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 		{
 			Name: "Uints32",
 			Fn:   func() onelog.LoggerContext { return logContext.Uints32("Test", []uint32{1, 2, 3}) },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -417,7 +411,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 		{
 			Name: "Uint64",
 			Fn:   func() onelog.LoggerContext { return logContext.Uint64("Test", 42) },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -427,7 +421,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 		{
 			Name: "Uints64",
 			Fn:   func() onelog.LoggerContext { return logContext.Uints64("Test", []uint64{1, 2, 3}) },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -437,7 +431,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 		{
 			Name: "Float32",
 			Fn:   func() onelog.LoggerContext { return logContext.Float32("Test", 42.42) },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -447,7 +441,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 		{
 			Name: "Floats32",
 			Fn:   func() onelog.LoggerContext { return logContext.Floats32("Test", []float32{1.1, 2.2, 3.3}) },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -457,7 +451,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 		{
 			Name: "Float64",
 			Fn:   func() onelog.LoggerContext { return logContext.Float64("Test", 42.42) },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -467,7 +461,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 		{
 			Name: "Floats64",
 			Fn:   func() onelog.LoggerContext { return logContext.Floats64("Test", []float64{1.1, 2.2, 3.3}) },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -477,7 +471,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 		{
 			Name: "Bool",
 			Fn:   func() onelog.LoggerContext { return logContext.Bool("Test", true) },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -487,7 +481,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 		{
 			Name: "Bools",
 			Fn:   func() onelog.LoggerContext { return logContext.Bools("Test", []bool{true, false, true}) },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -497,7 +491,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 		{
 			Name: "Time",
 			Fn:   func() onelog.LoggerContext { return logContext.Time("Test", now()) },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -507,7 +501,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 		{
 			Name: "Times",
 			Fn:   func() onelog.LoggerContext { return logContext.Times("Test", []time.Time{now(), now()}) },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -517,7 +511,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 		{
 			Name: "Dur",
 			Fn:   func() onelog.LoggerContext { return logContext.Dur("Test", time.Second) },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -527,7 +521,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 		{
 			Name: "Durs",
 			Fn:   func() onelog.LoggerContext { return logContext.Durs("Test", []time.Duration{time.Second, time.Second}) },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -537,7 +531,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 		{
 			Name: "TimeDiff",
 			Fn:   func() onelog.LoggerContext { return logContext.TimeDiff("Test", now(), now()) },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -547,7 +541,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 		{
 			Name: "IPAddr",
 			Fn:   func() onelog.LoggerContext { return logContext.IPAddr("Test", net.IP{127, 0, 0, 1}) },
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -561,7 +555,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 			Fn: func() onelog.LoggerContext {
 				return logContext.IPPrefix("Test", net.IPNet{IP: net.IP{127, 0, 0, 1}, Mask: net.IPMask{255, 255, 255, 0}})
 			},
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -575,7 +569,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 			Fn: func() onelog.LoggerContext {
 				return logContext.MACAddr("Test", net.HardwareAddr{0, 0, 0, 0, 0, 0})
 			},
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["Test"]
 				require.True(t, ok, "the log should contain the key 'Test'")
@@ -584,12 +578,103 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 				assert.EqualValues(t, expected.String(), value, "the log should contain the correct value")
 			},
 		},
+		{
+			Name: "Addr",
+			Fn:   func() onelog.LoggerContext { return logContext.Addr("Test", netip.MustParseAddr("2001:db8::1")) },
+			ValidateMethods: func(t *testing.T, result Record) {
+				t.Helper()
+				assert.Equal(t, "2001:db8::1", result["Test"], "the log should contain the correct value")
+			},
+		},
+		{
+			Name: "Prefix",
+			Fn:   func() onelog.LoggerContext { return logContext.Prefix("Test", netip.MustParsePrefix("10.0.0.0/8")) },
+			ValidateMethods: func(t *testing.T, result Record) {
+				t.Helper()
+				assert.Equal(t, "10.0.0.0/8", result["Test"], "the log should contain the correct value")
+			},
+		},
+		{
+			Name: "AddrPort",
+			Fn: func() onelog.LoggerContext {
+				return logContext.AddrPort("Test", netip.MustParseAddrPort("[2001:db8::1]:443"))
+			},
+			ValidateMethods: func(t *testing.T, result Record) {
+				t.Helper()
+				assert.Equal(t, "[2001:db8::1]:443", result["Test"], "the log should contain the correct value")
+			},
+		},
+		{
+			Name: "URL",
+			Fn: func() onelog.LoggerContext {
+				return logContext.URL("Test", &url.URL{
+					Scheme: "https",
+					User:   url.UserPassword("user", "secret"),
+					Host:   "example.com",
+					Path:   "/path",
+				})
+			},
+			ValidateMethods: func(t *testing.T, result Record) {
+				t.Helper()
+				assert.Equal(t, "https://xxxxx@example.com/path", result["Test"], "the user information should be redacted")
+			},
+		},
+		{
+			Name: "URLUsername",
+			Fn: func() onelog.LoggerContext {
+				return logContext.URL("Test", &url.URL{Scheme: "https", User: url.User("token"), Host: "example.com"})
+			},
+			ValidateMethods: func(t *testing.T, result Record) {
+				t.Helper()
+				assert.Equal(t, "https://xxxxx@example.com", result["Test"], "usernames should be redacted too")
+			},
+		},
+		{
+			Name: "BigInt",
+			Fn:   func() onelog.LoggerContext { return logContext.BigInt("Test", big.NewInt(-42)) },
+			ValidateMethods: func(t *testing.T, result Record) {
+				t.Helper()
+				assert.Equal(t, float64(-42), result["Test"], "the log should contain the value as a number")
+			},
+		},
+		{
+			Name: "BigFloat",
+			Fn:   func() onelog.LoggerContext { return logContext.BigFloat("Test", big.NewFloat(1.5)) },
+			ValidateMethods: func(t *testing.T, result Record) {
+				t.Helper()
+				assert.Equal(t, 1.5, result["Test"], "the log should contain the value as a number")
+			},
+		},
+		{
+			Name: "BigFloatInf",
+			Fn:   func() onelog.LoggerContext { return logContext.BigFloat("Test", new(big.Float).SetInf(true)) },
+			ValidateMethods: func(t *testing.T, result Record) {
+				t.Helper()
+				assert.Equal(t, "-Inf", result["Test"], "infinite values should be rendered as strings")
+			},
+		},
+		{
+			Name: "Decimal",
+			Fn:   func() onelog.LoggerContext { return logContext.Decimal("Test", decimal("12.50")) },
+			ValidateMethods: func(t *testing.T, result Record) {
+				t.Helper()
+				assert.Equal(t, 12.5, result["Test"], "the log should contain the value as a number")
+			},
+		},
+		{
+			Name: "DecimalInvalid",
+			Fn:   func() onelog.LoggerContext { return logContext.Decimal("Test", decimal("NaN")) },
+			ValidateMethods: func(t *testing.T, result Record) {
+				t.Helper()
+				assert.Equal(t, "NaN", result["Test"], "invalid numbers should be rendered as strings")
+			},
+		},
 		{
 			Name: "Err",
 			Fn: func() onelog.LoggerContext {
 				return logContext.Err(fmt.Errorf("test error"))
 			},
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["error"]
 				require.True(t, ok, "the log should contain the key 'error'")
@@ -601,7 +686,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 			Fn: func() onelog.LoggerContext {
 				return logContext.Errs("errors", []error{fmt.Errorf("test error1"), fmt.Errorf("test error2")})
 			},
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["errors"]
 				require.True(t, ok, "the log should contain the key 'errors'")
@@ -613,7 +698,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 			Fn: func() onelog.LoggerContext {
 				return logContext.AnErr("my_error", fmt.Errorf("test error"))
 			},
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["my_error"]
 				require.True(t, ok, "the log should contain the key 'my_error'")
@@ -625,7 +710,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 			Fn: func() onelog.LoggerContext {
 				return logContext.Any("my_any", "test any")
 			},
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["my_any"]
 				require.True(t, ok, "the log should contain the key 'my_any'")
@@ -637,7 +722,7 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 			Fn: func() onelog.LoggerContext {
 				return logContext.Fields(map[string]any{"my_field": "test field"})
 			},
-			ValidateMethods: func(t *testing.T, result map[string]any) {
+			ValidateMethods: func(t *testing.T, result Record) {
 				t.Helper()
 				value, ok := result["my_field"]
 				require.True(t, ok, "the log should contain the key 'my_field'")
@@ -648,45 +733,3 @@ func getMethodsTests(logContext onelog.LoggerContext) []testCase {
 
 	return tests
 }
-
-func TestingMethods(t *testing.T, logger onelog.Logger, logSink *bytes.Buffer) {
-	t.Helper()
-
-	// With is a shared method between all onelog.Logger implementations, so we set a test value here. We'll verify this
-	// behavior in the tests further down.
-	logger = logger.With("test-with", "test")
-
-	// Get tests with a valid context
-	logContext := logger.Info()
-	tests := getMethodsTests(logContext)
-
-	for _, tc := range tests {
-		t.Run(tc.Name, func(t *testing.T) {
-			logSink.Reset() // Make sure the log sink is empty
-
-			// Check if the returned context is non-nil
-			assert.NotNil(t, tc.Fn(), "the returned context should not be nil")
-
-			// Validate that the log message is correct
-			const testText = "Test message"
-			tc.Fn().Msg(testText)
-
-			result := parseLogRecord(t, logSink)
-
-			// Now that the log record is parsed, we can validate the message and the test-with field. With() got called
-			// in the beginning of the test, we expect the test-with field to be present in all log records.
-			assert.Equal(t, testText, result["msg"], "the log should contain the correct message")
-			assert.Equal(t, "test", result["test-with"], "the log should contain the correct value for 'test-with'")
-
-			// Validate all type methods
-			tc.ValidateMethods(t, result)
-
-			// Finally, validate that Msgf works
-			logSink.Reset()
-			tc.Fn().Msgf("Test message %s", "with format")
-
-			result = parseLogRecord(t, logSink)
-			assert.Equal(t, "Test message with format", result["msg"], "the log should contain the correct message")
-		})
-	}
-}
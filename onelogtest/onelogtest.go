@@ -0,0 +1,797 @@
+package onelogtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/netip"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikoksr/onelog"
+)
+
+// Default keys of the message and level fields.
+const (
+	DefaultMessageKey = "msg"
+	DefaultLevelKey   = "level"
+)
+
+type (
+	// Record is a parsed log record. Values use the types encoding/json decodes into: string, float64, bool, nil, []any
+	// and map[string]any.
+	Record map[string]any
+
+	// Parser parses the output written by a logger into records, in the order they were written.
+	Parser interface {
+		Parse(data []byte) ([]Record, error)
+	}
+
+	// ParserFunc is a function that implements Parser.
+	ParserFunc func(data []byte) ([]Record, error)
+
+	// Config describes the logger under test.
+	Config struct {
+		// NewLogger returns the logger under test, writing to out with all levels enabled. It is called once per
+		// test; the returned logger must not share state with loggers returned earlier.
+		NewLogger func(out io.Writer) onelog.Logger
+		// NewFatalLogger returns a logger like NewLogger that terminates the process using exit after fatal records.
+		// If nil, RunFatal uses NewLogger and replaces the process-wide exit function using onelog.SetExitFunc
+		// instead; it must not run in parallel with other tests writing fatal records then.
+		NewFatalLogger func(out io.Writer, exit onelog.ExitFunc) onelog.Logger
+		// NewEncodingLogger returns a logger like NewLogger that renders values according to enc. If nil, RunEncoding
+		// only verifies the default encoding.
+		NewEncodingLogger func(out io.Writer, enc onelog.Encoding) onelog.Logger
+		// Pooled reports that contexts are returned to a pool once sent or discarded and must not be used afterwards.
+		// The suites then use a fresh context for every record, and RunReuse is skipped.
+		Pooled bool
+		// Parser parses the output of the logger. The default is JSONParser.
+		Parser Parser
+		// MessageKey is the key of the message. The default is DefaultMessageKey.
+		MessageKey string
+		// LevelKey is the key of the level. The default is DefaultLevelKey.
+		LevelKey string
+		// LevelNames overrides the expected names of levels, which default to Level.String. Names are compared
+		// case-insensitively; e.g. slog writes fatal records as "ERROR".
+		LevelNames map[onelog.Level]string
+	}
+)
+
+// Parse calls f(data).
+func (f ParserFunc) Parse(data []byte) ([]Record, error) {
+	return f(data)
+}
+
+// JSONParser parses newline-delimited JSON records. Anything in front of the opening brace of a line, such as a
+// timestamp prefix, is skipped.
+var JSONParser Parser = ParserFunc(func(data []byte) ([]Record, error) {
+	var records []Record
+	for i, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		if idx := strings.Index(line, "{"); idx > 0 {
+			line = line[idx:]
+		}
+
+		record := make(Record)
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+})
+
+func (c *Config) parser() Parser {
+	if c.Parser == nil {
+		return JSONParser
+	}
+
+	return c.Parser
+}
+
+func (c *Config) messageKey() string {
+	if c.MessageKey == "" {
+		return DefaultMessageKey
+	}
+
+	return c.MessageKey
+}
+
+func (c *Config) levelKey() string {
+	if c.LevelKey == "" {
+		return DefaultLevelKey
+	}
+
+	return c.LevelKey
+}
+
+func (c *Config) levelName(level onelog.Level) string {
+	if name, ok := c.LevelNames[level]; ok {
+		return name
+	}
+
+	return level.String()
+}
+
+// parse parses the records written to buff.
+func (c *Config) parse(t *testing.T, buff *syncBuffer) []Record {
+	t.Helper()
+
+	records, err := c.parser().Parse(buff.Bytes())
+	require.NoError(t, err, "the log should be parsable")
+
+	return records
+}
+
+// parseOne parses the single record written to buff.
+func (c *Config) parseOne(t *testing.T, buff *syncBuffer) Record {
+	t.Helper()
+
+	records := c.parse(t, buff)
+	require.Len(t, records, 1, "exactly one record should have been written")
+
+	return records[0]
+}
+
+// syncBuffer is a bytes.Buffer that is safe for concurrent use.
+type syncBuffer struct {
+	mu   sync.Mutex
+	buff bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buff.Write(p)
+}
+
+func (b *syncBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]byte(nil), b.buff.Bytes()...)
+}
+
+func (b *syncBuffer) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.buff.Reset()
+}
+
+// Run runs the whole conformance suite against the logger described by cfg.
+func Run(t *testing.T, cfg Config) {
+	t.Helper()
+
+	t.Run("Methods", func(t *testing.T) { RunMethods(t, cfg) })
+	t.Run("With", func(t *testing.T) { RunWith(t, cfg) })
+	t.Run("Levels", func(t *testing.T) { RunLevels(t, cfg) })
+	t.Run("Fatal", func(t *testing.T) { RunFatal(t, cfg) })
+	t.Run("Reuse", func(t *testing.T) { RunReuse(t, cfg) })
+	t.Run("Send", func(t *testing.T) { RunSend(t, cfg) })
+	t.Run("Discard", func(t *testing.T) { RunDiscard(t, cfg) })
+	t.Run("Nil", func(t *testing.T) { RunNil(t, cfg) })
+	t.Run("Encoding", func(t *testing.T) { RunEncoding(t, cfg) })
+	t.Run("Precision", func(t *testing.T) { RunPrecision(t, cfg) })
+	t.Run("FieldOrder", func(t *testing.T) { RunFieldOrder(t, cfg) })
+	t.Run("Concurrency", func(t *testing.T) { RunConcurrency(t, cfg) })
+	t.Run("Template", func(t *testing.T) { RunTemplate(t, cfg) })
+}
+
+// RunMethods verifies every method of onelog.LoggerContext: each must return a non-nil context and encode its value
+// correctly, alongside the message and the fields added using With.
+func RunMethods(t *testing.T, cfg Config) {
+	t.Helper()
+
+	buff := new(syncBuffer)
+
+	// With is a shared method between all onelog.Logger implementations, so we set a test value here. We'll verify this
+	// behavior in the tests further down.
+	logger := cfg.NewLogger(buff).With("test-with", "test")
+
+	// Get tests with a valid context
+	logContext := logger.Info()
+	tests := getMethodsTests(logContext)
+
+	for i, tc := range tests {
+		i, tc := i, tc
+		fn := tc.Fn
+		if cfg.Pooled {
+			fn = func() onelog.LoggerContext { return getMethodsTests(logger.Info())[i].Fn() }
+		}
+
+		t.Run(tc.Name, func(t *testing.T) {
+			buff.Reset() // Make sure the log sink is empty
+
+			// Check if the returned context is non-nil
+			assert.NotNil(t, fn(), "the returned context should not be nil")
+
+			// Validate that the log message is correct
+			const testText = "Test message"
+			fn().Msg(testText)
+
+			result := cfg.parseOne(t, buff)
+
+			// Now that the log record is parsed, we can validate the message and the test-with field. With() got called
+			// in the beginning of the test, we expect the test-with field to be present in all log records.
+			assert.Equal(t, testText, result[cfg.messageKey()], "the log should contain the correct message")
+			assert.Equal(t, "test", result["test-with"], "the log should contain the correct value for 'test-with'")
+
+			// Validate all type methods
+			tc.ValidateMethods(t, result)
+
+			// Finally, validate that Msgf works
+			buff.Reset()
+			fn().Msgf("Test message %s", "with format")
+
+			result = cfg.parseOne(t, buff)
+			assert.Equal(t, "Test message with format", result[cfg.messageKey()], "the log should contain the correct message")
+		})
+	}
+}
+
+// RunWith verifies that With adds its fields to all records of the returned logger, accumulates when chained and
+// leaves the original logger untouched.
+func RunWith(t *testing.T, cfg Config) {
+	t.Helper()
+
+	buff := new(syncBuffer)
+	logger := cfg.NewLogger(buff)
+	parent := logger.With("parent", "value")
+	child := parent.With("child", 42)
+	sibling := parent.With("sibling", true)
+
+	child.Info().Str("own", "field").Msg("child")
+	sibling.Info().Msg("sibling")
+	parent.Info().Msg("parent")
+	logger.Info().Msg("root")
+
+	records := cfg.parse(t, buff)
+	require.Len(t, records, 4)
+
+	assert.Equal(t, "value", records[0]["parent"], "fields of parents should be inherited")
+	assert.EqualValues(t, 42, records[0]["child"], "fields of With should keep their type")
+	assert.Equal(t, "field", records[0]["own"], "fields of the record should be added to the inherited ones")
+	assert.NotContains(t, records[0], "sibling", "fields of siblings should not leak")
+
+	assert.Equal(t, "value", records[1]["parent"])
+	assert.Equal(t, true, records[1]["sibling"])
+	assert.NotContains(t, records[1], "child", "fields of siblings should not leak")
+
+	assert.Equal(t, "value", records[2]["parent"])
+	assert.NotContains(t, records[2], "child", "fields of children should not leak into their parent")
+
+	assert.NotContains(t, records[3], "parent", "With should not modify the original logger")
+}
+
+// RunLevels verifies that every level method writes a record with the expected level. Fatal is only verified if the
+// logger implements onelog.FatalWriter, since writing a fatal record would terminate the process otherwise.
+func RunLevels(t *testing.T, cfg Config) {
+	t.Helper()
+
+	levels := []onelog.Level{onelog.DebugLevel, onelog.InfoLevel, onelog.WarnLevel, onelog.ErrorLevel}
+
+	for _, level := range levels {
+		level := level
+		t.Run(level.String(), func(t *testing.T) {
+			buff := new(syncBuffer)
+			onelog.AtLevel(cfg.NewLogger(buff), level).Msg("test")
+
+			assertLevel(t, cfg, level, cfg.parseOne(t, buff))
+		})
+	}
+
+	t.Run(onelog.FatalLevel.String(), func(t *testing.T) {
+		buff := new(syncBuffer)
+		fatalWriter, ok := cfg.NewLogger(buff).(onelog.FatalWriter)
+		if !ok {
+			t.Skip("the logger does not implement onelog.FatalWriter")
+		}
+
+		fatalWriter.FatalNoExit().Msg("test")
+
+		assertLevel(t, cfg, onelog.FatalLevel, cfg.parseOne(t, buff))
+	})
+}
+
+// RunFatal verifies the fatal semantics of onelog.Logger: once a fatal record is sent, it must be written in full, the
+// registered exit hooks must run, and only then the exit function must be called, exactly once and with code 1.
+// FatalNoExit, if implemented, must write the record without calling the exit function.
+func RunFatal(t *testing.T, cfg Config) {
+	t.Helper()
+
+	var (
+		exitCalls  atomic.Int32
+		exitCode   atomic.Int32
+		hookCalls  atomic.Int32
+		hookFirst  atomic.Bool
+		exitRecord Record
+	)
+
+	buff := new(syncBuffer)
+	exit := func(code int) {
+		exitCalls.Add(1)
+		exitCode.Store(int32(code))
+		hookFirst.Store(hookCalls.Load() > 0)
+
+		if records, err := cfg.parser().Parse(buff.Bytes()); err == nil && len(records) == 1 {
+			exitRecord = records[0]
+		}
+	}
+
+	// Exit hooks are process-wide, so the hook may run for fatal records of parallel tests too; it only counts calls.
+	unregister := onelog.RegisterExitHook(func() { hookCalls.Add(1) })
+	defer unregister()
+
+	var logger onelog.Logger
+	if cfg.NewFatalLogger != nil {
+		logger = cfg.NewFatalLogger(buff, exit)
+	} else {
+		restore := onelog.SetExitFunc(exit)
+		defer restore()
+		logger = cfg.NewLogger(buff)
+	}
+	logger = logger.With("test-with", "test")
+
+	if fatalWriter, ok := logger.(onelog.FatalWriter); ok {
+		fatalWriter.FatalNoExit().Msg("no exit")
+		assert.Zero(t, exitCalls.Load(), "FatalNoExit should not call the exit function")
+		cfg.parseOne(t, buff)
+		buff.Reset()
+	}
+
+	logger.Fatal().Str("test", "value").Msg("fatal")
+
+	require.EqualValues(t, 1, exitCalls.Load(), "the exit function should be called exactly once")
+	assert.EqualValues(t, 1, exitCode.Load(), "the process should exit with code 1")
+	assert.True(t, hookFirst.Load(), "the exit hooks should run before the exit function")
+
+	require.NotNil(t, exitRecord, "the record should be written in full before the exit function is called")
+	assert.Equal(t, "fatal", exitRecord[cfg.messageKey()], "the log should contain the correct message")
+	assert.Equal(t, "value", exitRecord["test"], "the log should contain the fields of the record")
+	assert.Equal(t, "test", exitRecord["test-with"], "the log should contain the fields of With")
+	assertLevel(t, cfg, onelog.FatalLevel, exitRecord)
+}
+
+func assertLevel(t *testing.T, cfg Config, level onelog.Level, record Record) {
+	t.Helper()
+
+	value, ok := record[cfg.levelKey()]
+	require.True(t, ok, "the log should contain the key '%s'", cfg.levelKey())
+
+	name, _ := value.(string)
+	assert.True(t, strings.EqualFold(cfg.levelName(level), name), "the log should have level %q, got %v",
+		cfg.levelName(level), value)
+}
+
+// RunReuse verifies that a context can be reused after Msg and Msgf: the next record must be written in full and must
+// not contain the fields of the previous one.
+func RunReuse(t *testing.T, cfg Config) {
+	t.Helper()
+
+	if cfg.Pooled {
+		t.Skip("contexts of the logger are pooled")
+	}
+
+	buff := new(syncBuffer)
+	logContext := cfg.NewLogger(buff).With("test-with", "test").Warn()
+
+	logContext.Str("first", "value").Msg("first")
+	logContext.Int("second", 2).Msgf("%s", "second")
+	logContext.Msg("third")
+
+	records := cfg.parse(t, buff)
+	require.Len(t, records, 3)
+
+	assert.Equal(t, "first", records[0][cfg.messageKey()])
+	assert.Equal(t, "value", records[0]["first"])
+
+	assert.Equal(t, "second", records[1][cfg.messageKey()])
+	assert.EqualValues(t, 2, records[1]["second"])
+	assert.NotContains(t, records[1], "first", "fields should not outlive their record")
+
+	assert.Equal(t, "third", records[2][cfg.messageKey()])
+	assert.NotContains(t, records[2], "second", "fields should not outlive their record")
+
+	for _, record := range records {
+		assert.Equal(t, "test", record["test-with"], "reused contexts should keep the fields of With")
+		assertLevel(t, cfg, onelog.WarnLevel, record)
+	}
+}
+
+// RunSend verifies that Send writes the record like Msg, with an empty or no message.
+func RunSend(t *testing.T, cfg Config) {
+	t.Helper()
+
+	buff := new(syncBuffer)
+	cfg.NewLogger(buff).With("test-with", "test").Warn().Str("key", "value").Send()
+
+	record := cfg.parseOne(t, buff)
+	if msg, ok := record[cfg.messageKey()]; ok {
+		assert.Equal(t, "", msg, "the message should be empty")
+	}
+	assert.Equal(t, "value", record["key"])
+	assert.Equal(t, "test", record["test-with"])
+	assertLevel(t, cfg, onelog.WarnLevel, record)
+}
+
+// RunDiscard verifies that Discard abandons a record without writing it, that the context can be reused afterwards
+// unless Config.Pooled is set, and that discarding a fatal record does not terminate the process. The latter is
+// only verified if Config.NewFatalLogger is set.
+func RunDiscard(t *testing.T, cfg Config) {
+	t.Helper()
+
+	buff := new(syncBuffer)
+	logger := cfg.NewLogger(buff)
+
+	logContext := logger.Info().Str("discarded", "value")
+	logContext.Discard()
+	assert.Empty(t, buff.Bytes(), "discarded records should not be written")
+
+	if !cfg.Pooled {
+		logContext.Str("kept", "value").Msg("after discard")
+
+		record := cfg.parseOne(t, buff)
+		assert.Equal(t, "after discard", record[cfg.messageKey()])
+		assert.Equal(t, "value", record["kept"])
+		assert.NotContains(t, record, "discarded", "discarded fields should not outlive their record")
+	}
+
+	if cfg.NewFatalLogger == nil {
+		return
+	}
+
+	var exitCalls atomic.Int32
+	buff.Reset()
+	cfg.NewFatalLogger(buff, func(int) { exitCalls.Add(1) }).Fatal().Str("discarded", "value").Discard()
+
+	assert.Zero(t, exitCalls.Load(), "discarding a fatal record should not call the exit function")
+	assert.Empty(t, buff.Bytes(), "discarded records should not be written")
+}
+
+// nilPointer is a Stringer and error whose methods panic if called on a nil pointer, like those of most types.
+type nilPointer struct {
+	value string
+}
+
+func (p *nilPointer) String() string { return p.value }
+
+func (p *nilPointer) Error() string { return p.value }
+
+// RunNil verifies that every method of onelog.LoggerContext accepts nil values and renders them as documented by
+// onelog.LoggerContext: nil errors are omitted; nil Stringers, typed-nil pointers and empty addresses and raw JSON are
+// null; nil slices are empty arrays; nil elements are null; nil bytes are empty strings.
+func RunNil(t *testing.T, cfg Config) {
+	t.Helper()
+
+	var (
+		typedNil = (*nilPointer)(nil)
+		errNil   error
+		errTyped error = typedNil
+	)
+
+	omitted := func(t *testing.T, record Record, key string) {
+		t.Helper()
+		assert.NotContains(t, record, key, "nil errors should be omitted")
+	}
+	null := func(t *testing.T, record Record, key string) {
+		t.Helper()
+		require.Contains(t, record, key, "nil values should be rendered")
+		assert.Nil(t, record[key], "nil values should be rendered as null")
+	}
+	empty := func(t *testing.T, record Record, key string) {
+		t.Helper()
+		require.Contains(t, record, key, "nil slices should be rendered")
+		assert.Equal(t, []any{}, record[key], "nil slices should be rendered as empty arrays")
+	}
+	emptyString := func(t *testing.T, record Record, key string) {
+		t.Helper()
+		assert.Equal(t, "", record[key], "nil bytes should be rendered as empty strings")
+	}
+
+	tests := []struct {
+		name   string
+		fn     func(ctx onelog.LoggerContext) onelog.LoggerContext
+		key    string
+		verify func(t *testing.T, record Record, key string)
+	}{
+		{"Err", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.Err(errNil) }, "error", omitted},
+		{"ErrTypedNil", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.Err(errTyped) }, "error", omitted},
+		{"AnErr", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.AnErr("k", errNil) }, "k", omitted},
+		{"AnErrTypedNil", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.AnErr("k", errTyped) }, "k", omitted},
+		{"Errs", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.Errs("k", nil) }, "k", empty},
+		{"Stringer", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.Stringer("k", nil) }, "k", null},
+		{"StringerTypedNil", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.Stringer("k", typedNil) }, "k", null},
+		{"Stringers", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.Stringers("k", nil) }, "k", empty},
+		{"Any", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.Any("k", nil) }, "k", null},
+		{"AnyTypedNil", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.Any("k", typedNil) }, "k", null},
+		{"AnyNilSlice", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.Any("k", []int(nil)) }, "k", null},
+		{"Fields", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.Fields(onelog.Fields{"k": nil}) }, "k", null},
+		{"FieldsTypedNil", func(ctx onelog.LoggerContext) onelog.LoggerContext {
+			return ctx.Fields(onelog.Fields{"k": typedNil})
+		}, "k", null},
+		{"RawJSON", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.RawJSON("k", nil) }, "k", null},
+		{"IPAddr", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.IPAddr("k", nil) }, "k", null},
+		{"IPPrefix", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.IPPrefix("k", net.IPNet{}) }, "k", null},
+		{"MACAddr", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.MACAddr("k", nil) }, "k", null},
+		{"Addr", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.Addr("k", netip.Addr{}) }, "k", null},
+		{"Prefix", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.Prefix("k", netip.Prefix{}) }, "k", null},
+		{"AddrPort", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.AddrPort("k", netip.AddrPort{}) }, "k", null},
+		{"URL", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.URL("k", nil) }, "k", null},
+		{"BigInt", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.BigInt("k", nil) }, "k", null},
+		{"BigFloat", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.BigFloat("k", nil) }, "k", null},
+		{"Decimal", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.Decimal("k", nil) }, "k", null},
+		{"DecimalTypedNil", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.Decimal("k", typedNil) }, "k", null},
+		{"Bytes", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.Bytes("k", nil) }, "k", emptyString},
+		{"Hex", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.Hex("k", nil) }, "k", emptyString},
+		{"Strs", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.Strs("k", nil) }, "k", empty},
+		{"Ints", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.Ints("k", nil) }, "k", empty},
+		{"Ints8", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.Ints8("k", nil) }, "k", empty},
+		{"Ints16", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.Ints16("k", nil) }, "k", empty},
+		{"Ints32", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.Ints32("k", nil) }, "k", empty},
+		{"Ints64", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.Ints64("k", nil) }, "k", empty},
+		{"Uints", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.Uints("k", nil) }, "k", empty},
+		{"Uints8", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.Uints8("k", nil) }, "k", empty},
+		{"Uints16", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.Uints16("k", nil) }, "k", empty},
+		{"Uints32", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.Uints32("k", nil) }, "k", empty},
+		{"Uints64", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.Uints64("k", nil) }, "k", empty},
+		{"Floats32", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.Floats32("k", nil) }, "k", empty},
+		{"Floats64", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.Floats64("k", nil) }, "k", empty},
+		{"Bools", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.Bools("k", nil) }, "k", empty},
+		{"Times", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.Times("k", nil) }, "k", empty},
+		{"Durs", func(ctx onelog.LoggerContext) onelog.LoggerContext { return ctx.Durs("k", nil) }, "k", empty},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			buff := new(syncBuffer)
+			tc.fn(cfg.NewLogger(buff).Info()).Msg("nil")
+
+			record := cfg.parseOne(t, buff)
+			assert.Equal(t, "nil", record[cfg.messageKey()], "the log should contain the correct message")
+			tc.verify(t, record, tc.key)
+		})
+	}
+
+	// Nil elements must keep their position, so that indices still match those of the slice passed in
+	t.Run("NilElements", func(t *testing.T) {
+		buff := new(syncBuffer)
+		cfg.NewLogger(buff).Info().
+			Stringers("stringers", []fmt.Stringer{nil, typedNil, &nilPointer{value: "value"}}).
+			Errs("errs", []error{nil, errTyped, errors.New("value")}).
+			Msg("nil")
+
+		record := cfg.parseOne(t, buff)
+
+		assert.Equal(t, []any{nil, nil, "value"}, record["stringers"], "nil Stringers should be rendered as null")
+
+		errs, ok := record["errs"].([]any)
+		require.True(t, ok, "the log should contain an array of errors, got %T", record["errs"])
+		require.Len(t, errs, 3, "nil errors should not be dropped from arrays")
+		assert.Nil(t, errs[0], "nil errors should be rendered as null")
+		assert.Nil(t, errs[1], "typed-nil errors should be rendered as null")
+		validateErrors(t, []error{errors.New("value")}, errs[2:])
+	})
+}
+
+// RunPrecision verifies that big numbers and decimals are rendered as numbers with all their digits, rather than being
+// rounded to float64. It requires JSON records and is skipped if Config.Parser is set.
+func RunPrecision(t *testing.T, cfg Config) {
+	t.Helper()
+
+	if cfg.Parser != nil {
+		t.Skip("precision can only be verified for JSON records")
+	}
+
+	const (
+		bigIntText  = "-123456789012345678901234567890"
+		decimalText = "0.10000000000000000000000000001"
+	)
+	bigInt, _ := new(big.Int).SetString(bigIntText, 10)
+	bigFloat, _, err := big.ParseFloat("3.14159265358979323846264338327950288", 10, 128, big.ToNearestEven)
+	require.NoError(t, err)
+
+	buff := new(syncBuffer)
+	cfg.NewLogger(buff).Info().
+		BigInt("int", bigInt).
+		BigFloat("float", bigFloat).
+		Decimal("decimal", decimal(decimalText)).
+		Msg("precision")
+
+	data := buff.Bytes()
+	if idx := bytes.IndexByte(data, '{'); idx > 0 {
+		data = data[idx:]
+	}
+
+	// Decode numbers as json.Number to compare their digits; Record holds them as float64
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var record map[string]any
+	require.NoError(t, decoder.Decode(&record))
+
+	assert.Equal(t, json.Number(bigIntText), record["int"], "big integers should keep all digits")
+	assert.Equal(t, json.Number(bigFloat.Text('g', -1)), record["float"], "big floats should keep all digits")
+	assert.Equal(t, json.Number(decimalText), record["decimal"], "decimals should be rendered verbatim")
+}
+
+// RunFieldOrder verifies that Fields adds its entries in the order of their sorted keys, after the fields added before
+// and before the fields added after it. It requires JSON records and is skipped if Config.Parser is set.
+func RunFieldOrder(t *testing.T, cfg Config) {
+	t.Helper()
+
+	if cfg.Parser != nil {
+		t.Skip("the field order can only be verified for JSON records")
+	}
+
+	fields := make(onelog.Fields)
+	expected := []string{"first"}
+	for i := 0; i < 20; i++ {
+		key := fmt.Sprintf("field-%02d", i)
+		fields[key] = i
+		expected = append(expected, key)
+	}
+	expected = append(expected, "last")
+
+	// Write several records, so that a lucky iteration order of the map cannot pass the test
+	buff := new(syncBuffer)
+	logger := cfg.NewLogger(buff)
+	for i := 0; i < 5; i++ {
+		logger.Info().Str("first", "value").Fields(fields).Str("last", "value").Msg("order")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(buff.Bytes()))
+	for i := 0; i < 5; i++ {
+		keys := recordKeys(t, decoder)
+
+		// Drop the keys written by the logger itself, such as the level and message
+		ordered := make([]string, 0, len(expected))
+		for _, key := range keys {
+			if _, ok := fields[key]; ok || key == "first" || key == "last" {
+				ordered = append(ordered, key)
+			}
+		}
+		assert.Equal(t, expected, ordered, "the fields should be written in the order of their sorted keys")
+	}
+}
+
+// recordKeys decodes the next record from decoder and returns its top-level keys in the order they were written.
+func recordKeys(t *testing.T, decoder *json.Decoder) []string {
+	t.Helper()
+
+	token, err := decoder.Token()
+	require.NoError(t, err)
+	require.Equal(t, json.Delim('{'), token, "the record should be a JSON object")
+
+	var keys []string
+	for decoder.More() {
+		token, err := decoder.Token()
+		require.NoError(t, err)
+		key, ok := token.(string)
+		require.True(t, ok, "the keys of the record should be strings")
+		keys = append(keys, key)
+
+		var value json.RawMessage
+		require.NoError(t, decoder.Decode(&value))
+	}
+
+	_, err = decoder.Token()
+	require.NoError(t, err)
+
+	return keys
+}
+
+// RunConcurrency verifies that a logger, and loggers derived from it, can be used by many goroutines at once, each
+// with its own contexts, without losing or mixing up records.
+func RunConcurrency(t *testing.T, cfg Config) {
+	t.Helper()
+
+	const (
+		goroutines = 8
+		iterations = 50
+	)
+
+	buff := new(syncBuffer)
+	logger := cfg.NewLogger(buff).With("test-with", "test")
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+
+			derived := logger.With("goroutine", g)
+			for i := 0; i < iterations; i++ {
+				derived.Info().Int("iteration", i).Str("tag", fmt.Sprintf("%d-%d", g, i)).Msgf("record %d-%d", g, i)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	records := cfg.parse(t, buff)
+	require.Len(t, records, goroutines*iterations, "no record should be lost")
+
+	seen := make(map[string]bool, len(records))
+	for _, record := range records {
+		tag := fmt.Sprintf("%v-%v", record["goroutine"], record["iteration"])
+		assert.Equal(t, tag, record["tag"], "the fields of a record should not be mixed up with other records")
+		assert.Equal(t, "record "+tag, record[cfg.messageKey()])
+		assert.Equal(t, "test", record["test-with"])
+		seen[tag] = true
+	}
+	assert.Len(t, seen, goroutines*iterations, "every record should be written exactly once")
+}
+
+// RunTemplate verifies that a template keeps the level and fields of the context it was taken from, is unaffected by
+// fields added to that context and by changes to the values afterwards, and can be used by many goroutines at once,
+// each emitting records with fields of its own.
+func RunTemplate(t *testing.T, cfg Config) {
+	t.Helper()
+
+	const (
+		goroutines = 8
+		iterations = 50
+	)
+
+	buff := new(syncBuffer)
+	ints := []int{1, 2}
+	logContext := cfg.NewLogger(buff).With("test-with", "test").Warn().Str("shared", "value").Ints("ints", ints)
+	template := logContext.Template()
+
+	ints[0] = 42
+	logContext.Str("after", "value").Msg("context") // Still usable, as it was not sent yet
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+
+			reused := template.Context()
+			for i := 0; i < iterations; i++ {
+				template.Context().Int("goroutine", g).Int("iteration", i).Msgf("record %d-%d", g, i)
+				if cfg.Pooled {
+					reused = template.Context()
+				}
+				reused.Int("goroutine", g).Int("iteration", i).Msgf("reused %d-%d", g, i)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	records := cfg.parse(t, buff)
+	require.Len(t, records, 1+2*goroutines*iterations, "no record should be lost")
+
+	assert.Equal(t, "context", records[0][cfg.messageKey()])
+	assert.Equal(t, "value", records[0]["after"], "the context should be usable after taking a template")
+
+	seen := make(map[string]bool, len(records))
+	for _, record := range records[1:] {
+		msg, _ := record[cfg.messageKey()].(string)
+		tag := fmt.Sprintf("%v-%v", record["goroutine"], record["iteration"])
+		assert.True(t, msg == "record "+tag || msg == "reused "+tag,
+			"the fields of a record should not be mixed up with other records, got %q for %s", msg, tag)
+		assert.NotContains(t, record, "after", "fields added to the context should not leak into the template")
+		assert.Equal(t, "value", record["shared"], "records should contain the fields of the template")
+		assert.Equal(t, []any{1.0, 2.0}, record["ints"], "the template should be unaffected by later changes to values")
+		assert.Equal(t, "test", record["test-with"], "records should contain the fields of With")
+		assertLevel(t, cfg, onelog.WarnLevel, record)
+		seen[msg] = true
+	}
+	assert.Len(t, seen, 2*goroutines*iterations, "every record should be written exactly once")
+}
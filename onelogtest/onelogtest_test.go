@@ -0,0 +1,88 @@
+package onelogtest_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikoksr/onelog"
+	"github.com/nikoksr/onelog/internal/testutil"
+	"github.com/nikoksr/onelog/onelogtest"
+)
+
+// base64Writer encodes every record written by zerolog as a base64 line, standing in for a non-JSON format.
+type base64Writer struct {
+	out io.Writer
+}
+
+func (w base64Writer) Write(p []byte) (int, error) {
+	encoded := base64.StdEncoding.EncodeToString(bytes.TrimSpace(p)) + "\n"
+	if _, err := io.WriteString(w.out, encoded); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// TestCustomParser tests if the suite verifies loggers using a custom parser.
+func TestCustomParser(t *testing.T) {
+	t.Parallel()
+
+	parser := onelogtest.ParserFunc(func(data []byte) ([]onelogtest.Record, error) {
+		var records []onelogtest.Record
+		for _, line := range bytes.Fields(data) {
+			decoded, err := base64.StdEncoding.DecodeString(string(line))
+			if err != nil {
+				return nil, err
+			}
+
+			record := make(onelogtest.Record)
+			if err := json.Unmarshal(decoded, &record); err != nil {
+				return nil, err
+			}
+			records = append(records, record)
+		}
+
+		return records, nil
+	})
+
+	cfg := onelogtest.Config{
+		NewLogger:  func(out io.Writer) onelog.Logger { return testutil.NewAdapter(base64Writer{out: out}) },
+		Parser:     parser,
+		MessageKey: zerolog.MessageFieldName,
+	}
+
+	onelogtest.RunWith(t, cfg)
+	onelogtest.RunLevels(t, cfg)
+	onelogtest.RunReuse(t, cfg)
+	onelogtest.RunConcurrency(t, cfg)
+}
+
+// TestFatalProcessWideExitFunc tests if RunFatal falls back to the process-wide exit function if the config has no
+// NewFatalLogger.
+//
+//nolint:paralleltest // Replaces the process-wide exit function.
+func TestFatalProcessWideExitFunc(t *testing.T) {
+	onelogtest.RunFatal(t, onelogtest.Config{
+		NewLogger:  func(out io.Writer) onelog.Logger { return testutil.NewAdapter(out) },
+		MessageKey: zerolog.MessageFieldName,
+	})
+}
+
+// TestJSONParser tests if the JSON parser skips prefixes and empty lines and reports invalid records.
+func TestJSONParser(t *testing.T) {
+	t.Parallel()
+
+	records, err := onelogtest.JSONParser.Parse([]byte("2023-01-01 {\"msg\":\"first\"}\n\n{\"msg\":\"second\"}\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []onelogtest.Record{{"msg": "first"}, {"msg": "second"}}, records)
+
+	_, err = onelogtest.JSONParser.Parse([]byte("{\"msg\":\"first\"}\nnot json\n"))
+	assert.ErrorContains(t, err, "line 2")
+}
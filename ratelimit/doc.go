@@ -0,0 +1,4 @@
+// Package ratelimit provides a onelog.Logger wrapper that puts hard caps on the number of records written, using token
+// buckets keyed by an arbitrary function over level, message and fields, e.g. to write at most ten "payment failed"
+// records per tenant per second.
+package ratelimit
@@ -0,0 +1,357 @@
+package ratelimit
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nikoksr/onelog"
+	"github.com/nikoksr/onelog/internal/record"
+)
+
+// Compile-time check that Logger implements onelog.Logger, onelog.FatalWriter and onelog.Syncer
+var (
+	_ onelog.Logger      = (*Logger)(nil)
+	_ onelog.FatalWriter = (*Logger)(nil)
+	_ onelog.Syncer      = (*Logger)(nil)
+)
+
+// Keys of the fields added to suppression reports.
+const (
+	SuppressedKey = "suppressed"
+	LimitKeyKey   = "rate_limit_key"
+)
+
+const (
+	// defaultReportInterval is the interval suppression reports are written in if no interval is configured.
+	defaultReportInterval = time.Minute
+	// defaultPeriod is the period limits apply to if the given period is not positive.
+	defaultPeriod = time.Second
+)
+
+type (
+	// KeyFunc returns the key of the token bucket a record is counted against. The fields contain the fields added to
+	// the record as well as the ones added using With.
+	KeyFunc func(level onelog.Level, msg string, fields onelog.Fields) string
+
+	// Logger is a onelog.Logger that drops records once their token bucket is empty. Every bucket holds up to burst
+	// tokens and is refilled at the configured rate; each record takes one token.
+	//
+	// Suppressed records are counted per key and reported periodically by a record with the message "N records
+	// suppressed", written at the highest level of the suppressed records. Loggers derived using With share the
+	// buckets of their parent. Fatal records are never limited; pending reports are written before them.
+	Logger struct {
+		logger onelog.Logger
+		fields onelog.Fields // Fields added using With, passed on to custom key functions
+		state  *state
+	}
+
+	// Option configures a Logger.
+	Option func(*state)
+
+	// state holds the token buckets, shared by a Logger and all loggers derived from it.
+	state struct {
+		rate           float64 // Tokens per second
+		burst          float64
+		keyFunc        KeyFunc
+		reportInterval time.Duration
+		now            func() time.Time
+
+		mu      sync.Mutex
+		buckets map[string]*bucket
+
+		suppressed atomic.Uint64
+		stop       chan struct{}
+		done       chan struct{}
+		closeOnce  sync.Once
+	}
+
+	// bucket is the token bucket of a single key.
+	bucket struct {
+		tokens     float64
+		updatedAt  time.Time
+		suppressed int
+		level      onelog.Level // Highest level of the suppressed records
+		logger     onelog.Logger
+	}
+)
+
+// WithKeyFunc sets the function that maps records to token buckets. By default, records are keyed by level and
+// message, joined by a colon.
+func WithKeyFunc(fn KeyFunc) Option {
+	return func(s *state) {
+		s.keyFunc = fn
+	}
+}
+
+// WithBurst sets the number of records that can be written at once before the rate applies. It defaults to the limit;
+// values below one are ignored.
+func WithBurst(burst int) Option {
+	return func(s *state) {
+		if burst > 0 {
+			s.burst = float64(burst)
+		}
+	}
+}
+
+// WithReportInterval sets the interval in which suppression reports are written. The default is one minute.
+func WithReportInterval(interval time.Duration) Option {
+	return func(s *state) {
+		if interval > 0 {
+			s.reportInterval = interval
+		}
+	}
+}
+
+// New returns a Logger that writes at most limit records of l per period and key. It starts a goroutine writing the
+// suppression reports, which runs until Close is called.
+//
+// A limit below one is raised to one and a period that is not positive defaults to one second, so that records are
+// never dropped for good and the rate stays finite.
+func New(l onelog.Logger, limit int, period time.Duration, opts ...Option) *Logger {
+	if limit < 1 {
+		limit = 1
+	}
+	if period <= 0 {
+		period = defaultPeriod
+	}
+
+	s := &state{
+		rate:           float64(limit) / period.Seconds(),
+		burst:          float64(limit),
+		reportInterval: defaultReportInterval,
+		now:            time.Now,
+		buckets:        make(map[string]*bucket),
+		stop:           make(chan struct{}),
+		done:           make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	go s.run()
+
+	return &Logger{
+		logger: l,
+		state:  s,
+	}
+}
+
+// defaultKey keys records by level and message.
+func defaultKey(level onelog.Level, msg string) string {
+	return level.String() + ":" + msg
+}
+
+// Suppressed returns the total number of records suppressed so far.
+func (l *Logger) Suppressed() uint64 {
+	return l.state.suppressed.Load()
+}
+
+// Flush writes the pending suppression reports right away.
+func (l *Logger) Flush() {
+	l.state.report()
+}
+
+// Close stops writing periodic suppression reports and writes the pending ones.
+func (l *Logger) Close() {
+	l.state.closeOnce.Do(func() {
+		close(l.state.stop)
+	})
+	<-l.state.done
+}
+
+// run writes the suppression reports until the logger is closed.
+func (s *state) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.reportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.report()
+		case <-s.stop:
+			s.report()
+			return
+		}
+	}
+}
+
+// report writes a record for each key with suppressed records and removes idle buckets.
+func (s *state) report() {
+	type pending struct {
+		key    string
+		count  int
+		level  onelog.Level
+		logger onelog.Logger
+	}
+
+	now := s.now()
+
+	s.mu.Lock()
+	var reports []pending
+	for key, b := range s.buckets {
+		if b.suppressed > 0 {
+			reports = append(reports, pending{key: key, count: b.suppressed, level: b.level, logger: b.logger})
+			b.suppressed = 0
+			b.logger = nil
+			continue
+		}
+
+		// A full bucket behaves like a new one, so there is no need to keep it around
+		b.refill(now, s.rate, s.burst)
+		if b.tokens >= s.burst {
+			delete(s.buckets, key)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, r := range reports {
+		onelog.AtLevel(r.logger, r.level).
+			Int(SuppressedKey, r.count).
+			Str(LimitKeyKey, r.key).
+			Msgf("%d records suppressed", r.count)
+	}
+}
+
+// allow takes a token from the bucket of key. If the bucket is empty, the record is counted as suppressed.
+func (s *state) allow(l *Logger, level onelog.Level, key string) bool {
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: s.burst, updatedAt: now}
+		s.buckets[key] = b
+	}
+
+	b.refill(now, s.rate, s.burst)
+	if b.tokens >= 1 {
+		b.tokens--
+		return true
+	}
+
+	if b.suppressed == 0 || level > b.level {
+		b.level = level
+	}
+	b.suppressed++
+	b.logger = l.logger
+	s.suppressed.Add(1)
+
+	return false
+}
+
+// refill adds the tokens accumulated since the last update.
+func (b *bucket) refill(now time.Time, rate, burst float64) {
+	if elapsed := now.Sub(b.updatedAt); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * rate
+		if b.tokens > burst {
+			b.tokens = burst
+		}
+		b.updatedAt = now
+	}
+}
+
+// recordFields returns the fields of a record, including the ones added using With, as onelog.Fields.
+func (l *Logger) recordFields(fields []record.Field) onelog.Fields {
+	result := make(onelog.Fields, len(l.fields)+len(fields))
+	for key, value := range l.fields {
+		result[key] = value
+	}
+
+	for _, f := range fields {
+		if f.Kind == record.KindFields {
+			m, _ := f.Value.(onelog.Fields)
+			for key, value := range m {
+				result[key] = value
+			}
+			continue
+		}
+		result[f.Key] = f.Value
+	}
+
+	return result
+}
+
+func (l *Logger) newContext(level onelog.Level) onelog.LoggerContext {
+	return record.NewContext(func(msg string, fields []record.Field) {
+		key := defaultKey(level, msg)
+		if l.state.keyFunc != nil {
+			key = l.state.keyFunc(level, msg, l.recordFields(fields)) // Only custom key functions get to see the fields
+		}
+		if !l.state.allow(l, level, key) {
+			return
+		}
+
+		record.Apply(onelog.AtLevel(l.logger, level), fields).Msg(msg)
+	})
+}
+
+// With returns the logger with the given fields.
+func (l *Logger) With(fields ...any) onelog.Logger {
+	if l.state.keyFunc == nil {
+		return &Logger{logger: l.logger.With(fields...), state: l.state}
+	}
+
+	withFields := make(onelog.Fields, len(l.fields)+len(fields)/2)
+	for key, value := range l.fields {
+		withFields[key] = value
+	}
+	for i := 0; i+1 < len(fields); i += 2 {
+		if key, ok := fields[i].(string); ok {
+			withFields[key] = fields[i+1]
+		}
+	}
+
+	return &Logger{
+		logger: l.logger.With(fields...),
+		fields: withFields,
+		state:  l.state,
+	}
+}
+
+// Sync flushes the buffered records of the wrapped logger, if it implements onelog.Syncer.
+func (l *Logger) Sync() error {
+	return onelog.Sync(l.logger)
+}
+
+// Debug returns a LoggerContext for a debug log. To send the log, use the Msg or Msgf methods.
+func (l *Logger) Debug() onelog.LoggerContext {
+	return l.newContext(onelog.DebugLevel)
+}
+
+// Info returns a LoggerContext for an info log. To send the log, use the Msg or Msgf methods.
+func (l *Logger) Info() onelog.LoggerContext {
+	return l.newContext(onelog.InfoLevel)
+}
+
+// Warn returns a LoggerContext for a warn log. To send the log, use the Msg or Msgf methods.
+func (l *Logger) Warn() onelog.LoggerContext {
+	return l.newContext(onelog.WarnLevel)
+}
+
+// Error returns a LoggerContext for an error log. To send the log, use the Msg or Msgf methods.
+func (l *Logger) Error() onelog.LoggerContext {
+	return l.newContext(onelog.ErrorLevel)
+}
+
+// Fatal returns a LoggerContext for a fatal log. Pending suppression reports are written before the fatal record.
+func (l *Logger) Fatal() onelog.LoggerContext {
+	return record.NewContext(func(msg string, fields []record.Field) {
+		l.Flush()
+		record.Apply(l.logger.Fatal(), fields).Msg(msg)
+	})
+}
+
+// FatalNoExit returns a LoggerContext for a fatal log that does not exit once sent.
+func (l *Logger) FatalNoExit() onelog.LoggerContext {
+	return record.NewContext(func(msg string, fields []record.Field) {
+		l.Flush()
+		ctx, _ := onelog.FatalNoExit(l.logger)
+		record.Apply(ctx, fields).Msg(msg)
+	})
+}
@@ -0,0 +1,231 @@
+package ratelimit
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikoksr/onelog"
+	"github.com/nikoksr/onelog/internal/testutil"
+)
+
+// fakeClock is a manually advanced clock.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func newTestLogger(out io.Writer, limit int, opts ...Option) (*Logger, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}
+	logger := New(testutil.NewAdapter(out), limit, time.Second, opts...)
+	logger.state.now = clock.Now
+
+	return logger, clock
+}
+
+// TestLoggerLimit tests if records exceeding the limit are suppressed and the bucket refills over time.
+func TestLoggerLimit(t *testing.T) {
+	t.Parallel()
+
+	buff := new(testutil.SyncBuffer)
+	logger, clock := newTestLogger(buff, 2)
+	defer logger.Close()
+
+	for i := 0; i < 5; i++ {
+		logger.Error().Int("i", i).Msg("payment failed")
+	}
+	logger.Error().Msg("other")
+
+	records := testutil.ParseRecords(t, buff.String())
+	require.Len(t, records, 3, "records exceeding the limit should be suppressed")
+	assert.EqualValues(t, 1, records[1]["i"])
+	assert.Equal(t, "other", records[2]["message"], "other messages should have their own bucket")
+	assert.EqualValues(t, 3, logger.Suppressed())
+
+	clock.Add(500 * time.Millisecond)
+	logger.Error().Msg("payment failed")
+	logger.Error().Msg("payment failed")
+	assert.Len(t, testutil.ParseRecords(t, buff.String()), 4, "the bucket should refill at the configured rate")
+}
+
+// TestLoggerInvalidLimits tests if limits below one, bursts below one and non-positive periods are clamped, so that
+// records are neither dropped for good nor let through at an infinite rate.
+func TestLoggerInvalidLimits(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		limit  int
+		period time.Duration
+		opts   []Option
+	}{
+		{name: "ZeroLimit", limit: 0, period: time.Second},
+		{name: "NegativeLimit", limit: -1, period: time.Second},
+		{name: "ZeroPeriod", limit: 1, period: 0},
+		{name: "NegativePeriod", limit: 1, period: -time.Second},
+		{name: "ZeroBurst", limit: 1, period: time.Second, opts: []Option{WithBurst(0)}},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			buff := new(testutil.SyncBuffer)
+			clock := &fakeClock{now: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}
+			logger := New(testutil.NewAdapter(buff), tc.limit, tc.period, tc.opts...)
+			logger.state.now = clock.Now
+			defer logger.Close()
+
+			logger.Info().Msg("first")
+			logger.Info().Msg("first")
+			assert.Len(t, testutil.ParseRecords(t, buff.String()), 1, "one record should be written per second")
+
+			clock.Add(time.Second)
+			logger.Info().Msg("first")
+			assert.Len(t, testutil.ParseRecords(t, buff.String()), 2, "the bucket should refill")
+		})
+	}
+}
+
+// TestLoggerKeyFunc tests if records are counted against the bucket returned by the key function, which gets to see
+// fields added using With.
+func TestLoggerKeyFunc(t *testing.T) {
+	t.Parallel()
+
+	buff := new(testutil.SyncBuffer)
+	logger, _ := newTestLogger(buff, 1, WithKeyFunc(func(_ onelog.Level, msg string, fields onelog.Fields) string {
+		tenant, _ := fields["tenant"].(string)
+		return tenant + ":" + msg
+	}))
+	defer logger.Close()
+
+	logger.With("tenant", "a").Error().Msg("payment failed")
+	logger.With("tenant", "a").Error().Msg("payment failed")
+	logger.Error().Str("tenant", "b").Msg("payment failed")
+	logger.Error().Fields(onelog.Fields{"tenant": "b"}).Msg("payment failed")
+	logger.Error().Str("tenant", "c").Msg("payment failed")
+
+	assert.Len(t, testutil.ParseRecords(t, buff.String()), 3, "every tenant should have its own bucket")
+	assert.EqualValues(t, 2, logger.Suppressed())
+}
+
+// TestLoggerReport tests if suppressed records are reported per key at their highest level.
+func TestLoggerReport(t *testing.T) {
+	t.Parallel()
+
+	buff := new(testutil.SyncBuffer)
+	logger, _ := newTestLogger(buff, 1, WithKeyFunc(func(onelog.Level, string, onelog.Fields) string {
+		return "all"
+	}))
+	defer logger.Close()
+
+	tenantLogger := logger.With("tenant", "a")
+	tenantLogger.Info().Msg("first")
+	tenantLogger.Info().Msg("second")
+	tenantLogger.Warn().Msg("third")
+	tenantLogger.Info().Msg("fourth")
+
+	logger.Flush()
+
+	records := testutil.ParseRecords(t, buff.String())
+	require.Len(t, records, 2)
+	report := records[1]
+	assert.Equal(t, "3 records suppressed", report["message"])
+	assert.Equal(t, "warn", report["level"], "the report should use the highest suppressed level")
+	assert.EqualValues(t, 3, report[SuppressedKey])
+	assert.Equal(t, "all", report[LimitKeyKey])
+	assert.Equal(t, "a", report["tenant"], "the report should carry the fields added using With")
+
+	logger.Flush()
+	assert.Len(t, testutil.ParseRecords(t, buff.String()), 2, "suppressed records should only be reported once")
+}
+
+// TestLoggerPeriodicReport tests if suppression reports are written periodically and on close.
+func TestLoggerPeriodicReport(t *testing.T) {
+	t.Parallel()
+
+	buff := new(testutil.SyncBuffer)
+	logger, _ := newTestLogger(buff, 1, WithReportInterval(10*time.Millisecond))
+
+	logger.Info().Msg("test")
+	logger.Info().Msg("test")
+
+	assert.Eventually(t, func() bool {
+		return strings.Contains(buff.String(), "1 records suppressed")
+	}, time.Second, 5*time.Millisecond, "suppressed records should be reported periodically")
+
+	logger.Info().Msg("test")
+	logger.Close()
+	logger.Close() // Closing twice should be harmless
+
+	assert.Equal(t, 2, strings.Count(buff.String(), "1 records suppressed"), "pending reports should be written on close")
+
+	records := testutil.ParseRecords(t, buff.String())
+	require.NotEmpty(t, records)
+	assert.Equal(t, "info:test", records[len(records)-1][LimitKeyKey], "records should be keyed by level and message")
+}
+
+// TestLoggerFatalNoExit tests if fatal records are never limited and pending reports are written before them.
+func TestLoggerFatalNoExit(t *testing.T) {
+	t.Parallel()
+
+	buff := new(testutil.SyncBuffer)
+	logger, _ := newTestLogger(buff, 1)
+	defer logger.Close()
+
+	logger.Info().Msg("test")
+	logger.Info().Msg("test")
+	logger.FatalNoExit().Msg("fatal")
+	logger.FatalNoExit().Msg("fatal")
+
+	records := testutil.ParseRecords(t, buff.String())
+	require.Len(t, records, 4)
+	assert.Equal(t, "1 records suppressed", records[1]["message"])
+	assert.Equal(t, "fatal", records[2]["message"])
+	assert.Equal(t, "fatal", records[3]["message"])
+}
+
+// TestLoggerConcurrent tests if the logger is safe for concurrent use.
+func TestLoggerConcurrent(t *testing.T) {
+	t.Parallel()
+
+	buff := new(testutil.SyncBuffer)
+	logger, _ := newTestLogger(buff, 10)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				logger.With("goroutine", j).Info().Msg("test")
+			}
+		}()
+	}
+	wg.Wait()
+	logger.Close()
+
+	records := testutil.ParseRecords(t, buff.String())
+	assert.Len(t, records, 11, "exactly the burst should be written, followed by a single report")
+	assert.EqualValues(t, 790, logger.Suppressed())
+}
@@ -0,0 +1,71 @@
+package redact
+
+import "regexp"
+
+// Detector finds sensitive values within strings.
+type Detector struct {
+	pattern  *regexp.Regexp
+	validate func(match string) bool
+}
+
+// NewDetector returns a detector for all substrings matching pattern.
+func NewDetector(pattern *regexp.Regexp) Detector {
+	return Detector{pattern: pattern}
+}
+
+var (
+	// JWT detects JSON Web Tokens.
+	JWT = NewDetector(regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`))
+
+	// Email detects email addresses.
+	Email = NewDetector(regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`))
+
+	// CardNumber detects payment card numbers (PANs) of 13 to 19 digits, optionally grouped by spaces or dashes. Only
+	// numbers that pass the Luhn check are detected.
+	CardNumber = Detector{
+		pattern:  regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+		validate: luhn,
+	}
+)
+
+// replace replaces all sensitive values in s using strategy. It returns s and false if nothing was found.
+func (d Detector) replace(s string, strategy Strategy) (string, bool) {
+	found := false
+	result := d.pattern.ReplaceAllStringFunc(s, func(match string) string {
+		if d.validate != nil && !d.validate(match) {
+			return match
+		}
+		found = true
+
+		return strategy(match)
+	})
+
+	return result, found
+}
+
+// luhn reports whether the digits in number pass the Luhn check. Non-digit characters are ignored.
+func luhn(number string) bool {
+	sum := 0
+	double := false
+	digits := 0
+
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+		digits++
+	}
+
+	return digits > 0 && sum%10 == 0
+}
@@ -0,0 +1,4 @@
+// Package redact provides a onelog.Logger wrapper that masks sensitive values, such as passwords, tokens, emails or
+// card numbers, before they reach the backend logger. Values are matched by key name patterns, by value detectors and
+// by `redact` struct tags on values logged via Any.
+package redact
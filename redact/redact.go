@@ -0,0 +1,315 @@
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/nikoksr/onelog"
+	"github.com/nikoksr/onelog/internal/record"
+)
+
+// Compile-time check that logger implements onelog.Logger, onelog.FatalWriter and onelog.Syncer
+var (
+	_ onelog.Logger      = (*logger)(nil)
+	_ onelog.FatalWriter = (*logger)(nil)
+	_ onelog.Syncer      = (*logger)(nil)
+)
+
+// tagName is the struct tag that marks fields to redact, e.g. `redact:"partial"`.
+const tagName = "redact"
+
+type (
+	// Option configures the redaction rules.
+	Option func(*redactor)
+
+	keyRule struct {
+		pattern  string
+		strategy Strategy
+	}
+
+	valueRule struct {
+		detector Detector
+		strategy Strategy
+	}
+
+	// redactor holds the redaction rules and applies them to fields.
+	redactor struct {
+		keyRules   []keyRule
+		valueRules []valueRule
+		tags       map[string]Strategy
+	}
+
+	// logger is a onelog.Logger that redacts all fields and messages before passing them on.
+	logger struct {
+		logger   onelog.Logger
+		redactor *redactor
+	}
+)
+
+// WithKeys masks the values of all fields whose key matches one of the given patterns using strategy. Patterns use
+// the syntax of path.Match, e.g. "*password*", and are matched case-insensitively. The first matching rule wins.
+func WithKeys(strategy Strategy, patterns ...string) Option {
+	return func(r *redactor) {
+		for _, pattern := range patterns {
+			r.keyRules = append(r.keyRules, keyRule{pattern: strings.ToLower(pattern), strategy: strategy})
+		}
+	}
+}
+
+// WithValues masks all values, or parts of values, found by one of the given detectors using strategy. Detectors are
+// applied to string values, including strings nested in slices, maps and raw JSON, and to messages.
+func WithValues(strategy Strategy, detectors ...Detector) Option {
+	return func(r *redactor) {
+		for _, detector := range detectors {
+			r.valueRules = append(r.valueRules, valueRule{detector: detector, strategy: strategy})
+		}
+	}
+}
+
+// WithTag registers strategy for struct fields tagged with `redact:"<name>"`. The tags "full", "partial" (keeping the
+// last four characters) and "hash" are registered by default; an empty tag value means "full".
+func WithTag(name string, strategy Strategy) Option {
+	return func(r *redactor) {
+		r.tags[name] = strategy
+	}
+}
+
+// New returns a Logger that redacts sensitive values before passing records on to l. Redaction applies to the fields
+// added via Str, Strs, Bytes, RawJSON, Any and Fields, to fields added via With and to messages. Other typed fields,
+// such as numbers and times, are passed on unchanged.
+func New(l onelog.Logger, opts ...Option) onelog.Logger {
+	r := &redactor{
+		tags: map[string]Strategy{
+			"":        Full,
+			"full":    Full,
+			"partial": Partial(4),
+			"hash":    Hash(nil),
+		},
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return &logger{
+		logger:   l,
+		redactor: r,
+	}
+}
+
+func (l *logger) newContext(newBackendContext func() onelog.LoggerContext) onelog.LoggerContext {
+	return record.NewContext(func(msg string, fields []record.Field) {
+		for i, f := range fields {
+			fields[i] = l.redactor.field(f)
+		}
+
+		record.Apply(newBackendContext(), fields).Msg(l.redactor.str(msg))
+	})
+}
+
+// With returns the logger with the given fields.
+func (l *logger) With(fields ...any) onelog.Logger {
+	redactedFields := make([]any, len(fields))
+	copy(redactedFields, fields)
+
+	for i := 0; i+1 < len(redactedFields); i += 2 {
+		key, ok := redactedFields[i].(string)
+		if !ok {
+			continue
+		}
+		f := l.redactor.field(record.Field{Kind: record.KindAny, Key: key, Value: redactedFields[i+1]})
+		redactedFields[i+1] = f.Value
+	}
+
+	return &logger{
+		logger:   l.logger.With(redactedFields...),
+		redactor: l.redactor,
+	}
+}
+
+// Sync flushes the buffered records of the wrapped logger, if it implements onelog.Syncer.
+func (l *logger) Sync() error {
+	return onelog.Sync(l.logger)
+}
+
+// Debug returns a LoggerContext for a debug log. To send the log, use the Msg or Msgf methods.
+func (l *logger) Debug() onelog.LoggerContext {
+	return l.newContext(l.logger.Debug)
+}
+
+// Info returns a LoggerContext for an info log. To send the log, use the Msg or Msgf methods.
+func (l *logger) Info() onelog.LoggerContext {
+	return l.newContext(l.logger.Info)
+}
+
+// Warn returns a LoggerContext for a warn log. To send the log, use the Msg or Msgf methods.
+func (l *logger) Warn() onelog.LoggerContext {
+	return l.newContext(l.logger.Warn)
+}
+
+// Error returns a LoggerContext for an error log. To send the log, use the Msg or Msgf methods.
+func (l *logger) Error() onelog.LoggerContext {
+	return l.newContext(l.logger.Error)
+}
+
+// Fatal returns a LoggerContext for a fatal log. To send the log, use the Msg or Msgf methods.
+func (l *logger) Fatal() onelog.LoggerContext {
+	return l.newContext(l.logger.Fatal)
+}
+
+// FatalNoExit returns a LoggerContext for a fatal log that does not exit once sent.
+func (l *logger) FatalNoExit() onelog.LoggerContext {
+	return l.newContext(func() onelog.LoggerContext {
+		ctx, _ := onelog.FatalNoExit(l.logger)
+		return ctx
+	})
+}
+
+// keyStrategy returns the strategy of the first key rule matching key, or nil.
+func (r *redactor) keyStrategy(key string) Strategy {
+	if len(r.keyRules) == 0 || key == "" {
+		return nil
+	}
+
+	key = strings.ToLower(key)
+	for _, rule := range r.keyRules {
+		if ok, _ := path.Match(rule.pattern, key); ok {
+			return rule.strategy
+		}
+	}
+
+	return nil
+}
+
+// str applies all value rules to s.
+func (r *redactor) str(s string) string {
+	for _, rule := range r.valueRules {
+		s, _ = rule.detector.replace(s, rule.strategy)
+	}
+
+	return s
+}
+
+// field returns f with all rules applied.
+func (r *redactor) field(f record.Field) record.Field {
+	switch f.Kind {
+	case record.KindStr, record.KindStrs, record.KindBytes, record.KindRawJSON, record.KindAny, record.KindFields:
+	default:
+		return f
+	}
+
+	if strategy := r.keyStrategy(f.Key); strategy != nil {
+		if values, ok := f.Value.([]string); ok {
+			masked := make([]string, len(values))
+			for i, v := range values {
+				masked[i] = strategy(v)
+			}
+
+			return record.Field{Kind: record.KindStrs, Key: f.Key, Value: masked}
+		}
+
+		return record.Field{Kind: record.KindStr, Key: f.Key, Value: strategy(stringify(f.Value))}
+	}
+
+	switch f.Kind {
+	case record.KindBytes:
+		value, _ := f.Value.([]byte)
+		f.Value = []byte(r.str(string(value)))
+	case record.KindRawJSON:
+		value, _ := f.Value.([]byte)
+		masked, ok := r.rawJSON(value)
+		if !ok {
+			// Invalid JSON; log the masked text as a string, so that masking cannot produce broken output
+			return record.Field{Kind: record.KindStr, Key: f.Key, Value: r.str(string(value))}
+		}
+		f.Value = masked
+	default:
+		f.Value = r.value(f.Value)
+	}
+
+	return f
+}
+
+// rawJSON applies all rules to the keys and values of a JSON document. It returns false if data is not valid JSON.
+func (r *redactor) rawJSON(data []byte) ([]byte, bool) {
+	// Decode numbers as json.Number, so that they are re-encoded verbatim instead of losing precision as float64
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var decoded any
+	if err := decoder.Decode(&decoded); err != nil || decoder.More() {
+		return nil, false
+	}
+
+	encoded, err := json.Marshal(r.value(decoded))
+	if err != nil {
+		return nil, false
+	}
+
+	return encoded, true
+}
+
+// value applies all rules to an arbitrary value. Maps and slices are copied, never modified in place.
+func (r *redactor) value(v any) any {
+	switch value := v.(type) {
+	case nil:
+		return nil
+	case string:
+		return r.str(value)
+	case []string:
+		masked := make([]string, len(value))
+		for i, s := range value {
+			masked[i] = r.str(s)
+		}
+		return masked
+	case []any:
+		masked := make([]any, len(value))
+		for i, elem := range value {
+			masked[i] = r.value(elem)
+		}
+		return masked
+	case map[string]any:
+		return r.fields(value)
+	case map[string]string:
+		masked := make(map[string]string, len(value))
+		for key, s := range value {
+			if strategy := r.keyStrategy(key); strategy != nil {
+				masked[key] = strategy(s)
+			} else {
+				masked[key] = r.str(s)
+			}
+		}
+		return masked
+	default:
+		return r.structValue(v)
+	}
+}
+
+// fields applies all rules to the keys and values of a map.
+func (r *redactor) fields(fields map[string]any) map[string]any {
+	masked := make(map[string]any, len(fields))
+	for key, v := range fields {
+		if strategy := r.keyStrategy(key); strategy != nil {
+			masked[key] = strategy(stringify(v))
+		} else {
+			masked[key] = r.value(v)
+		}
+	}
+
+	return masked
+}
+
+// stringify returns the string representation of a value that is about to be masked.
+func stringify(v any) string {
+	switch value := v.(type) {
+	case string:
+		return value
+	case []byte:
+		return string(value)
+	default:
+		return fmt.Sprint(value)
+	}
+}
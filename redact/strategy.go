@@ -0,0 +1,53 @@
+package redact
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"unicode/utf8"
+)
+
+// redacted is the replacement used by the Full strategy.
+const redacted = "[REDACTED]"
+
+// Strategy masks a sensitive value.
+type Strategy func(value string) string
+
+// Full replaces the whole value with a fixed placeholder.
+func Full(_ string) string {
+	return redacted
+}
+
+// Partial returns a strategy that keeps the last visible characters of a value and replaces all others with '*'.
+// Values that are not longer than visible characters are masked entirely.
+func Partial(visible int) Strategy {
+	return func(value string) string {
+		length := utf8.RuneCountInString(value)
+		if length <= visible {
+			return strings.Repeat("*", length)
+		}
+
+		runes := []rune(value)
+
+		return strings.Repeat("*", length-visible) + string(runes[length-visible:])
+	}
+}
+
+// Hash returns a strategy that replaces a value with a truncated SHA-256 hash of it, so that equal values can still be
+// correlated. If key is set, an HMAC is used instead, which prevents guessing low-entropy values such as emails.
+func Hash(key []byte) Strategy {
+	return func(value string) string {
+		var sum []byte
+		if len(key) > 0 {
+			mac := hmac.New(sha256.New, key)
+			_, _ = mac.Write([]byte(value))
+			sum = mac.Sum(nil)
+		} else {
+			digest := sha256.Sum256([]byte(value))
+			sum = digest[:]
+		}
+
+		return "sha256:" + hex.EncodeToString(sum[:8])
+	}
+}
@@ -0,0 +1,182 @@
+package redact
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// taggedTypes caches whether a type contains fields with a redact tag.
+var taggedTypes sync.Map // map[reflect.Type]bool
+
+// structValue redacts structs, pointers to structs and slices of structs that contain fields with a redact tag. They are
+// converted to maps keyed by their JSON field names. All other values are returned unchanged, which preserves their
+// encoding by the backend logger.
+func (r *redactor) structValue(v any) any {
+	rv := reflect.ValueOf(v)
+	if !hasTags(rv.Type()) {
+		return v
+	}
+
+	return r.reflectValue(rv)
+}
+
+// reflectValue applies all rules to a value whose type contains redact tags.
+func (r *redactor) reflectValue(rv reflect.Value) any {
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Struct:
+		if !hasTags(rv.Type()) {
+			return rv.Interface()
+		}
+
+		fields := make(map[string]any, rv.NumField())
+		r.structFields(rv, fields)
+
+		return fields
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return nil
+		}
+
+		values := make([]any, rv.Len())
+		for i := range values {
+			values[i] = r.reflectValue(rv.Index(i))
+		}
+
+		return values
+	default:
+		if !rv.CanInterface() {
+			return nil
+		}
+
+		return r.value(rv.Interface())
+	}
+}
+
+// structFields adds the redacted fields of the struct rv to fields. Embedded structs without a JSON name are flattened,
+// like encoding/json does.
+func (r *redactor) structFields(rv reflect.Value, fields map[string]any) {
+	t := rv.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		name, omitEmpty, ok := jsonName(sf)
+		if !ok {
+			continue
+		}
+
+		fv := rv.Field(i)
+		if omitEmpty && fv.IsZero() {
+			continue
+		}
+
+		if sf.Anonymous && !hasJSONName(sf) {
+			embedded := fv
+			for embedded.Kind() == reflect.Pointer && !embedded.IsNil() {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				r.structFields(embedded, fields)
+				continue
+			}
+		}
+
+		strategy := r.keyStrategy(name)
+		if tag, tagged := sf.Tag.Lookup(tagName); tagged {
+			strategy = r.tags[tag]
+			if strategy == nil {
+				strategy = Full
+			}
+		}
+
+		if strategy == nil {
+			fields[name] = r.reflectValue(fv)
+			continue
+		}
+
+		for fv.Kind() == reflect.Pointer || fv.Kind() == reflect.Interface {
+			if fv.IsNil() {
+				break
+			}
+			fv = fv.Elem()
+		}
+
+		if (fv.Kind() == reflect.Pointer || fv.Kind() == reflect.Interface) && fv.IsNil() {
+			fields[name] = nil
+		} else {
+			fields[name] = strategy(stringify(fv.Interface()))
+		}
+	}
+}
+
+// jsonName returns the JSON name of a struct field, whether it is tagged omitempty and whether it gets encoded at all.
+func jsonName(sf reflect.StructField) (string, bool, bool) {
+	tag := sf.Tag.Get("json")
+	if tag == "-" {
+		return "", false, false
+	}
+
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = sf.Name
+	}
+
+	return name, strings.Contains(opts, "omitempty"), true
+}
+
+// hasJSONName reports whether a struct field has an explicit JSON name.
+func hasJSONName(sf reflect.StructField) bool {
+	name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+
+	return name != ""
+}
+
+// hasTags reports whether t, or any type it is composed of, contains struct fields with a redact tag.
+func hasTags(t reflect.Type) bool {
+	if tagged, ok := taggedTypes.Load(t); ok {
+		return tagged.(bool)
+	}
+
+	tagged := typeHasTags(t, make(map[reflect.Type]bool))
+	taggedTypes.Store(t, tagged)
+
+	return tagged
+}
+
+func typeHasTags(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return false // Recursive type; the tags, if any, are found on the first visit
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array:
+		return typeHasTags(t.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if !sf.IsExported() {
+				continue
+			}
+			if _, ok := sf.Tag.Lookup(tagName); ok {
+				return true
+			}
+			if typeHasTags(sf.Type, seen) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,444 @@
+package sampling
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+	"net/netip"
+	"net/url"
+	"time"
+
+	"github.com/nikoksr/onelog"
+)
+
+// Compile-time check that sampledContext and sampledTemplate implement onelog.LoggerContext and onelog.Template
+var (
+	_ onelog.LoggerContext = (*sampledContext)(nil)
+	_ onelog.Template      = (*sampledTemplate)(nil)
+)
+
+type (
+	// sampledContext is the LoggerContext of records whose sampling decision depends on their message. Fields are added
+	// to the context of the wrapped logger right away, without being recorded; once the message is known, the record
+	// is either sent or discarded.
+	sampledContext struct {
+		ctx    onelog.LoggerContext
+		logger *Logger
+		level  onelog.Level
+	}
+
+	// sampledTemplate is the Template of a sampledContext.
+	sampledTemplate struct {
+		tmpl   onelog.Template
+		logger *Logger
+		level  onelog.Level
+	}
+)
+
+// Bytes adds the field key with val as a []byte to the logger context.
+func (c *sampledContext) Bytes(key string, value []byte) onelog.LoggerContext {
+	c.ctx = c.ctx.Bytes(key, value)
+
+	return c
+}
+
+// Hex adds the field key with val as a hex string to the logger context.
+func (c *sampledContext) Hex(key string, value []byte) onelog.LoggerContext {
+	c.ctx = c.ctx.Hex(key, value)
+
+	return c
+}
+
+// RawJSON adds the field key with val as a json.RawMessage to the logger context.
+func (c *sampledContext) RawJSON(key string, value []byte) onelog.LoggerContext {
+	c.ctx = c.ctx.RawJSON(key, value)
+
+	return c
+}
+
+// Str adds the field key with val as a string to the logger context.
+func (c *sampledContext) Str(key, value string) onelog.LoggerContext {
+	c.ctx = c.ctx.Str(key, value)
+
+	return c
+}
+
+// Strs adds the field key with val as a []string to the logger context.
+func (c *sampledContext) Strs(key string, value []string) onelog.LoggerContext {
+	c.ctx = c.ctx.Strs(key, value)
+
+	return c
+}
+
+// Stringer adds the field key with val as a fmt.Stringer to the logger context.
+func (c *sampledContext) Stringer(key string, val fmt.Stringer) onelog.LoggerContext {
+	c.ctx = c.ctx.Stringer(key, val)
+
+	return c
+}
+
+// Stringers adds the field key with val as a []fmt.Stringer to the logger context.
+func (c *sampledContext) Stringers(key string, vals []fmt.Stringer) onelog.LoggerContext {
+	c.ctx = c.ctx.Stringers(key, vals)
+
+	return c
+}
+
+// Int adds the field key with val as an int to the logger context.
+func (c *sampledContext) Int(key string, value int) onelog.LoggerContext {
+	c.ctx = c.ctx.Int(key, value)
+
+	return c
+}
+
+// Ints adds the field key with val as a []int to the logger context.
+func (c *sampledContext) Ints(key string, value []int) onelog.LoggerContext {
+	c.ctx = c.ctx.Ints(key, value)
+
+	return c
+}
+
+// Int8 adds the field key with val as an int8 to the logger context.
+func (c *sampledContext) Int8(key string, value int8) onelog.LoggerContext {
+	c.ctx = c.ctx.Int8(key, value)
+
+	return c
+}
+
+// Ints8 adds the field key with val as a []int8 to the logger context.
+func (c *sampledContext) Ints8(key string, value []int8) onelog.LoggerContext {
+	c.ctx = c.ctx.Ints8(key, value)
+
+	return c
+}
+
+// Int16 adds the field key with val as an int16 to the logger context.
+func (c *sampledContext) Int16(key string, value int16) onelog.LoggerContext {
+	c.ctx = c.ctx.Int16(key, value)
+
+	return c
+}
+
+// Ints16 adds the field key with val as a []int16 to the logger context.
+func (c *sampledContext) Ints16(key string, value []int16) onelog.LoggerContext {
+	c.ctx = c.ctx.Ints16(key, value)
+
+	return c
+}
+
+// Int32 adds the field key with val as an int32 to the logger context.
+func (c *sampledContext) Int32(key string, value int32) onelog.LoggerContext {
+	c.ctx = c.ctx.Int32(key, value)
+
+	return c
+}
+
+// Ints32 adds the field key with val as a []int32 to the logger context.
+func (c *sampledContext) Ints32(key string, value []int32) onelog.LoggerContext {
+	c.ctx = c.ctx.Ints32(key, value)
+
+	return c
+}
+
+// Int64 adds the field key with val as an int64 to the logger context.
+func (c *sampledContext) Int64(key string, value int64) onelog.LoggerContext {
+	c.ctx = c.ctx.Int64(key, value)
+
+	return c
+}
+
+// Ints64 adds the field key with val as a []int64 to the logger context.
+func (c *sampledContext) Ints64(key string, value []int64) onelog.LoggerContext {
+	c.ctx = c.ctx.Ints64(key, value)
+
+	return c
+}
+
+// Uint adds the field key with val as a uint to the logger context.
+func (c *sampledContext) Uint(key string, value uint) onelog.LoggerContext {
+	c.ctx = c.ctx.Uint(key, value)
+
+	return c
+}
+
+// Uints adds the field key with val as a []uint to the logger context.
+func (c *sampledContext) Uints(key string, value []uint) onelog.LoggerContext {
+	c.ctx = c.ctx.Uints(key, value)
+
+	return c
+}
+
+// Uint8 adds the field key with val as a uint8 to the logger context.
+func (c *sampledContext) Uint8(key string, value uint8) onelog.LoggerContext {
+	c.ctx = c.ctx.Uint8(key, value)
+
+	return c
+}
+
+// Uints8 adds the field key with val as a []uint8 to the logger context.
+func (c *sampledContext) Uints8(key string, value []uint8) onelog.LoggerContext {
+	c.ctx = c.ctx.Uints8(key, value)
+
+	return c
+}
+
+// Uint16 adds the field key with val as a uint16 to the logger context.
+func (c *sampledContext) Uint16(key string, value uint16) onelog.LoggerContext {
+	c.ctx = c.ctx.Uint16(key, value)
+
+	return c
+}
+
+// Uints16 adds the field key with val as a []uint16 to the logger context.
+func (c *sampledContext) Uints16(key string, value []uint16) onelog.LoggerContext {
+	c.ctx = c.ctx.Uints16(key, value)
+
+	return c
+}
+
+// Uint32 adds the field key with val as a uint32 to the logger context.
+func (c *sampledContext) Uint32(key string, value uint32) onelog.LoggerContext {
+	c.ctx = c.ctx.Uint32(key, value)
+
+	return c
+}
+
+// Uints32 adds the field key with val as a []uint32 to the logger context.
+func (c *sampledContext) Uints32(key string, value []uint32) onelog.LoggerContext {
+	c.ctx = c.ctx.Uints32(key, value)
+
+	return c
+}
+
+// Uint64 adds the field key with val as a uint64 to the logger context.
+func (c *sampledContext) Uint64(key string, value uint64) onelog.LoggerContext {
+	c.ctx = c.ctx.Uint64(key, value)
+
+	return c
+}
+
+// Uints64 adds the field key with val as a []uint64 to the logger context.
+func (c *sampledContext) Uints64(key string, value []uint64) onelog.LoggerContext {
+	c.ctx = c.ctx.Uints64(key, value)
+
+	return c
+}
+
+// Float32 adds the field key with val as a float32 to the logger context.
+func (c *sampledContext) Float32(key string, value float32) onelog.LoggerContext {
+	c.ctx = c.ctx.Float32(key, value)
+
+	return c
+}
+
+// Floats32 adds the field key with val as a []float32 to the logger context.
+func (c *sampledContext) Floats32(key string, value []float32) onelog.LoggerContext {
+	c.ctx = c.ctx.Floats32(key, value)
+
+	return c
+}
+
+// Float64 adds the field key with val as a float64 to the logger context.
+func (c *sampledContext) Float64(key string, value float64) onelog.LoggerContext {
+	c.ctx = c.ctx.Float64(key, value)
+
+	return c
+}
+
+// Floats64 adds the field key with val as a []float64 to the logger context.
+func (c *sampledContext) Floats64(key string, value []float64) onelog.LoggerContext {
+	c.ctx = c.ctx.Floats64(key, value)
+
+	return c
+}
+
+// Bool adds the field key with val as a bool to the logger context.
+func (c *sampledContext) Bool(key string, value bool) onelog.LoggerContext {
+	c.ctx = c.ctx.Bool(key, value)
+
+	return c
+}
+
+// Bools adds the field key with val as a []bool to the logger context.
+func (c *sampledContext) Bools(key string, value []bool) onelog.LoggerContext {
+	c.ctx = c.ctx.Bools(key, value)
+
+	return c
+}
+
+// Time adds the field key with val as a time.Time to the logger context.
+func (c *sampledContext) Time(key string, value time.Time) onelog.LoggerContext {
+	c.ctx = c.ctx.Time(key, value)
+
+	return c
+}
+
+// Times adds the field key with val as a []time.Time to the logger context.
+func (c *sampledContext) Times(key string, value []time.Time) onelog.LoggerContext {
+	c.ctx = c.ctx.Times(key, value)
+
+	return c
+}
+
+// Dur adds the field key with val as a time.Duration to the logger context.
+func (c *sampledContext) Dur(key string, value time.Duration) onelog.LoggerContext {
+	c.ctx = c.ctx.Dur(key, value)
+
+	return c
+}
+
+// Durs adds the field key with val as a []time.Duration to the logger context.
+func (c *sampledContext) Durs(key string, value []time.Duration) onelog.LoggerContext {
+	c.ctx = c.ctx.Durs(key, value)
+
+	return c
+}
+
+// TimeDiff adds the field key with begin and end as a time.Time to the logger context.
+func (c *sampledContext) TimeDiff(key string, t, start time.Time) onelog.LoggerContext {
+	c.ctx = c.ctx.TimeDiff(key, t, start)
+
+	return c
+}
+
+// IPAddr adds the field key with val as a net.IP to the logger context.
+func (c *sampledContext) IPAddr(key string, value net.IP) onelog.LoggerContext {
+	c.ctx = c.ctx.IPAddr(key, value)
+
+	return c
+}
+
+// IPPrefix adds the field key with val as a net.IPNet to the logger context.
+func (c *sampledContext) IPPrefix(key string, value net.IPNet) onelog.LoggerContext {
+	c.ctx = c.ctx.IPPrefix(key, value)
+
+	return c
+}
+
+// MACAddr adds the field key with val as a net.HardwareAddr to the logger context.
+func (c *sampledContext) MACAddr(key string, value net.HardwareAddr) onelog.LoggerContext {
+	c.ctx = c.ctx.MACAddr(key, value)
+
+	return c
+}
+
+// Addr adds the field key with val as a netip.Addr to the logger context.
+func (c *sampledContext) Addr(key string, value netip.Addr) onelog.LoggerContext {
+	c.ctx = c.ctx.Addr(key, value)
+
+	return c
+}
+
+// Prefix adds the field key with val as a netip.Prefix to the logger context.
+func (c *sampledContext) Prefix(key string, value netip.Prefix) onelog.LoggerContext {
+	c.ctx = c.ctx.Prefix(key, value)
+
+	return c
+}
+
+// AddrPort adds the field key with val as a netip.AddrPort to the logger context.
+func (c *sampledContext) AddrPort(key string, value netip.AddrPort) onelog.LoggerContext {
+	c.ctx = c.ctx.AddrPort(key, value)
+
+	return c
+}
+
+// URL adds the field key with val as a *url.URL to the logger context.
+func (c *sampledContext) URL(key string, value *url.URL) onelog.LoggerContext {
+	c.ctx = c.ctx.URL(key, value)
+
+	return c
+}
+
+// BigInt adds the field key with val as a *big.Int to the logger context.
+func (c *sampledContext) BigInt(key string, value *big.Int) onelog.LoggerContext {
+	c.ctx = c.ctx.BigInt(key, value)
+
+	return c
+}
+
+// BigFloat adds the field key with val as a *big.Float to the logger context.
+func (c *sampledContext) BigFloat(key string, value *big.Float) onelog.LoggerContext {
+	c.ctx = c.ctx.BigFloat(key, value)
+
+	return c
+}
+
+// Decimal adds the field key with val as a decimal number to the logger context.
+func (c *sampledContext) Decimal(key string, value fmt.Stringer) onelog.LoggerContext {
+	c.ctx = c.ctx.Decimal(key, value)
+
+	return c
+}
+
+// Err adds the field "error" with val as an error to the logger context.
+func (c *sampledContext) Err(err error) onelog.LoggerContext {
+	c.ctx = c.ctx.Err(err)
+
+	return c
+}
+
+// Errs adds the field key with val as a []error to the logger context.
+func (c *sampledContext) Errs(key string, errs []error) onelog.LoggerContext {
+	c.ctx = c.ctx.Errs(key, errs)
+
+	return c
+}
+
+// AnErr adds the field key with val as an error to the logger context.
+func (c *sampledContext) AnErr(key string, err error) onelog.LoggerContext {
+	c.ctx = c.ctx.AnErr(key, err)
+
+	return c
+}
+
+// Any adds the field key with val as an arbitrary value to the logger context.
+func (c *sampledContext) Any(key string, value any) onelog.LoggerContext {
+	c.ctx = c.ctx.Any(key, value)
+
+	return c
+}
+
+// Fields adds the fields to the logger context.
+func (c *sampledContext) Fields(fields onelog.Fields) onelog.LoggerContext {
+	c.ctx = c.ctx.Fields(fields)
+
+	return c
+}
+
+// Msg sends the record with msg to the wrapped logger if the sampler accepts it, and discards it otherwise.
+func (c *sampledContext) Msg(msg string) {
+	if !c.logger.sampler.Sample(c.level, msg) {
+		c.logger.stats.inc(c.level)
+		c.ctx.Discard()
+
+		return
+	}
+
+	c.ctx.Msg(msg)
+}
+
+// Msgf sends the record with formatted msg to the wrapped logger if the sampler accepts it.
+func (c *sampledContext) Msgf(format string, v ...any) {
+	c.Msg(fmt.Sprintf(format, v...))
+}
+
+// Send sends the record without a message to the wrapped logger if the sampler accepts it.
+func (c *sampledContext) Send() {
+	c.Msg("")
+}
+
+// Discard abandons the record without writing it.
+func (c *sampledContext) Discard() {
+	c.ctx.Discard()
+}
+
+// Template returns a snapshot of the context of the wrapped logger. Records created from it are sampled too.
+func (c *sampledContext) Template() onelog.Template {
+	return &sampledTemplate{tmpl: c.ctx.Template(), logger: c.logger, level: c.level}
+}
+
+// Context returns a new sampled context holding the fields of the template.
+func (t *sampledTemplate) Context() onelog.LoggerContext {
+	return &sampledContext{ctx: t.tmpl.Context(), logger: t.logger, level: t.level}
+}
@@ -0,0 +1,4 @@
+// Package sampling provides a onelog.Logger wrapper that samples records before passing them on, which keeps hot
+// paths from flooding the backend. It ships zap-style tick sampling keyed by level and message, as well as random and
+// burst sampling.
+package sampling
@@ -0,0 +1,133 @@
+package sampling
+
+import (
+	"sync/atomic"
+
+	"github.com/nikoksr/onelog"
+	nopadapter "github.com/nikoksr/onelog/adapter/nop"
+)
+
+// Compile-time check that Logger implements onelog.Logger, onelog.FatalWriter and onelog.Syncer
+var (
+	_ onelog.Logger      = (*Logger)(nil)
+	_ onelog.FatalWriter = (*Logger)(nil)
+	_ onelog.Syncer      = (*Logger)(nil)
+)
+
+type (
+	// Logger is a onelog.Logger that samples records before passing them on to another logger. Fatal records are never
+	// sampled.
+	//
+	// If the sampler is a LevelSampler, rejected records are dropped as soon as their LoggerContext is created.
+	// Otherwise, the decision depends on the message, so fields are added to the wrapped logger right away and the
+	// record is discarded if the sampler rejects it once its message is known.
+	Logger struct {
+		logger  onelog.Logger
+		sampler Sampler
+		stats   *Stats
+	}
+
+	// Stats counts the records dropped by a sampling Logger and all loggers derived from it using With. It is safe for
+	// concurrent use.
+	Stats struct {
+		dropped [numLevels]atomic.Uint64
+	}
+)
+
+// New returns a Logger that writes the records of l that are accepted by sampler.
+func New(l onelog.Logger, sampler Sampler) *Logger {
+	return &Logger{
+		logger:  l,
+		sampler: sampler,
+		stats:   new(Stats),
+	}
+}
+
+// Stats returns the counters of dropped records.
+func (l *Logger) Stats() *Stats {
+	return l.stats
+}
+
+func (l *Logger) newContext(level onelog.Level) onelog.LoggerContext {
+	if levelSampler, ok := l.sampler.(LevelSampler); ok {
+		if !levelSampler.SampleLevel(level) {
+			l.stats.inc(level)
+			return nopadapter.Context{}
+		}
+
+		return onelog.AtLevel(l.logger, level)
+	}
+
+	return &sampledContext{ctx: onelog.AtLevel(l.logger, level), logger: l, level: level}
+}
+
+// With returns the logger with the given fields.
+func (l *Logger) With(fields ...any) onelog.Logger {
+	return &Logger{
+		logger:  l.logger.With(fields...),
+		sampler: l.sampler,
+		stats:   l.stats,
+	}
+}
+
+// Sync flushes the buffered records of the wrapped logger, if it implements onelog.Syncer.
+func (l *Logger) Sync() error {
+	return onelog.Sync(l.logger)
+}
+
+// Debug returns a LoggerContext for a debug log. To send the log, use the Msg or Msgf methods.
+func (l *Logger) Debug() onelog.LoggerContext {
+	return l.newContext(onelog.DebugLevel)
+}
+
+// Info returns a LoggerContext for an info log. To send the log, use the Msg or Msgf methods.
+func (l *Logger) Info() onelog.LoggerContext {
+	return l.newContext(onelog.InfoLevel)
+}
+
+// Warn returns a LoggerContext for a warn log. To send the log, use the Msg or Msgf methods.
+func (l *Logger) Warn() onelog.LoggerContext {
+	return l.newContext(onelog.WarnLevel)
+}
+
+// Error returns a LoggerContext for an error log. To send the log, use the Msg or Msgf methods.
+func (l *Logger) Error() onelog.LoggerContext {
+	return l.newContext(onelog.ErrorLevel)
+}
+
+// Fatal returns a LoggerContext for a fatal log. Fatal records are never sampled.
+func (l *Logger) Fatal() onelog.LoggerContext {
+	return l.logger.Fatal()
+}
+
+// FatalNoExit returns a LoggerContext for a fatal log that does not exit once sent.
+func (l *Logger) FatalNoExit() onelog.LoggerContext {
+	ctx, _ := onelog.FatalNoExit(l.logger)
+
+	return ctx
+}
+
+func (s *Stats) inc(level onelog.Level) {
+	if level >= onelog.DebugLevel && int(level) < numLevels {
+		s.dropped[level].Add(1)
+	}
+}
+
+// Dropped returns the total number of dropped records.
+func (s *Stats) Dropped() uint64 {
+	var total uint64
+	for i := range s.dropped {
+		total += s.dropped[i].Load()
+	}
+
+	return total
+}
+
+// DroppedLevel returns the number of dropped records at the given level.
+func (s *Stats) DroppedLevel(level onelog.Level) uint64 {
+	if level < onelog.DebugLevel || int(level) >= numLevels {
+		return 0
+	}
+
+	return s.dropped[level].Load()
+}
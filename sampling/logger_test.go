@@ -0,0 +1,107 @@
+package sampling
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nikoksr/onelog"
+	nopadapter "github.com/nikoksr/onelog/adapter/nop"
+	"github.com/nikoksr/onelog/internal/race"
+	"github.com/nikoksr/onelog/internal/testutil"
+)
+
+func countLines(buff *bytes.Buffer) int {
+	return strings.Count(buff.String(), "\n")
+}
+
+// TestLoggerTickSampling tests if records are sampled by message and fields are only written for sampled records.
+func TestLoggerTickSampling(t *testing.T) {
+	t.Parallel()
+
+	buff := new(bytes.Buffer)
+	logger := New(testutil.NewAdapter(buff), NewTickSampler(time.Hour, 2, 0))
+	derived := logger.With("test-with", "test")
+
+	for i := 0; i < 5; i++ {
+		derived.Info().Int("i", i).Msg("hot path")
+	}
+	derived.Info().Msg("cold path")
+
+	assert.Equal(t, 3, countLines(buff), "the first two hot path records and the cold path record should be written")
+	assert.Contains(t, buff.String(), `"i":1`, "sampled records should contain their fields")
+	assert.NotContains(t, buff.String(), `"i":2`, "dropped records should not be written")
+	assert.Contains(t, buff.String(), `"test-with":"test"`, "sampled records should contain inherited fields")
+
+	assert.EqualValues(t, 3, logger.Stats().Dropped(), "dropped records of derived loggers should be counted")
+	assert.EqualValues(t, 3, logger.Stats().DroppedLevel(onelog.InfoLevel))
+	assert.Zero(t, logger.Stats().DroppedLevel(onelog.WarnLevel))
+}
+
+// TestLoggerLevelSampling tests if level samplers drop records as soon as the context is created.
+func TestLoggerLevelSampling(t *testing.T) {
+	t.Parallel()
+
+	buff := new(bytes.Buffer)
+	logger := New(testutil.NewAdapter(buff), NewRandomSampler(0))
+
+	assert.IsType(t, nopadapter.Context{}, logger.Info(), "rejected records should get a no-op context")
+	logger.Warn().Str("Test", "Value").Msg("dropped")
+
+	assert.Zero(t, buff.Len(), "no record should be written")
+	assert.EqualValues(t, 2, logger.Stats().Dropped())
+}
+
+// TestLoggerFatalNotSampled tests if fatal records bypass the sampler.
+func TestLoggerFatalNotSampled(t *testing.T) {
+	t.Parallel()
+
+	buff := new(bytes.Buffer)
+	logger := New(testutil.NewAdapter(buff), NewRandomSampler(0))
+
+	logger.FatalNoExit().Msg("fatal")
+
+	assert.Equal(t, 1, countLines(buff), "fatal records should always be written")
+	assert.Zero(t, logger.Stats().Dropped())
+}
+
+// TestLoggerTickSamplingTemplate tests if records created from templates are sampled too.
+func TestLoggerTickSamplingTemplate(t *testing.T) {
+	t.Parallel()
+
+	buff := new(bytes.Buffer)
+	logger := New(testutil.NewAdapter(buff), NewTickSampler(time.Hour, 1, 0))
+
+	tmpl := logger.Info().Str("Test", "Value").Template()
+	for i := 0; i < 3; i++ {
+		tmpl.Context().Int("i", i).Msg("template")
+	}
+
+	assert.Equal(t, 1, countLines(buff), "only the first record should be written")
+	assert.Contains(t, buff.String(), `"Test":"Value","i":0`, "sampled records should contain the template fields")
+	assert.EqualValues(t, 2, logger.Stats().Dropped())
+}
+
+// TestLoggerTickSamplingAllocs tests if records rejected by a message-based sampler are not recorded.
+//
+//nolint:paralleltest // Measures allocations.
+func TestLoggerTickSamplingAllocs(t *testing.T) {
+	if race.Enabled {
+		t.Skip("allocations are not representative under the race detector")
+	}
+
+	adapter := testutil.NewAdapter(io.Discard)
+	logger := New(adapter, NewTickSampler(time.Hour, 0, 0))
+
+	discarded := testing.AllocsPerRun(100, func() {
+		adapter.Info().Str("a", "b").Int("c", 1).Bool("d", true).Str("e", "f").Discard()
+	})
+	dropped := testing.AllocsPerRun(100, func() {
+		logger.Info().Str("a", "b").Int("c", 1).Bool("d", true).Str("e", "f").Msg("dropped")
+	})
+	assert.LessOrEqual(t, dropped, discarded+1, "only the sampled context should be allocated on top of the wrapped one")
+}
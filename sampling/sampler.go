@@ -0,0 +1,179 @@
+package sampling
+
+import (
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"github.com/nikoksr/onelog"
+)
+
+// Compile-time check that the samplers implement the sampler interfaces
+var (
+	_ Sampler      = (*TickSampler)(nil)
+	_ LevelSampler = (*RandomSampler)(nil)
+	_ LevelSampler = (*BurstSampler)(nil)
+)
+
+type (
+	// Sampler decides whether a record gets written.
+	Sampler interface {
+		// Sample reports whether a record at the given level with the given message should be written.
+		Sample(level onelog.Level, msg string) bool
+	}
+
+	// LevelSampler is a Sampler whose decision does not depend on the message. Records rejected by a LevelSampler are
+	// dropped as soon as their LoggerContext is created, before any field is added.
+	LevelSampler interface {
+		Sampler
+
+		// SampleLevel reports whether a record at the given level should be written.
+		SampleLevel(level onelog.Level) bool
+	}
+)
+
+// numBuckets is the number of counters per level a TickSampler hashes messages into.
+const numBuckets = 4096
+
+// numLevels is the number of levels a TickSampler keeps counters for.
+const numLevels = int(onelog.FatalLevel) + 1
+
+// counter counts records within the current tick.
+type counter struct {
+	resetAt atomic.Int64
+	count   atomic.Uint64
+}
+
+// incCheckReset increments the counter and returns the new count. The counter starts over if the current tick has
+// passed.
+func (c *counter) incCheckReset(now time.Time, tick time.Duration) uint64 {
+	nowNanos := now.UnixNano()
+	resetAt := c.resetAt.Load()
+	if resetAt > nowNanos {
+		return c.count.Add(1)
+	}
+
+	c.count.Store(1)
+	if !c.resetAt.CompareAndSwap(resetAt, nowNanos+tick.Nanoseconds()) {
+		// Another goroutine started the new tick already
+		return c.count.Add(1)
+	}
+
+	return 1
+}
+
+// TickSampler writes the first N records with the same level and message in each tick, and every Mth record with
+// that level and message thereafter. It works like zap's sampler: messages are hashed into a fixed number of buckets,
+// so distinct messages may occasionally share a counter.
+type TickSampler struct {
+	tick       time.Duration
+	first      uint64
+	thereafter uint64
+	counters   [numLevels][numBuckets]counter
+	now        func() time.Time
+}
+
+// NewTickSampler returns a sampler that writes the first records with the same level and message in each tick, and
+// every thereafter-th record after that. If thereafter is zero, all records after the first ones are dropped.
+func NewTickSampler(tick time.Duration, first, thereafter int) *TickSampler {
+	return &TickSampler{
+		tick:       tick,
+		first:      uint64(first),
+		thereafter: uint64(thereafter),
+		now:        time.Now,
+	}
+}
+
+// Sample reports whether a record at the given level with the given message should be written.
+func (s *TickSampler) Sample(level onelog.Level, msg string) bool {
+	if level < onelog.DebugLevel || int(level) >= numLevels {
+		return true
+	}
+
+	c := &s.counters[level][hashMessage(msg)%numBuckets]
+
+	n := c.incCheckReset(s.now(), s.tick)
+	if n <= s.first {
+		return true
+	}
+
+	return s.thereafter > 0 && (n-s.first)%s.thereafter == 0
+}
+
+// hashMessage returns the 32-bit FNV-1a hash of msg. It is computed inline, since hash/fnv allocates.
+func hashMessage(msg string) uint32 {
+	const (
+		offset32 = 2166136261
+		prime32  = 16777619
+	)
+
+	hash := uint32(offset32)
+	for i := 0; i < len(msg); i++ {
+		hash ^= uint32(msg[i])
+		hash *= prime32
+	}
+
+	return hash
+}
+
+// RandomSampler writes records with a fixed probability.
+type RandomSampler struct {
+	rate float64
+}
+
+// NewRandomSampler returns a sampler that writes each record with the given probability, between 0 and 1.
+func NewRandomSampler(rate float64) *RandomSampler {
+	return &RandomSampler{rate: rate}
+}
+
+// Sample reports whether a record should be written.
+func (s *RandomSampler) Sample(level onelog.Level, _ string) bool {
+	return s.SampleLevel(level)
+}
+
+// SampleLevel reports whether a record should be written.
+func (s *RandomSampler) SampleLevel(_ onelog.Level) bool {
+	switch {
+	case s.rate <= 0:
+		return false
+	case s.rate >= 1:
+		return true
+	default:
+		return rand.Float64() < s.rate //nolint:gosec // No need for a cryptographically secure random number here
+	}
+}
+
+// BurstSampler writes up to a given number of records per period. Once the burst is used up, it delegates the
+// decision to its next sampler until the period ends.
+type BurstSampler struct {
+	burst   uint64
+	period  time.Duration
+	next    LevelSampler
+	counter counter
+	now     func() time.Time
+}
+
+// NewBurstSampler returns a sampler that writes up to burst records per period. Records exceeding the burst are
+// passed on to next; if next is nil, they are dropped.
+func NewBurstSampler(burst int, period time.Duration, next LevelSampler) *BurstSampler {
+	return &BurstSampler{
+		burst:  uint64(burst),
+		period: period,
+		next:   next,
+		now:    time.Now,
+	}
+}
+
+// Sample reports whether a record should be written.
+func (s *BurstSampler) Sample(level onelog.Level, _ string) bool {
+	return s.SampleLevel(level)
+}
+
+// SampleLevel reports whether a record should be written.
+func (s *BurstSampler) SampleLevel(level onelog.Level) bool {
+	if s.counter.incCheckReset(s.now(), s.period) <= s.burst {
+		return true
+	}
+
+	return s.next != nil && s.next.SampleLevel(level)
+}
@@ -0,0 +1,73 @@
+package sampling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nikoksr/onelog"
+)
+
+func sampleN(s Sampler, level onelog.Level, msg string, n int) []bool {
+	results := make([]bool, n)
+	for i := range results {
+		results[i] = s.Sample(level, msg)
+	}
+
+	return results
+}
+
+// TestTickSampler tests if the tick sampler writes the first N records and every Mth thereafter per level and message.
+func TestTickSampler(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	sampler := NewTickSampler(time.Second, 2, 3)
+	sampler.now = func() time.Time { return now }
+
+	expected := []bool{true, true, false, false, true, false, false, true}
+	assert.Equal(t, expected, sampleN(sampler, onelog.InfoLevel, "hot path", len(expected)))
+
+	// Other messages and levels have their own counters
+	assert.True(t, sampler.Sample(onelog.InfoLevel, "other message"))
+	assert.True(t, sampler.Sample(onelog.WarnLevel, "hot path"))
+
+	// Counters start over once the tick has passed
+	now = now.Add(time.Second)
+	assert.Equal(t, expected, sampleN(sampler, onelog.InfoLevel, "hot path", len(expected)))
+}
+
+// TestTickSamplerDropThereafter tests if all records after the first ones are dropped if thereafter is zero.
+func TestTickSamplerDropThereafter(t *testing.T) {
+	t.Parallel()
+
+	sampler := NewTickSampler(time.Hour, 1, 0)
+
+	assert.Equal(t, []bool{true, false, false}, sampleN(sampler, onelog.InfoLevel, "hot path", 3))
+}
+
+// TestRandomSampler tests the boundaries of the random sampler.
+func TestRandomSampler(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, NewRandomSampler(0).SampleLevel(onelog.InfoLevel), "a rate of 0 should drop all records")
+	assert.True(t, NewRandomSampler(1).SampleLevel(onelog.InfoLevel), "a rate of 1 should keep all records")
+}
+
+// TestBurstSampler tests if the burst sampler writes up to burst records per period and delegates afterwards.
+func TestBurstSampler(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	sampler := NewBurstSampler(2, time.Second, nil)
+	sampler.now = func() time.Time { return now }
+
+	assert.Equal(t, []bool{true, true, false}, sampleN(sampler, onelog.InfoLevel, "", 3))
+
+	now = now.Add(time.Second)
+	assert.True(t, sampler.SampleLevel(onelog.InfoLevel), "the burst should start over in the next period")
+
+	delegating := NewBurstSampler(0, time.Second, NewRandomSampler(1))
+	assert.True(t, delegating.SampleLevel(onelog.InfoLevel), "records exceeding the burst should be delegated")
+}